@@ -0,0 +1,165 @@
+// Package audit keeps the per-account log of writes made by fastmail-cli.
+// Each line of the log is one command's writes: JMAP method calls, with the
+// prior state 'fastmail undo' needs to reverse moves, keyword changes and
+// masked email state changes, and WebDAV, CalDAV and Sieve requests, which
+// cannot be undone.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+)
+
+// DirName is the name of the audit log directory inside config.StateDir().
+const DirName = "audit"
+
+// Entry is one command's writes to an account, however many requests they
+// took. ID and UndoneBy are derived when the log is
+// read: an entry's ID is its line number.
+type Entry struct {
+	ID       int                `json:"id,omitempty"`
+	Time     time.Time          `json:"time"`
+	Command  []string           `json:"command"`
+	Calls    []jmap.AuditedCall `json:"calls"`
+	Requests []Request          `json:"requests,omitempty"`
+	Undoes   int                `json:"undoes,omitempty"`
+	UndoneBy int                `json:"undoneBy,omitempty"`
+}
+
+// Request is a write made outside JMAP, such as a WebDAV upload, a CalDAV
+// event or a Sieve update. Requests are recorded but cannot be undone.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// Undoable reports whether any of the entry's changes can be reverted.
+func (e Entry) Undoable() bool {
+	for _, call := range e.Calls {
+		if call.Undoable() {
+			return true
+		}
+	}
+	return false
+}
+
+// Log is an append-only JSONL audit log.
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// New returns the log at path. The file is created on first append.
+func New(path string) *Log {
+	return &Log{path: path}
+}
+
+// ForAccount returns the log for an account inside config.StateDir().
+func ForAccount(account string) *Log {
+	return New(filepath.Join(config.StateDir(), DirName, config.SafeName(account)+".jsonl"))
+}
+
+// Path returns the log file path.
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append adds e to the end of the log.
+func (l *Log) Append(e Entry) error {
+	e.ID = 0
+	e.UndoneBy = 0
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal audit entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("create audit log dir: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	// One write per entry keeps concurrent appenders from interleaving lines.
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
+
+// Entries reads the log, oldest first. A missing log has no entries.
+func (l *Log) Entries() ([]Entry, error) {
+	f, err := os.Open(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Entry{}, nil
+		}
+		return nil, fmt.Errorf("read audit log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("parse audit log %s line %d: %w", l.path, line, err)
+		}
+		e.ID = line
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read audit log: %w", err)
+	}
+
+	byID := make(map[int]int, len(entries))
+	for i, e := range entries {
+		byID[e.ID] = i
+	}
+	for _, e := range entries {
+		if i, ok := byID[e.Undoes]; ok && e.Undoes != 0 {
+			entries[i].UndoneBy = e.ID
+		}
+	}
+	return entries, nil
+}
+
+// Find returns the entry with the given ID.
+func Find(entries []Entry, id int) (Entry, bool) {
+	for _, e := range entries {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Latest returns the most recent entry that has not been undone and is not
+// itself an undo.
+func Latest(entries []Entry) (Entry, bool) {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].UndoneBy == 0 && entries[i].Undoes == 0 {
+			return entries[i], true
+		}
+	}
+	return Entry{}, false
+}
@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+)
+
+func TestLogRoundTrip(t *testing.T) {
+	log := New(filepath.Join(t.TempDir(), DirName, "a.jsonl"))
+
+	entries, err := log.Entries()
+	if err != nil {
+		t.Fatalf("Entries on missing log: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected no entries, got %+v", entries)
+	}
+
+	move := jmap.AuditedCall{
+		Method:  "Email/set",
+		Updated: []string{"e1"},
+		Update:  map[string]map[string]any{"e1": {"mailboxIds": map[string]any{"archive": true}}},
+		Prior:   map[string]map[string]any{"e1": {"mailboxIds": map[string]any{"inbox": true}}},
+	}
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, e := range []Entry{
+		{Time: now, Command: []string{"fastmail", "email", "archive", "e1"}, Calls: []jmap.AuditedCall{move}},
+		{Time: now, Command: []string{"fastmail", "email", "send"}, Calls: []jmap.AuditedCall{{Method: "EmailSubmission/set", Created: []string{"s1"}}}},
+		{Time: now, Command: []string{"fastmail", "undo", "1"}, Calls: []jmap.AuditedCall{move}, Undoes: 1},
+	} {
+		if err := log.Append(e); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	entries, err = log.Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	for i, e := range entries {
+		if e.ID != i+1 {
+			t.Errorf("entry %d has ID %d", i, e.ID)
+		}
+	}
+	if entries[0].UndoneBy != 3 || entries[2].Undoes != 1 {
+		t.Errorf("undo links not derived: %+v", entries)
+	}
+	if !entries[0].Undoable() || entries[1].Undoable() {
+		t.Error("only the archive entry should be undoable")
+	}
+
+	latest, ok := Latest(entries)
+	if !ok || latest.ID != 2 {
+		t.Errorf("Latest = %+v, %v; want entry 2", latest, ok)
+	}
+	if _, ok := Find(entries, 4); ok {
+		t.Error("Find(4) should fail")
+	}
+
+	info, err := os.Stat(log.Path())
+	if err != nil {
+		t.Fatalf("stat log: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("log permissions = %o, want 600", perm)
+	}
+}
+
+func TestForAccount(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.StateDirEnvVarName, dir)
+
+	want := filepath.Join(dir, DirName, "me@example.com.jsonl")
+	if got := ForAccount("Me@Example.com").Path(); got != want {
+		t.Errorf("ForAccount path = %s, want %s", got, want)
+	}
+}
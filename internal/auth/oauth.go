@@ -0,0 +1,405 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
+)
+
+// DefaultOAuthIssuer is Fastmail's OAuth authorization server.
+const DefaultOAuthIssuer = "https://api.fastmail.com"
+
+// DefaultOAuthScopes requests every JMAP capability the CLI uses.
+var DefaultOAuthScopes = []string{
+	jmap.CapabilityCore,
+	jmap.CapabilityMail,
+	jmap.CapabilitySubmission,
+	jmap.CapabilityVacationResponse,
+	jmap.CapabilityContacts,
+	jmap.CapabilityCalendars,
+	jmap.CapabilityMaskedEmail,
+}
+
+// devicePollInterval is used when the server does not say how often to poll.
+var devicePollInterval = 5 * time.Second
+
+// OAuthEndpoints is the authorization server metadata (RFC 8414) the login
+// flows need.
+type OAuthEndpoints struct {
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+// OAuthToken is a token endpoint response.
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Scope        string `json:"scope"`
+}
+
+// ExpiresAt returns when the access token expires, or the zero time if the
+// server did not say.
+func (t *OAuthToken) ExpiresAt(issued time.Time) time.Time {
+	if t.ExpiresIn <= 0 {
+		return time.Time{}
+	}
+	return issued.Add(time.Duration(t.ExpiresIn) * time.Second).UTC()
+}
+
+// OAuthError is an error response from the authorization server (RFC 6749
+// section 5.2).
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("oauth error (%s): %s", e.Code, e.Description)
+	}
+	return fmt.Sprintf("oauth error (%s)", e.Code)
+}
+
+// OAuthClient runs OAuth 2.0 flows against one authorization server.
+type OAuthClient struct {
+	ClientID  string
+	Scopes    []string
+	Endpoints OAuthEndpoints
+	HTTP      *http.Client
+
+	// Out receives instructions for the user (URLs to open, device codes).
+	Out io.Writer
+	// OpenBrowser opens the loopback login page (e.g. auth.OpenBrowser); the
+	// URL is printed either way.
+	OpenBrowser func(rawURL string) error
+}
+
+// DiscoverOAuth fetches the authorization server metadata of issuer.
+func DiscoverOAuth(ctx context.Context, httpClient *http.Client, issuer string) (*OAuthEndpoints, error) {
+	metadataURL := strings.TrimSuffix(issuer, "/") + "/.well-known/oauth-authorization-server"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClientOrDefault(httpClient).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching OAuth metadata: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching OAuth metadata: %s", resp.Status)
+	}
+
+	var endpoints OAuthEndpoints
+	if err := json.NewDecoder(resp.Body).Decode(&endpoints); err != nil {
+		return nil, fmt.Errorf("decoding OAuth metadata: %w", err)
+	}
+	if endpoints.TokenEndpoint == "" {
+		return nil, fmt.Errorf("OAuth metadata has no token_endpoint")
+	}
+	return &endpoints, nil
+}
+
+// LoginPKCE runs the authorization code flow with PKCE (RFC 7636). A
+// loopback listener on 127.0.0.1 redirects the browser to the authorization
+// server and receives the code on /callback.
+func (c *OAuthClient) LoginPKCE(ctx context.Context) (*OAuthToken, error) {
+	if c.Endpoints.AuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("authorization server has no authorization_endpoint")
+	}
+
+	state, err := generateCSRFToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, challenge, err := newPKCEPair()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find available port: %w", err)
+	}
+	baseURL := fmt.Sprintf("http://127.0.0.1:%d", listener.Addr().(*net.TCPAddr).Port)
+	redirectURI := baseURL + "/callback"
+
+	authURL := c.Endpoints.AuthorizationEndpoint + "?" + url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.ClientID},
+		"redirect_uri":          {redirectURI},
+		"scope":                 {strings.Join(c.Scopes, " ")},
+		"state":                 {state},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}.Encode()
+
+	type callback struct {
+		code string
+		err  error
+	}
+	result := make(chan callback, 1)
+	deliver := func(cb callback) {
+		select {
+		case result <- cb:
+		default:
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		http.Redirect(w, r, authURL, http.StatusFound)
+	})
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("state") != state {
+			http.Error(w, "Invalid state", http.StatusBadRequest)
+			return
+		}
+
+		cb := callback{code: q.Get("code")}
+		switch {
+		case q.Get("error") != "":
+			cb.err = &OAuthError{Code: q.Get("error"), Description: q.Get("error_description")}
+		case cb.code == "":
+			cb.err = fmt.Errorf("authorization response has no code")
+		}
+		deliver(cb)
+
+		message := "Authorization complete. You can close this window and return to the terminal."
+		if cb.err != nil {
+			message = "Authorization failed. Return to the terminal for details."
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = fmt.Fprintf(w, oauthDonePageHTML, message) //nolint:errcheck // best-effort page render
+	})
+
+	server := &http.Server{
+		Handler:      mux,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != http.ErrServerClosed {
+			deliver(callback{err: err})
+		}
+	}()
+	defer func() { _ = server.Shutdown(context.Background()) }() //nolint:errcheck // best-effort shutdown
+
+	c.printf("Sign in at: %s\n", baseURL)
+	if c.OpenBrowser != nil {
+		_ = c.OpenBrowser(baseURL) //nolint:errcheck // the URL is printed above
+	}
+	c.printf("Waiting for authorization... (press Ctrl+C to cancel)\n")
+
+	var cb callback
+	select {
+	case cb = <-result:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if cb.err != nil {
+		return nil, cb.err
+	}
+
+	return c.requestToken(ctx, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {cb.code},
+		"redirect_uri":  {redirectURI},
+		"client_id":     {c.ClientID},
+		"code_verifier": {verifier},
+	})
+}
+
+// deviceAuthorization is a device authorization response (RFC 8628).
+type deviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+// LoginDevice runs the device authorization flow (RFC 8628) for machines
+// without a browser: the user enters a code on another device while the CLI
+// polls the token endpoint.
+func (c *OAuthClient) LoginDevice(ctx context.Context) (*OAuthToken, error) {
+	if c.Endpoints.DeviceAuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("authorization server does not support the device flow")
+	}
+
+	var device deviceAuthorization
+	if err := c.postForm(ctx, c.Endpoints.DeviceAuthorizationEndpoint, url.Values{
+		"client_id": {c.ClientID},
+		"scope":     {strings.Join(c.Scopes, " ")},
+	}, &device); err != nil {
+		return nil, fmt.Errorf("requesting device code: %w", err)
+	}
+	if device.DeviceCode == "" || device.UserCode == "" {
+		return nil, fmt.Errorf("device authorization response is incomplete")
+	}
+
+	c.printf("To sign in, visit %s and enter the code: %s\n", device.VerificationURI, device.UserCode)
+	if device.VerificationURIComplete != "" {
+		c.printf("Or open: %s\n", device.VerificationURIComplete)
+	}
+	c.printf("Waiting for authorization... (press Ctrl+C to cancel)\n")
+
+	interval := devicePollInterval
+	if device.Interval > 0 {
+		interval = time.Duration(device.Interval) * time.Second
+	}
+	if device.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(device.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+
+	for {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("device authorization: %w", ctx.Err())
+		case <-timer.C:
+		}
+
+		token, err := c.requestToken(ctx, url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"device_code": {device.DeviceCode},
+			"client_id":   {c.ClientID},
+		})
+		if err == nil {
+			return token, nil
+		}
+
+		var oauthErr *OAuthError
+		if !errors.As(err, &oauthErr) {
+			return nil, err
+		}
+		switch oauthErr.Code {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			return nil, err
+		}
+	}
+}
+
+// Refresh redeems a refresh token for a new access token. Servers that do not
+// rotate refresh tokens omit refresh_token; the old one is then kept.
+func (c *OAuthClient) Refresh(ctx context.Context, refreshToken string) (*OAuthToken, error) {
+	token, err := c.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {c.ClientID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
+}
+
+// requestToken posts a grant to the token endpoint.
+func (c *OAuthClient) requestToken(ctx context.Context, form url.Values) (*OAuthToken, error) {
+	var token OAuthToken
+	if err := c.postForm(ctx, c.Endpoints.TokenEndpoint, form, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access_token")
+	}
+	return &token, nil
+}
+
+// postForm posts form to endpoint and decodes a JSON success response into
+// v, or returns the server's OAuthError.
+func (c *OAuthClient) postForm(ctx context.Context, endpoint string, form url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClientOrDefault(c.HTTP).Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr OAuthError
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Code != "" {
+			return &oauthErr
+		}
+		return fmt.Errorf("%s: %s", endpoint, resp.Status)
+	}
+	return json.Unmarshal(body, v)
+}
+
+func (c *OAuthClient) printf(format string, args ...any) {
+	if c.Out != nil {
+		fmt.Fprintf(c.Out, format, args...)
+	}
+}
+
+// newPKCEPair returns a code verifier and its S256 challenge.
+func newPKCEPair() (verifier, challenge string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate PKCE verifier: %w", err)
+	}
+	verifier = base64.RawURLEncoding.EncodeToString(b)
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func httpClientOrDefault(c *http.Client) *http.Client {
+	if c != nil {
+		return c
+	}
+	return transport.NewHTTPClient(30 * time.Second)
+}
+
+const oauthDonePageHTML = `<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Fastmail CLI</title></head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, sans-serif; text-align: center; padding-top: 4rem;">
+<p>%s</p>
+</body>
+</html>
+`
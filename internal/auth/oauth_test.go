@@ -0,0 +1,230 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAuthServer is a minimal OAuth authorization server supporting the
+// authorization code (with PKCE), device code and refresh token grants.
+type fakeAuthServer struct {
+	*httptest.Server
+	t *testing.T
+
+	mu            sync.Mutex
+	challenge     string
+	redirectURI   string
+	pendingPolls  int
+	refreshTokens []string
+}
+
+func newFakeAuthServer(t *testing.T) *fakeAuthServer {
+	t.Helper()
+	s := &fakeAuthServer{t: t}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"issuer":                        s.URL,
+			"authorization_endpoint":        s.URL + "/authorize",
+			"token_endpoint":                s.URL + "/token",
+			"device_authorization_endpoint": s.URL + "/device",
+		})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("client_id") != "cli" || q.Get("code_challenge_method") != "S256" || q.Get("response_type") != "code" {
+			http.Error(w, "bad authorization request", http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.challenge = q.Get("code_challenge")
+		s.redirectURI = q.Get("redirect_uri")
+		s.mu.Unlock()
+
+		callback := q.Get("redirect_uri") + "?" + url.Values{"code": {"auth-code"}, "state": {q.Get("state")}}.Encode()
+		http.Redirect(w, r, callback, http.StatusFound)
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"device_code":      "dev-code",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": s.URL + "/activate",
+			"expires_in":       60,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+			if r.PostForm.Get("code") != "auth-code" ||
+				base64.RawURLEncoding.EncodeToString(sum[:]) != s.challenge ||
+				r.PostForm.Get("redirect_uri") != s.redirectURI {
+				writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"access_token": "access-1", "refresh_token": "refresh-1", "expires_in": 3600})
+		case "urn:ietf:params:oauth:grant-type:device_code":
+			if s.pendingPolls > 0 {
+				s.pendingPolls--
+				writeJSON(w, http.StatusBadRequest, map[string]any{"error": "authorization_pending"})
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"access_token": "access-device", "refresh_token": "refresh-device"})
+		case "refresh_token":
+			s.refreshTokens = append(s.refreshTokens, r.PostForm.Get("refresh_token"))
+			if r.PostForm.Get("refresh_token") == "revoked" {
+				writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_grant", "error_description": "token revoked"})
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"access_token": "access-2"})
+		default:
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "unsupported_grant_type"})
+		}
+	})
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeAuthServer) client(t *testing.T) *OAuthClient {
+	t.Helper()
+	endpoints, err := DiscoverOAuth(context.Background(), s.Client(), s.URL)
+	if err != nil {
+		t.Fatalf("DiscoverOAuth() error: %v", err)
+	}
+	return &OAuthClient{
+		ClientID:  "cli",
+		Scopes:    DefaultOAuthScopes,
+		Endpoints: *endpoints,
+		HTTP:      s.Client(),
+		Out:       io.Discard,
+	}
+}
+
+func TestOAuthClient_LoginPKCE(t *testing.T) {
+	server := newFakeAuthServer(t)
+	client := server.client(t)
+
+	// The "browser" follows the loopback redirect to the authorization server
+	// and back to the callback.
+	client.OpenBrowser = func(rawURL string) error {
+		go func() {
+			resp, err := http.Get(rawURL)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	token, err := client.LoginPKCE(ctx)
+	if err != nil {
+		t.Fatalf("LoginPKCE() error: %v", err)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" {
+		t.Errorf("token = %+v", token)
+	}
+	if token.ExpiresAt(time.Unix(0, 0)).Unix() != 3600 {
+		t.Errorf("ExpiresAt = %v", token.ExpiresAt(time.Unix(0, 0)))
+	}
+}
+
+func TestOAuthClient_LoginPKCERejectsWrongState(t *testing.T) {
+	server := newFakeAuthServer(t)
+	client := server.client(t)
+
+	got := make(chan int, 1)
+	client.OpenBrowser = func(rawURL string) error {
+		go func() {
+			resp, err := http.Get(rawURL + "/callback?code=stolen&state=wrong")
+			if err != nil {
+				t.Errorf("callback request: %v", err)
+				got <- 0
+				return
+			}
+			got <- resp.StatusCode
+			_ = resp.Body.Close()
+		}()
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.LoginPKCE(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("LoginPKCE() error = %v, want to keep waiting", err)
+	}
+	if status := <-got; status != http.StatusBadRequest {
+		t.Errorf("callback status = %d, want 400", status)
+	}
+}
+
+func TestOAuthClient_LoginDevice(t *testing.T) {
+	old := devicePollInterval
+	devicePollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { devicePollInterval = old })
+
+	server := newFakeAuthServer(t)
+	server.pendingPolls = 2
+	client := server.client(t)
+
+	token, err := client.LoginDevice(context.Background())
+	if err != nil {
+		t.Fatalf("LoginDevice() error: %v", err)
+	}
+	if token.AccessToken != "access-device" {
+		t.Errorf("token = %+v", token)
+	}
+	if server.pendingPolls != 0 {
+		t.Errorf("pendingPolls = %d, want all consumed", server.pendingPolls)
+	}
+}
+
+func TestOAuthClient_Refresh(t *testing.T) {
+	server := newFakeAuthServer(t)
+	client := server.client(t)
+
+	token, err := client.Refresh(context.Background(), "refresh-1")
+	if err != nil {
+		t.Fatalf("Refresh() error: %v", err)
+	}
+	if token.AccessToken != "access-2" || token.RefreshToken != "refresh-1" {
+		t.Errorf("token = %+v, want new access token and the old refresh token kept", token)
+	}
+
+	_, err = client.Refresh(context.Background(), "revoked")
+	var oauthErr *OAuthError
+	if !errors.As(err, &oauthErr) || oauthErr.Code != "invalid_grant" {
+		t.Fatalf("Refresh(revoked) error = %v, want invalid_grant", err)
+	}
+}
+
+func TestDiscoverOAuth_RequiresTokenEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"issuer": "x"})
+	}))
+	defer server.Close()
+
+	if _, err := DiscoverOAuth(context.Background(), server.Client(), server.URL); err == nil {
+		t.Fatal("DiscoverOAuth() should fail without token_endpoint")
+	}
+}
@@ -383,6 +383,12 @@ func writeJSON(w http.ResponseWriter, status int, data any) {
 	_ = json.NewEncoder(w).Encode(data) //nolint:errcheck // best-effort JSON encode
 }
 
+// OpenBrowser opens a loopback URL in the default browser, unless disabled
+// with FASTMAIL_NO_BROWSER.
+func OpenBrowser(rawURL string) error {
+	return openBrowser(rawURL)
+}
+
 func openBrowser(rawURL string) error {
 	if shouldSkipAutoBrowserOpen() {
 		return nil
@@ -0,0 +1,132 @@
+// Package cache stores JSON entries on disk so short-lived CLI invocations
+// can reuse data such as the JMAP session and mailbox list. Entries are
+// best-effort: a missing or unreadable entry is treated as a cache miss.
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+)
+
+// Store is a directory of JSON cache entries.
+type Store struct {
+	dir string
+}
+
+// New returns a store rooted at dir. The directory is created on first save.
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// ForAccount returns the store for an account inside config.CacheDir().
+func ForAccount(account string) *Store {
+	return New(filepath.Join(config.CacheDir(), config.SafeName(account)))
+}
+
+// Dir returns the store's directory.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Load decodes the entry for key into v and reports whether it was found.
+func (s *Store) Load(key string, v any) bool {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// Save writes v as the entry for key.
+func (s *Store) Save(key string, v any) error {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal cache entry: %w", err)
+	}
+
+	// Write to a temp file first so readers never see a truncated entry.
+	path := s.path(key)
+	tmp, err := os.CreateTemp(s.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the entry for key, if any.
+func (s *Store) Delete(key string) {
+	_ = os.Remove(s.path(key))
+}
+
+// Clear removes every cached entry for all accounts. Only what stores wrote
+// is removed: the *.json entries and leftover temp files in per-account
+// directories, then those directories if empty. The cache directory itself
+// and anything else in it are left alone, as FASTMAIL_CACHE_DIR may point at
+// a directory shared with other programs.
+func Clear() error {
+	root := config.CacheDir()
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("clear cache: %w", err)
+	}
+	for _, d := range dirs {
+		if !d.IsDir() || !isAccountDir(d.Name()) {
+			continue
+		}
+		dir := filepath.Join(root, d.Name())
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return fmt.Errorf("clear cache: %w", err)
+		}
+		for _, e := range entries {
+			if e.Type().IsRegular() && isEntryFile(e.Name()) {
+				if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+					return fmt.Errorf("clear cache: %w", err)
+				}
+			}
+		}
+		// Fails harmlessly if anything else is left in the directory.
+		_ = os.Remove(dir)
+	}
+	return nil
+}
+
+// isAccountDir reports whether name could be a directory ForAccount made:
+// an account email as SafeName leaves it.
+func isAccountDir(name string) bool {
+	return strings.Contains(name, "@") && config.SafeName(name) == name
+}
+
+// isEntryFile reports whether name is an entry or temp file Save writes.
+func isEntryFile(name string) bool {
+	return strings.HasSuffix(name, ".json") || (strings.HasSuffix(name, ".tmp") && strings.Contains(name, ".json."))
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, config.SafeName(key)+".json")
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+)
+
+func TestStore_SaveLoadDelete(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "acct"))
+
+	var got map[string]int
+	if s.Load("missing", &got) {
+		t.Fatal("Load() of a missing entry should report a miss")
+	}
+
+	if err := s.Save("mailboxes-u1", map[string]int{"a": 1}); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if !s.Load("mailboxes-u1", &got) || got["a"] != 1 {
+		t.Fatalf("Load() = %v, want saved entry", got)
+	}
+
+	info, err := os.Stat(filepath.Join(s.Dir(), "mailboxes-u1.json"))
+	if err != nil {
+		t.Fatalf("entry file missing: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("entry permissions = %o, want 600", perm)
+	}
+
+	s.Delete("mailboxes-u1")
+	if s.Load("mailboxes-u1", &got) {
+		t.Error("Load() after Delete() should miss")
+	}
+}
+
+func TestStore_CorruptEntryIsMiss(t *testing.T) {
+	s := New(t.TempDir())
+	if err := os.WriteFile(filepath.Join(s.Dir(), "session.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var v map[string]any
+	if s.Load("session", &v) {
+		t.Error("corrupt entry should be a miss")
+	}
+}
+
+func TestForAccountAndClear(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(config.CacheDirEnvVarName, root)
+
+	s := ForAccount("Me@Example.com")
+	if s.Dir() != filepath.Join(root, "me@example.com") {
+		t.Errorf("Dir() = %q", s.Dir())
+	}
+	if err := s.Save("session", map[string]string{"apiUrl": "x"}); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	// Files other programs keep in a shared cache directory survive.
+	foreign := []string{
+		filepath.Join(root, "other-app", "state.json"),
+		filepath.Join(root, "notes.json"),
+		filepath.Join(root, "me@example.com", "keep.txt"),
+	}
+	for _, path := range foreign {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ForAccount("you@example.com").Save("mailboxes", []string{"inbox"}); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	if err := Clear(); err != nil {
+		t.Fatalf("Clear() error: %v", err)
+	}
+	var got any
+	if s.Load("session", &got) {
+		t.Error("session entry should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(root, "you@example.com")); !os.IsNotExist(err) {
+		t.Errorf("empty account dir should be removed, stat err = %v", err)
+	}
+	for _, path := range foreign {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Clear() removed %s: %v", path, err)
+		}
+	}
+}
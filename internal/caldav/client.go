@@ -27,6 +27,7 @@ type Client struct {
 	token      string // unexported - security sensitive
 	httpClient *http.Client
 	retry      transport.RetryConfig
+	readOnly   bool
 }
 
 // String implements fmt.Stringer with redacted sensitive fields.
@@ -38,13 +39,11 @@ func (c *Client) String() string {
 // NewClient creates a new CalDAV client with the provided credentials
 func NewClient(baseURL, username, token string) *Client {
 	return &Client{
-		BaseURL:  baseURL,
-		Username: username,
-		token:    token,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		retry: transport.DefaultRetryConfig(),
+		BaseURL:    baseURL,
+		Username:   username,
+		token:      token,
+		httpClient: transport.NewHTTPClient(30 * time.Second),
+		retry:      transport.DefaultRetryConfig(),
 	}
 }
 
@@ -53,6 +52,17 @@ func (c *Client) SetRetryConfig(cfg transport.RetryConfig) {
 	c.retry = cfg
 }
 
+// SetHTTPClient sets a custom HTTP client for the CalDAV client
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+// SetReadOnly makes the client reject requests that change data, such as
+// CreateEvent, with a *transport.ReadOnlyError.
+func (c *Client) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
+
 // CalendarHomeURL returns the CalDAV calendar home URL for the user
 // Format: {baseURL}/dav/calendars/user/{username}/
 func (c *Client) CalendarHomeURL() string {
@@ -70,6 +80,10 @@ func (c *Client) AddressBookHomeURL() string {
 // doRequest performs an authenticated HTTP request using basic auth
 // The caller is responsible for closing the response body on success.
 func (c *Client) doRequest(ctx context.Context, method, url string, body io.Reader, contentType string) (*http.Response, error) {
+	if c.readOnly && transport.IsWriteMethod(method) {
+		return nil, &transport.ReadOnlyError{Operation: method + " " + url}
+	}
+
 	var bodyBytes []byte
 	if body != nil {
 		var err error
@@ -123,6 +137,17 @@ func (c *Client) doRequest(ctx context.Context, method, url string, body io.Read
 	return resp, nil
 }
 
+// Ping checks that the CalDAV server is reachable and accepts the credentials
+// by sending OPTIONS to the user's calendar home.
+func (c *Client) Ping(ctx context.Context) error {
+	resp, err := c.doRequest(ctx, http.MethodOptions, c.CalendarHomeURL(), nil, "")
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
 // CreateEvent creates a new calendar event via CalDAV PUT.
 func (c *Client) CreateEvent(ctx context.Context, calendarName string, event *Event) error {
 	if event.UID == "" {
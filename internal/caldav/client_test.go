@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
 )
 
 func TestNewClient(t *testing.T) {
@@ -326,6 +328,25 @@ func TestClient_CreateEvent(t *testing.T) {
 	}
 }
 
+func TestClient_CreateEvent_ReadOnly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s request in read-only mode", r.Method)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "testuser@example.com", "testtoken")
+	client.SetReadOnly(true)
+
+	event := &Event{
+		UID:   "test-event-123",
+		Start: time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC),
+	}
+	if err := client.CreateEvent(context.Background(), "Default", event); !transport.IsReadOnly(err) {
+		t.Errorf("CreateEvent() error = %v, want read-only error", err)
+	}
+}
+
 func TestClient_CreateEvent_MissingUID(t *testing.T) {
 	client := NewClient("https://caldav.example.com", "testuser", "testtoken")
 	ctx := context.Background()
@@ -418,3 +439,27 @@ func TestClient_CreateEvent_ServerError(t *testing.T) {
 		})
 	}
 }
+
+func TestPing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodOptions {
+			t.Errorf("Method = %q, want OPTIONS", r.Method)
+		}
+		if r.URL.Path != "/dav/calendars/user/testuser/" {
+			t.Errorf("Path = %q, want calendar home", r.URL.Path)
+		}
+		if _, pass, _ := r.BasicAuth(); pass != "good-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if err := NewClient(server.URL, "testuser", "good-token").Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if err := NewClient(server.URL, "testuser", "bad-token").Ping(context.Background()); err == nil {
+		t.Fatal("Ping() with rejected credentials should fail")
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
+)
+
+// allAccounts is the --account value that selects every configured account.
+const allAccounts = "all"
+
+// multiAccountCommands lists the commands that accept --account all or a
+// comma-separated list of accounts.
+const multiAccountCommands = "email list, email search, masked list, quota, calendar events"
+
+// accountValue is one account's successful result from fanOut.
+type accountValue[T any] struct {
+	Account string
+	Value   T
+}
+
+// accountError reports a failure for one account in a multi-account command.
+type accountError struct {
+	Account string `json:"account"`
+	Error   string `json:"error"`
+}
+
+// isMultiAccount reports whether an --account value selects several accounts.
+func isMultiAccount(value string) bool {
+	return strings.EqualFold(strings.TrimSpace(value), allAccounts) || strings.Contains(value, ",")
+}
+
+// MultiAccounts returns the accounts selected by --account all or a
+// comma-separated --account list. ok is false when a single account is
+// selected, in which case RequireAccount should be used instead.
+func (a *App) MultiAccounts() (accounts []string, ok bool, err error) {
+	if a.Flags == nil || !isMultiAccount(a.Flags.Account) {
+		return nil, false, nil
+	}
+
+	if strings.EqualFold(strings.TrimSpace(a.Flags.Account), allAccounts) {
+		accounts, err = config.ListAccounts()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get accounts: %w", err)
+		}
+		if len(accounts) == 0 {
+			return nil, false, fmt.Errorf("no accounts configured: run 'fastmail auth' to set up an account")
+		}
+		return accounts, true, nil
+	}
+
+	seen := map[string]bool{}
+	for _, acc := range strings.Split(a.Flags.Account, ",") {
+		acc = strings.TrimSpace(acc)
+		if acc == "" || seen[strings.ToLower(acc)] {
+			continue
+		}
+		seen[strings.ToLower(acc)] = true
+		accounts = append(accounts, acc)
+	}
+	if len(accounts) == 0 {
+		return nil, false, fmt.Errorf("%w: --account list is empty", ErrUsage)
+	}
+	return accounts, true, nil
+}
+
+// fanOut runs fn concurrently for each account with a client from newClient.
+// Successful results are returned in account order; failures (including
+// client creation errors) are returned per account instead of aborting.
+func fanOut[C, T any](ctx context.Context, accounts []string, newClient func(string) (C, error), fn func(context.Context, C) (T, error)) ([]accountValue[T], []accountError) {
+	values := make([]*T, len(accounts))
+	errs := make([]error, len(accounts))
+
+	var wg sync.WaitGroup
+	for i, account := range accounts {
+		wg.Add(1)
+		go func(i int, account string) {
+			defer wg.Done()
+			client, err := newClient(account)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			v, err := fn(ctx, client)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			values[i] = &v
+		}(i, account)
+	}
+	wg.Wait()
+
+	var results []accountValue[T]
+	failed := []accountError{}
+	for i, account := range accounts {
+		if errs[i] != nil {
+			failed = append(failed, accountError{Account: account, Error: errs[i].Error()})
+			continue
+		}
+		results = append(results, accountValue[T]{Account: account, Value: *values[i]})
+	}
+	return results, failed
+}
+
+// checkAccountErrors prints per-account failures to stderr in text mode and
+// returns an error only when every account failed.
+func checkAccountErrors(ctx context.Context, app *App, accounts []string, errs []accountError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	if len(errs) == len(accounts) {
+		msgs := make([]string, len(errs))
+		for i, e := range errs {
+			msgs[i] = e.Account + ": " + e.Error
+		}
+		return fmt.Errorf("all accounts failed: %s", strings.Join(msgs, "; "))
+	}
+	if !app.IsJSON(ctx) {
+		for _, e := range errs {
+			outfmt.Errorf("Warning: %s: %s", e.Account, e.Error)
+		}
+	}
+	return nil
+}
+
+// multiAccountJSON builds the JSON payload for a multi-account command: the
+// tagged rows under key, plus any per-account errors.
+func multiAccountJSON(key string, rows []map[string]any, errs []accountError) map[string]any {
+	if rows == nil {
+		rows = []map[string]any{}
+	}
+	out := map[string]any{key: rows}
+	if len(errs) > 0 {
+		out["errors"] = errs
+	}
+	return out
+}
+
+// withAccount converts v to a JSON object and tags it with account.
+func withAccount(account string, v any) map[string]any {
+	out := map[string]any{}
+	if data, err := json.Marshal(v); err == nil {
+		_ = json.Unmarshal(data, &out)
+	}
+	out["account"] = account
+	return out
+}
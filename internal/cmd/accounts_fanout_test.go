@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+)
+
+func TestMultiAccounts_CommaList(t *testing.T) {
+	app := newTestApp()
+	app.Flags.Account = " a@example.com, b@example.com,,A@example.com "
+
+	accounts, ok, err := app.MultiAccounts()
+	if err != nil || !ok {
+		t.Fatalf("MultiAccounts() = %v, %v, %v", accounts, ok, err)
+	}
+	if !reflect.DeepEqual(accounts, []string{"a@example.com", "b@example.com"}) {
+		t.Errorf("accounts = %v", accounts)
+	}
+
+	app.Flags.Account = "a@example.com"
+	if _, ok, _ := app.MultiAccounts(); ok {
+		t.Error("a single account should not fan out")
+	}
+}
+
+func TestRequireAccount_RejectsMultiAccount(t *testing.T) {
+	app := newTestApp()
+	app.Flags.Account = "all"
+
+	if _, err := app.RequireAccount(); !errors.Is(err, ErrUsage) {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	newClient := func(account string) (string, error) {
+		if account == "broken" {
+			return "", errors.New("no token")
+		}
+		return "client-" + account, nil
+	}
+
+	results, errs := fanOut(context.Background(), []string{"b", "broken", "a", "fails"}, newClient, func(_ context.Context, client string) (string, error) {
+		if client == "client-fails" {
+			return "", errors.New("boom")
+		}
+		return client, nil
+	})
+
+	if len(results) != 2 || results[0].Account != "b" || results[0].Value != "client-b" || results[1].Account != "a" {
+		t.Fatalf("results should keep account order, got %+v", results)
+	}
+	want := []accountError{{Account: "broken", Error: "no token"}, {Account: "fails", Error: "boom"}}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("errs = %+v", errs)
+	}
+}
+
+func TestCheckAccountErrors(t *testing.T) {
+	app := newTestApp()
+	ctx := context.Background()
+	errs := []accountError{{Account: "a", Error: "boom"}}
+
+	captureStderr(t, func() {
+		if err := checkAccountErrors(ctx, app, []string{"a", "b"}, errs); err != nil {
+			t.Errorf("partial failure should not fail the command: %v", err)
+		}
+	})
+	if err := checkAccountErrors(ctx, app, []string{"a"}, errs); err == nil {
+		t.Error("expected an error when every account failed")
+	}
+}
+
+func TestMergeAccountEmails(t *testing.T) {
+	results := []accountValue[accountEmails]{
+		{Account: "a", Value: accountEmails{
+			emails:       []jmap.Email{{ID: "a1", ThreadID: "t1", ReceivedAt: "2025-01-03T00:00:00Z"}, {ID: "a2", ReceivedAt: "2025-01-01T00:00:00Z"}},
+			threadCounts: map[string]int{"t1": 3},
+		}},
+		{Account: "b", Value: accountEmails{
+			emails:   []jmap.Email{{ID: "b1", ReceivedAt: "2025-01-02T00:00:00Z"}},
+			snippets: []jmap.SearchSnippet{{EmailID: "b1", Subject: "<mark>hi</mark>"}},
+		}},
+	}
+
+	rows := mergeAccountEmails(results, 2)
+
+	if len(rows) != 2 || rows[0].email.ID != "a1" || rows[1].email.ID != "b1" {
+		t.Fatalf("expected newest two across accounts, got %+v", rows)
+	}
+	if rows[0].account != "a" || rows[0].threadCount != 3 {
+		t.Errorf("row should keep account and thread count: %+v", rows[0])
+	}
+	if rows[1].account != "b" || rows[1].snippet == nil || rows[1].snippet.Subject != "<mark>hi</mark>" {
+		t.Errorf("row should keep its snippet: %+v", rows[1])
+	}
+}
+
+func TestWithAccount(t *testing.T) {
+	got := withAccount("a@example.com", jmap.Quota{ID: "q1"})
+	if got["account"] != "a@example.com" || got["id"] != "q1" {
+		t.Errorf("withAccount() = %v", got)
+	}
+}
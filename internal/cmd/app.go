@@ -4,11 +4,16 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 
+	"github.com/salmonumbrella/fastmail-cli/internal/audit"
+	"github.com/salmonumbrella/fastmail-cli/internal/cache"
+	"github.com/salmonumbrella/fastmail-cli/internal/caldav"
 	"github.com/salmonumbrella/fastmail-cli/internal/config"
 	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
 	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
 	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
 	"github.com/salmonumbrella/fastmail-cli/internal/ui"
 	"github.com/salmonumbrella/fastmail-cli/internal/webdav"
 	"github.com/spf13/cobra"
@@ -20,6 +25,19 @@ type App struct {
 	Flags  *rootFlags
 	UI     *ui.UI
 	Logger Logger
+
+	// recorder captures write requests under --dry-run (see dryRunRecorder).
+	recorder     *transport.Recorder
+	recorderOnce sync.Once
+
+	// auditEntries collects this command's writes per account until
+	// flushAudit; auditAccounts keeps the accounts in first-write order.
+	auditMu       sync.Mutex
+	auditEntries  map[string]*audit.Entry
+	auditAccounts []string
+
+	// tracer records HTTP traffic under --trace or --trace-file (see newTracer).
+	tracer *transport.Tracer
 }
 
 // Logger is the minimal interface we need from slog.Logger.
@@ -75,7 +93,7 @@ func (a *App) PrintJSON(cmd *cobra.Command, v any) error {
 }
 
 func (a *App) Confirm(cmd *cobra.Command, skip bool, prompt string, accepted ...string) (bool, error) {
-	if skip || a.IsJSON(cmd.Context()) || (a.Flags != nil && a.Flags.Yes) {
+	if skip || a.IsJSON(cmd.Context()) || (a.Flags != nil && (a.Flags.Yes || a.Flags.DryRun)) {
 		return true, nil
 	}
 	return confirmPrompt(os.Stderr, prompt, accepted...)
@@ -83,6 +101,9 @@ func (a *App) Confirm(cmd *cobra.Command, skip bool, prompt string, accepted ...
 
 func (a *App) RequireAccount() (string, error) {
 	if a.Flags != nil && a.Flags.Account != "" {
+		if isMultiAccount(a.Flags.Account) {
+			return "", fmt.Errorf("%w: --account %s is only supported by: %s", ErrUsage, a.Flags.Account, multiAccountCommands)
+		}
 		return a.Flags.Account, nil
 	}
 
@@ -105,12 +126,46 @@ func (a *App) JMAPClient() (*jmap.Client, error) {
 		return nil, err
 	}
 
-	token, err := config.GetToken(account)
+	return a.JMAPClientFor(account)
+}
+
+// JMAPClientFor creates a JMAP client for a specific configured account,
+// regardless of --account. --jmap-account still applies.
+func (a *App) JMAPClientFor(account string) (*jmap.Client, error) {
+	creds, err := config.GetCredentials(account)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get token for %s: %w", account, err)
 	}
 
-	return jmap.NewClient(token), nil
+	endpoints, err := a.Endpoints(account)
+	if err != nil {
+		return nil, err
+	}
+
+	client := jmap.NewClient(creds.Token)
+	if endpoints.JMAP != "" {
+		client.SetSessionURL(endpoints.JMAP)
+	}
+	if creds.OAuth != nil {
+		client.SetTokenRefresher(oauthRefresher(account, *creds.OAuth))
+	}
+	if len(creds.Scoped) > 0 {
+		client.SetScopedTokens(clientScopedTokens(creds.Scoped))
+	}
+	if a.Flags == nil || !a.Flags.NoCache {
+		client.SetCache(cache.ForAccount(account))
+	}
+	if a.Flags != nil && a.Flags.JMAPAccount != "" {
+		client.SetAccount(a.Flags.JMAPAccount)
+	}
+	client.SetReadOnly(a.ReadOnly())
+	if httpClient := a.dryRunHTTPClient(); httpClient != nil {
+		client.SetHTTPClient(httpClient)
+	}
+	if auditor := a.auditor(account); auditor != nil {
+		client.SetAuditor(auditor)
+	}
+	return client, nil
 }
 
 // WebDAVClient creates a WebDAV client for the configured account.
@@ -125,7 +180,39 @@ func (a *App) WebDAVClient() (*webdav.Client, error) {
 		return nil, fmt.Errorf("failed to get token for %s: %w", account, err)
 	}
 
-	return webdav.NewClient(token), nil
+	endpoints, err := a.Endpoints(account)
+	if err != nil {
+		return nil, err
+	}
+
+	client := webdav.NewClientWithBaseURL(token, endpoints.WebDAV)
+	client.SetReadOnly(a.ReadOnly())
+	client.SetHTTPClient(a.auditedHTTPClient(account))
+	return client, nil
+}
+
+// Endpoints returns the servers configured for account in the config file,
+// with Fastmail's WebDAV and CalDAV URLs as defaults. An empty JMAP endpoint
+// means Fastmail's.
+func (a *App) Endpoints(account string) (config.Endpoints, error) {
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return config.Endpoints{}, err
+	}
+	endpoints := settings.EndpointsFor(account)
+	if endpoints.WebDAV == "" {
+		endpoints.WebDAV = webdav.DefaultBaseURL
+	}
+	if endpoints.CalDAV == "" {
+		endpoints.CalDAV = caldav.DefaultBaseURL
+	}
+	return endpoints, nil
+}
+
+// ReadOnly reports whether --read-only (or FASTMAIL_READ_ONLY) is set. The
+// API clients enforce it themselves, so commands need not check it.
+func (a *App) ReadOnly() bool {
+	return a.Flags != nil && a.Flags.ReadOnly
 }
 
 // Suggest wraps an error with a user-facing suggestion.
@@ -0,0 +1,553 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
+	"github.com/salmonumbrella/fastmail-cli/internal/format"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultAttachmentTemplate    = "{date}_{from}_{name}"
+	defaultAttachmentConcurrency = 4
+	attachmentManifestName       = "manifest.json"
+)
+
+type attachmentScanClient interface {
+	ScanEmails(ctx context.Context, opts jmap.ScanEmailsOpts) ([]jmap.Email, error)
+}
+
+type attachmentFetchClient interface {
+	attachmentScanClient
+	DownloadBlob(ctx context.Context, blobID string) (io.ReadCloser, error)
+}
+
+// attachmentFilter selects attachments across emails.
+type attachmentFilter struct {
+	Query  string
+	Type   string
+	Name   string
+	Larger int64
+	Limit  int
+}
+
+// attachmentMatch is one attachment found by a cross-message search.
+type attachmentMatch struct {
+	EmailID    string `json:"emailId"`
+	ReceivedAt string `json:"receivedAt"`
+	From       string `json:"from"`
+	Subject    string `json:"subject"`
+	BlobID     string `json:"blobId"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Size       int64  `json:"size"`
+}
+
+// attachmentFile records where a fetched attachment was written.
+type attachmentFile struct {
+	attachmentMatch
+	OutputFile string `json:"outputFile"`
+	Written    int64  `json:"written,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// attachmentManifest is the summary written next to fetched attachments.
+type attachmentManifest struct {
+	GeneratedAt string           `json:"generatedAt"`
+	Query       string           `json:"query,omitempty"`
+	Dir         string           `json:"dir"`
+	Files       []attachmentFile `json:"files"`
+	Skipped     []attachmentFile `json:"skipped"`
+	Errors      []attachmentFile `json:"errors"`
+}
+
+func newAttachmentsCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "attachments",
+		Aliases: []string{"attachment"},
+		Short:   "Search and download attachments across emails",
+		Long: `Search and download attachments across many emails at once.
+
+Use 'fastmail email attachments' and 'fastmail email download' to work
+with the attachments of a single email.`,
+	}
+
+	cmd.AddCommand(newAttachmentsFindCmd(app))
+	cmd.AddCommand(newAttachmentsFetchCmd(app))
+
+	return cmd
+}
+
+// addAttachmentFilterFlags registers the filter flags shared by find and fetch.
+func addAttachmentFilterFlags(cmd *cobra.Command, query, fileType, name, larger *string, limit *int) {
+	cmd.Flags().StringVarP(query, "query", "q", "", "Email search query (supports after:/before: dates)")
+	cmd.Flags().StringVar(fileType, "type", "", "Attachment type: extension or MIME type (e.g. pdf, image/png)")
+	cmd.Flags().StringVar(name, "name", "", "Attachment filename glob, case-insensitive (e.g. '*invoice*')")
+	cmd.Flags().StringVar(larger, "larger", "", "Only attachments larger than this size (e.g. 500K, 1M)")
+	cmd.Flags().IntVar(limit, "limit", 0, "Maximum number of emails to scan (0 = all)")
+}
+
+func parseAttachmentFilter(query, fileType, name, larger string, limit int) (attachmentFilter, error) {
+	filter := attachmentFilter{
+		Query: query,
+		Type:  strings.ToLower(strings.TrimPrefix(strings.TrimSpace(fileType), ".")),
+		Name:  strings.ToLower(name),
+		Limit: limit,
+	}
+
+	if filter.Name != "" {
+		if _, err := path.Match(filter.Name, ""); err != nil {
+			return filter, fmt.Errorf("%w: invalid --name pattern %q: %v", ErrUsage, name, err)
+		}
+	}
+	if larger != "" {
+		size, err := format.ParseBytes(larger)
+		if err != nil {
+			return filter, fmt.Errorf("%w: --larger: %v", ErrUsage, err)
+		}
+		filter.Larger = size
+	}
+
+	return filter, nil
+}
+
+func newAttachmentsFindCmd(app *App) *cobra.Command {
+	var query, fileType, name, larger string
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:     "find",
+		Aliases: []string{"search", "ls"},
+		Short:   "List attachments across matching emails",
+		Long: `List attachments across every email matching the filters.
+
+Examples:
+  fastmail attachments find --type pdf --name '*invoice*'
+  fastmail attachments find --query "after:2025-01-01 from:billing" --larger 1M`,
+		Args: cobra.NoArgs,
+		RunE: runE(app, func(cmd *cobra.Command, _ []string, app *App) error {
+			filter, err := parseAttachmentFilter(query, fileType, name, larger, limit)
+			if err != nil {
+				return err
+			}
+
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+
+			matches, scanned, err := findAttachments(cmd.Context(), client, filter, time.Now())
+			if err != nil {
+				return err
+			}
+
+			if app.IsJSON(cmd.Context()) {
+				return app.PrintJSON(cmd, map[string]any{
+					"scanned":     scanned,
+					"attachments": matches,
+					"total":       len(matches),
+				})
+			}
+
+			if len(matches) == 0 {
+				printNoResults("No matching attachments in %d emails", scanned)
+				return nil
+			}
+
+			tw := outfmt.NewTabWriter()
+			fmt.Fprintln(tw, "DATE\tFROM\tNAME\tTYPE\tSIZE\tEMAIL ID\tBLOB ID")
+			for _, m := range matches {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					attachmentDate(m.ReceivedAt),
+					outfmt.SanitizeTab(format.Truncate(m.From, 30)),
+					outfmt.SanitizeTab(m.Name),
+					m.Type,
+					format.FormatBytes(m.Size),
+					m.EmailID,
+					m.BlobID,
+				)
+			}
+			tw.Flush()
+
+			return nil
+		}),
+	}
+
+	addAttachmentFilterFlags(cmd, &query, &fileType, &name, &larger, &limit)
+
+	return cmd
+}
+
+func newAttachmentsFetchCmd(app *App) *cobra.Command {
+	var query, fileType, name, larger string
+	var limit int
+	var outputDir string
+	var template string
+	var concurrency int
+	var manifestPath string
+
+	cmd := &cobra.Command{
+		Use:     "fetch",
+		Aliases: []string{"download", "dl"},
+		Short:   "Download attachments across matching emails",
+		Long: `Download every attachment matching the filters into a directory.
+
+Attachments shared by several emails (same blob ID) are downloaded once.
+Files that already exist are skipped, so re-running a fetch only downloads
+new attachments. A manifest.json describing every file is written to the
+output directory unless --manifest points elsewhere.
+
+Filename template fields:
+  {date}     Received date (YYYY-MM-DD)
+  {from}     Sender email address
+  {name}     Original attachment filename
+  {subject}  Email subject
+  {emailId}  Email ID
+  {blobId}   Attachment blob ID
+
+Examples:
+  fastmail attachments fetch --type pdf --name '*invoice*' --dir ~/Invoices
+  fastmail attachments fetch --query "after:2025-06-01 before:2025-07-01" \
+    --dir ./june --template "{from}/{date}_{name}"`,
+		Args: cobra.NoArgs,
+		RunE: runE(app, func(cmd *cobra.Command, _ []string, app *App) error {
+			filter, err := parseAttachmentFilter(query, fileType, name, larger, limit)
+			if err != nil {
+				return err
+			}
+			if concurrency <= 0 {
+				return fmt.Errorf("%w: --concurrency must be greater than 0", ErrUsage)
+			}
+			if strings.TrimSpace(template) == "" {
+				return fmt.Errorf("%w: --template cannot be empty", ErrUsage)
+			}
+
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+
+			return runAttachmentsFetch(cmd, app, client, filter, outputDir, template, concurrency, manifestPath)
+		}),
+	}
+
+	addAttachmentFilterFlags(cmd, &query, &fileType, &name, &larger, &limit)
+	cmd.Flags().StringVarP(&outputDir, "dir", "d", ".", "Output directory (created if it doesn't exist)")
+	cmd.Flags().StringVar(&template, "template", defaultAttachmentTemplate, "Filename template")
+	cmd.Flags().IntVar(&concurrency, "concurrency", defaultAttachmentConcurrency, "Number of parallel downloads")
+	cmd.Flags().StringVar(&manifestPath, "manifest", "", "Manifest path (default: <dir>/manifest.json)")
+
+	return cmd
+}
+
+func runAttachmentsFetch(cmd *cobra.Command, app *App, client attachmentFetchClient, filter attachmentFilter, outputDir, template string, concurrency int, manifestPath string) error {
+	ctx := cmd.Context()
+
+	matches, scanned, err := findAttachments(ctx, client, filter, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(outputDir, 0o750); err != nil {
+		return fmt.Errorf("failed to create directory '%s': %w", outputDir, err)
+	}
+
+	manifest := fetchAttachments(ctx, client, matches, outputDir, template, concurrency)
+	manifest.Query = filter.Query
+
+	if manifestPath == "" {
+		manifestPath = filepath.Join(outputDir, attachmentManifestName)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(manifestPath, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	if app.IsJSON(ctx) {
+		return app.PrintJSON(cmd, map[string]any{
+			"scanned":  scanned,
+			"manifest": manifestPath,
+			"files":    manifest.Files,
+			"skipped":  manifest.Skipped,
+			"errors":   manifest.Errors,
+			"total":    len(manifest.Files),
+		})
+	}
+
+	if len(matches) == 0 {
+		printNoResults("No matching attachments in %d emails", scanned)
+		return nil
+	}
+
+	for _, f := range manifest.Files {
+		fmt.Printf("Downloaded %s (%s)\n", f.OutputFile, format.FormatBytes(f.Written))
+	}
+	for _, f := range manifest.Skipped {
+		fmt.Printf("Skipping %s (already exists)\n", f.OutputFile)
+	}
+	for _, f := range manifest.Errors {
+		fmt.Printf("Error downloading %s: %s\n", f.Name, f.Error)
+	}
+	fmt.Printf("\nDownloaded %d attachment(s), skipped %d, failed %d\n", len(manifest.Files), len(manifest.Skipped), len(manifest.Errors))
+	fmt.Printf("Manifest: %s\n", manifestPath)
+
+	return nil
+}
+
+// findAttachments scans emails with attachments and returns every attachment
+// matching filter, along with the number of emails scanned.
+func findAttachments(ctx context.Context, client attachmentScanClient, filter attachmentFilter, now time.Time) ([]attachmentMatch, int, error) {
+	opts := jmap.ScanEmailsOpts{
+		HasAttachment:      true,
+		MinSize:            filter.Larger,
+		IncludeAttachments: true,
+		Limit:              filter.Limit,
+	}
+	if strings.TrimSpace(filter.Query) != "" {
+		searchFilter, err := parseEmailSearchFilter(filter.Query, now)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: %v", ErrUsage, err)
+		}
+		opts.Filter = searchFilter
+	}
+
+	emails, err := client.ScanEmails(ctx, opts)
+	if err != nil {
+		return nil, 0, cerrors.WithContext(err, "searching emails")
+	}
+
+	matches := make([]attachmentMatch, 0)
+	for _, e := range emails {
+		from := ""
+		if len(e.From) > 0 {
+			from = e.From[0].Email
+		}
+		for _, att := range e.Attachments {
+			if !filter.matches(att) {
+				continue
+			}
+			matches = append(matches, attachmentMatch{
+				EmailID:    e.ID,
+				ReceivedAt: e.ReceivedAt,
+				From:       from,
+				Subject:    e.Subject,
+				BlobID:     att.BlobID,
+				Name:       att.Name,
+				Type:       att.Type,
+				Size:       att.Size,
+			})
+		}
+	}
+
+	return matches, len(emails), nil
+}
+
+func (f attachmentFilter) matches(att jmap.Attachment) bool {
+	if att.BlobID == "" {
+		return false
+	}
+	if f.Larger > 0 && att.Size <= f.Larger {
+		return false
+	}
+	if f.Name != "" {
+		if ok, _ := path.Match(f.Name, strings.ToLower(att.Name)); !ok {
+			return false
+		}
+	}
+	if f.Type != "" && !attachmentTypeMatches(att, f.Type) {
+		return false
+	}
+	return true
+}
+
+// attachmentTypeMatches reports whether want (an extension such as "pdf" or a
+// MIME type such as "image/png" or "image/*") matches the attachment.
+func attachmentTypeMatches(att jmap.Attachment, want string) bool {
+	mediaType := strings.ToLower(att.Type)
+	if parsed, _, err := mime.ParseMediaType(mediaType); err == nil {
+		mediaType = parsed
+	}
+
+	if strings.Contains(want, "/") {
+		if strings.HasSuffix(want, "/*") {
+			return strings.HasPrefix(mediaType, strings.TrimSuffix(want, "*"))
+		}
+		return mediaType == want
+	}
+
+	if ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(att.Name)), "."); ext == want {
+		return true
+	}
+	_, subtype, _ := strings.Cut(mediaType, "/")
+	return subtype == want
+}
+
+// fetchAttachments downloads matches into dir using up to concurrency
+// parallel DownloadBlob calls. Attachments are deduplicated by blob ID.
+func fetchAttachments(ctx context.Context, client attachmentFetchClient, matches []attachmentMatch, dir, template string, concurrency int) attachmentManifest {
+	manifest := attachmentManifest{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Dir:         dir,
+		Files:       []attachmentFile{},
+		Skipped:     []attachmentFile{},
+		Errors:      []attachmentFile{},
+	}
+
+	// Assign output paths up front so names are stable regardless of the
+	// order downloads finish in.
+	seenBlobs := make(map[string]bool)
+	usedPaths := make(map[string]bool)
+	var pending []attachmentFile
+	for _, m := range matches {
+		if seenBlobs[m.BlobID] {
+			continue
+		}
+		seenBlobs[m.BlobID] = true
+
+		outputFile := uniqueAttachmentPath(filepath.Join(dir, expandAttachmentTemplate(template, m)), usedPaths)
+		file := attachmentFile{attachmentMatch: m, OutputFile: outputFile}
+		if _, err := os.Stat(outputFile); err == nil {
+			file.Reason = "already_exists"
+			manifest.Skipped = append(manifest.Skipped, file)
+			continue
+		}
+		pending = append(pending, file)
+	}
+
+	results := make([]attachmentFile, len(pending))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, len(pending)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = downloadAttachmentFile(ctx, client, pending[i])
+			}
+		}()
+	}
+	for i := range pending {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, r := range results {
+		if r.Error != "" {
+			manifest.Errors = append(manifest.Errors, r)
+		} else {
+			manifest.Files = append(manifest.Files, r)
+		}
+	}
+
+	return manifest
+}
+
+func downloadAttachmentFile(ctx context.Context, client attachmentFetchClient, file attachmentFile) attachmentFile {
+	if err := os.MkdirAll(filepath.Dir(file.OutputFile), 0o750); err != nil {
+		file.Error = err.Error()
+		return file
+	}
+
+	reader, err := client.DownloadBlob(ctx, file.BlobID)
+	if err != nil {
+		file.Error = err.Error()
+		return file
+	}
+	defer reader.Close()
+
+	outFile, err := os.OpenFile(file.OutputFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		file.Error = err.Error()
+		return file
+	}
+
+	written, err := io.Copy(outFile, reader)
+	closeErr := outFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.OutputFile)
+		file.Error = err.Error()
+		return file
+	}
+
+	file.Written = written
+	return file
+}
+
+// expandAttachmentTemplate fills in the filename template for one attachment.
+// Each field is sanitized on its own so only '/' in the template itself
+// creates subdirectories.
+func expandAttachmentTemplate(template string, m attachmentMatch) string {
+	name := m.Name
+	if name == "" {
+		name = "attachment-" + m.BlobID
+	}
+
+	replacer := strings.NewReplacer(
+		"{date}", attachmentTemplateField(attachmentDate(m.ReceivedAt)),
+		"{from}", attachmentTemplateField(m.From),
+		"{name}", attachmentTemplateField(name),
+		"{subject}", attachmentTemplateField(format.Truncate(m.Subject, 60)),
+		"{emailId}", attachmentTemplateField(m.EmailID),
+		"{blobId}", attachmentTemplateField(m.BlobID),
+	)
+
+	var parts []string
+	for _, part := range strings.Split(replacer.Replace(template), "/") {
+		if part = format.SanitizeFilename(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "attachment-" + format.SanitizeFilename(m.BlobID)
+	}
+	return filepath.Join(parts...)
+}
+
+func attachmentTemplateField(value string) string {
+	value = strings.NewReplacer("/", "_", "\\", "_").Replace(value)
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+// uniqueAttachmentPath appends -2, -3, ... before the extension when another
+// attachment in the same fetch already claimed p.
+func uniqueAttachmentPath(p string, used map[string]bool) string {
+	candidate := p
+	ext := filepath.Ext(p)
+	base := strings.TrimSuffix(p, ext)
+	for n := 2; used[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d%s", base, n, ext)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+func attachmentDate(receivedAt string) string {
+	if t, err := time.Parse(time.RFC3339, receivedAt); err == nil {
+		return t.Format("2006-01-02")
+	}
+	return receivedAt
+}
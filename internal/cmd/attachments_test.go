@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/spf13/cobra"
+)
+
+type fakeAttachmentClient struct {
+	emails   []jmap.Email
+	scanOpts jmap.ScanEmailsOpts
+	blobs    map[string]string
+
+	mu        sync.Mutex
+	downloads []string
+}
+
+func (f *fakeAttachmentClient) ScanEmails(_ context.Context, opts jmap.ScanEmailsOpts) ([]jmap.Email, error) {
+	f.scanOpts = opts
+	return f.emails, nil
+}
+
+func (f *fakeAttachmentClient) DownloadBlob(_ context.Context, blobID string) (io.ReadCloser, error) {
+	f.mu.Lock()
+	f.downloads = append(f.downloads, blobID)
+	f.mu.Unlock()
+
+	content, ok := f.blobs[blobID]
+	if !ok {
+		return nil, errors.New("blob not found")
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+func invoiceEmails() []jmap.Email {
+	from := []jmap.EmailAddress{{Email: "billing@example.com"}}
+	return []jmap.Email{
+		{
+			ID: "e1", From: from, Subject: "March invoice", ReceivedAt: "2025-03-01T09:00:00Z",
+			Attachments: []jmap.Attachment{
+				{BlobID: "b1", Name: "Invoice-March.pdf", Type: "application/pdf", Size: 4096},
+				{BlobID: "b2", Name: "logo.png", Type: "image/png", Size: 100},
+			},
+		},
+		{
+			ID: "e2", From: from, Subject: "Fwd: March invoice", ReceivedAt: "2025-03-02T09:00:00Z",
+			Attachments: []jmap.Attachment{
+				{BlobID: "b1", Name: "Invoice-March.pdf", Type: "application/pdf", Size: 4096},
+			},
+		},
+		{
+			ID: "e3", From: from, Subject: "April invoice", ReceivedAt: "2025-04-01T09:00:00Z",
+			Attachments: []jmap.Attachment{
+				{BlobID: "b3", Name: "invoice-april", Type: "application/pdf; name=invoice-april", Size: 512},
+			},
+		},
+	}
+}
+
+func TestFindAttachments_Filters(t *testing.T) {
+	client := &fakeAttachmentClient{emails: invoiceEmails()}
+
+	filter, err := parseAttachmentFilter("after:2025-01-01 invoice", "pdf", "*INVOICE*", "1K", 0)
+	if err != nil {
+		t.Fatalf("parseAttachmentFilter error: %v", err)
+	}
+	matches, scanned, err := findAttachments(context.Background(), client, filter, time.Now())
+	if err != nil {
+		t.Fatalf("findAttachments error: %v", err)
+	}
+
+	if scanned != 3 {
+		t.Errorf("scanned = %d, want 3", scanned)
+	}
+	if !client.scanOpts.HasAttachment || !client.scanOpts.IncludeAttachments || client.scanOpts.MinSize != 1024 {
+		t.Errorf("unexpected scan options: %+v", client.scanOpts)
+	}
+	if client.scanOpts.Filter == nil || client.scanOpts.Filter.Text != "invoice" || client.scanOpts.Filter.After == "" {
+		t.Errorf("expected query to be parsed into search filter, got %+v", client.scanOpts.Filter)
+	}
+	if len(matches) != 2 || matches[0].EmailID != "e1" || matches[1].EmailID != "e2" {
+		t.Fatalf("expected the March PDF from e1 and e2, got %+v", matches)
+	}
+}
+
+func TestAttachmentTypeMatches(t *testing.T) {
+	pdfNoExt := jmap.Attachment{Name: "invoice-april", Type: "application/pdf; name=invoice-april"}
+	png := jmap.Attachment{Name: "logo.png", Type: "image/png"}
+
+	cases := []struct {
+		att  jmap.Attachment
+		want string
+		ok   bool
+	}{
+		{pdfNoExt, "pdf", true},
+		{pdfNoExt, "application/pdf", true},
+		{png, "png", true},
+		{png, "image/*", true},
+		{png, "pdf", false},
+	}
+	for _, tt := range cases {
+		if got := attachmentTypeMatches(tt.att, tt.want); got != tt.ok {
+			t.Errorf("attachmentTypeMatches(%q, %q) = %v, want %v", tt.att.Name, tt.want, got, tt.ok)
+		}
+	}
+}
+
+func TestParseAttachmentFilter_InvalidSize(t *testing.T) {
+	if _, err := parseAttachmentFilter("", "", "", "big", 0); !errors.Is(err, ErrUsage) {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
+
+func TestExpandAttachmentTemplate(t *testing.T) {
+	m := attachmentMatch{
+		EmailID:    "e1",
+		ReceivedAt: "2025-03-01T09:00:00Z",
+		From:       "billing@example.com",
+		Name:       "../../etc/passwd",
+		BlobID:     "b1",
+	}
+
+	if got := expandAttachmentTemplate(defaultAttachmentTemplate, m); got != "2025-03-01_billing@example.com_.._.._etc_passwd" {
+		t.Errorf("unexpected filename %q", got)
+	}
+	if got := expandAttachmentTemplate("{from}/{date}_{blobId}", m); got != filepath.Join("billing@example.com", "2025-03-01_b1") {
+		t.Errorf("unexpected nested filename %q", got)
+	}
+}
+
+func TestRunAttachmentsFetch_DedupesAndWritesManifest(t *testing.T) {
+	dir := t.TempDir()
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeAttachmentClient{
+		emails: invoiceEmails(),
+		blobs:  map[string]string{"b1": "march", "b3": "april"},
+	}
+	filter, err := parseAttachmentFilter("", "pdf", "", "", 0)
+	if err != nil {
+		t.Fatalf("parseAttachmentFilter error: %v", err)
+	}
+
+	// A file from an earlier run is skipped rather than downloaded again.
+	existing := filepath.Join(dir, "2025-04-01_billing@example.com_invoice-april")
+	if err := os.WriteFile(existing, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runAttachmentsFetch(cmd, app, client, filter, dir, defaultAttachmentTemplate, 2, ""); err != nil {
+			t.Fatalf("runAttachmentsFetch error: %v", err)
+		}
+	})
+
+	if len(client.downloads) != 1 || client.downloads[0] != "b1" {
+		t.Fatalf("expected a single download of b1, got %v", client.downloads)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "2025-03-01_billing@example.com_Invoice-March.pdf"))
+	if err != nil || string(data) != "march" {
+		t.Fatalf("expected downloaded file content, got %q (%v)", data, err)
+	}
+	if !strings.Contains(out, "Downloaded 1 attachment(s), skipped 1, failed 0") {
+		t.Errorf("unexpected summary: %q", out)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, attachmentManifestName))
+	if err != nil {
+		t.Fatalf("manifest not written: %v", err)
+	}
+	var manifest attachmentManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	if len(manifest.Files) != 1 || manifest.Files[0].BlobID != "b1" || manifest.Files[0].Written != 5 {
+		t.Errorf("unexpected manifest files: %+v", manifest.Files)
+	}
+	if len(manifest.Skipped) != 1 || manifest.Skipped[0].Reason != "already_exists" {
+		t.Errorf("unexpected manifest skipped: %+v", manifest.Skipped)
+	}
+}
+
+func TestUniqueAttachmentPath(t *testing.T) {
+	used := map[string]bool{}
+	first := uniqueAttachmentPath("out/report.pdf", used)
+	second := uniqueAttachmentPath("out/report.pdf", used)
+	if first != "out/report.pdf" || second != "out/report-2.pdf" {
+		t.Fatalf("got %q and %q", first, second)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/salmonumbrella/fastmail-cli/internal/auth"
 	"github.com/salmonumbrella/fastmail-cli/internal/config"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
 	"github.com/salmonumbrella/fastmail-cli/internal/logging"
 	"github.com/salmonumbrella/fastmail-cli/internal/ui"
 )
@@ -51,6 +53,7 @@ func newAuthCmd(app *App) *cobra.Command {
 	cmd.AddCommand(newAuthLoginCmd(app))
 	cmd.AddCommand(newAuthAddCmd(app))
 	cmd.AddCommand(newAuthListCmd(app))
+	cmd.AddCommand(newAuthAccountsCmd(app))
 	cmd.AddCommand(newAuthRemoveCmd(app))
 	cmd.AddCommand(newAuthStatusCmd(app))
 
@@ -93,29 +96,70 @@ func runAuthLogin(cmd *cobra.Command) error {
 }
 
 func newAuthLoginCmd(app *App) *cobra.Command {
-	return &cobra.Command{
+	var opts oauthLoginOptions
+
+	cmd := &cobra.Command{
 		Use:   "login",
 		Short: "Authenticate via browser (recommended)",
-		Long:  `Starts a local setup server for interactive authentication. Use --no-browser to print the URL without auto-launching a browser.`,
-		Args:  cobra.NoArgs,
-		RunE: runE(app, func(cmd *cobra.Command, _ []string, _ *App) error {
+		Long: `Starts a local setup server for interactive authentication. Use --no-browser to print the URL without auto-launching a browser.
+
+With --oauth, signs in with OAuth instead of an API token: the browser is sent
+to Fastmail from a listener on 127.0.0.1 and the authorization code comes back
+to it (PKCE). Access and refresh tokens are stored in the keyring, and expired
+access tokens are refreshed automatically. On machines without a browser, use
+--device to sign in from another device with a one-time code.
+
+OAuth needs a registered client ID (--client-id or FASTMAIL_OAUTH_CLIENT_ID).`,
+		Example: `  fastmail auth login
+  fastmail auth login --oauth --client-id YOUR_CLIENT_ID
+  fastmail auth login --device --client-id YOUR_CLIENT_ID`,
+		Args: cobra.NoArgs,
+		RunE: runE(app, func(cmd *cobra.Command, _ []string, app *App) error {
+			if opts.OAuth || opts.Device {
+				return runAuthOAuthLogin(cmd, app, opts)
+			}
 			return runAuthLogin(cmd)
 		}),
 	}
+
+	cmd.Flags().BoolVar(&opts.OAuth, "oauth", false, "Sign in with OAuth (authorization code + PKCE)")
+	cmd.Flags().BoolVar(&opts.Device, "device", false, "Sign in with the OAuth device flow (no local browser needed)")
+	cmd.Flags().StringVar(&opts.ClientID, "client-id", "", "OAuth client ID (default: FASTMAIL_OAUTH_CLIENT_ID)")
+	cmd.Flags().StringSliceVar(&opts.Scopes, "scope", nil, "OAuth scopes to request (default: all JMAP capabilities the CLI uses)")
+	cmd.Flags().StringVar(&opts.Email, "email", "", "Account email to store the login under (default: the session's username)")
+
+	return cmd
 }
 
 func newAuthAddCmd(app *App) *cobra.Command {
-	var tokenFlag string
+	var tokenFlag, scopeFlag string
 
 	cmd := &cobra.Command{
 		Use:   "add <email>",
 		Short: "Add a Fastmail account (prompts for API token)",
-		Args:  cobra.ExactArgs(1),
+		Long: `Add a Fastmail account (prompts for API token).
+
+With --scope, the token is stored as an extra token limited to read, write or
+send access. Each request then uses the least-privileged token that covers it:
+reads use the read token, /set calls the write token, and sending the send
+token, falling back to the account's main token.`,
+		Example: `  fastmail auth add you@fastmail.com
+  FASTMAIL_TOKEN=... fastmail auth add you@fastmail.com --scope read`,
+		Args: cobra.ExactArgs(1),
 		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
 			email := strings.TrimSpace(args[0])
 			if email == "" {
 				return fmt.Errorf("email cannot be empty")
 			}
+			// Refuse before prompting for a token that could not be saved.
+			if scopeFlag == "" {
+				if err := config.CheckKeyringAccount(email); err != nil {
+					if errors.Is(err, config.ErrTokenCommand) {
+						return fmt.Errorf("%w: %w", ErrUsage, err)
+					}
+					return err
+				}
+			}
 
 			var token string
 
@@ -151,8 +195,15 @@ func newAuthAddCmd(app *App) *cobra.Command {
 				return fmt.Errorf("token cannot be empty")
 			}
 
+			if scopeFlag != "" {
+				return runAuthAddScoped(cmd, app, email, scopeFlag, token)
+			}
+
 			// Save to keychain
 			if err := config.SaveToken(email, token); err != nil {
+				if errors.Is(err, config.ErrTokenCommand) {
+					return fmt.Errorf("%w: %w", ErrUsage, err)
+				}
 				return fmt.Errorf("failed to save token: %w", err)
 			}
 
@@ -169,6 +220,7 @@ func newAuthAddCmd(app *App) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&tokenFlag, "token", "", "API token (deprecated: use FASTMAIL_TOKEN env var instead)")
+	cmd.Flags().StringVar(&scopeFlag, "scope", "", "Store as a scoped token: read, write or send")
 
 	return cmd
 }
@@ -199,8 +251,9 @@ func newAuthListCmd(app *App) *cobra.Command {
 
 			if app.IsJSON(cmd.Context()) {
 				type account struct {
-					Email     string `json:"email"`
-					CreatedAt string `json:"created_at,omitempty"`
+					Email        string `json:"email"`
+					CreatedAt    string `json:"created_at,omitempty"`
+					TokenCommand bool   `json:"token_command,omitempty"`
 				}
 				accounts := make([]account, len(tokens))
 				for i, tok := range tokens {
@@ -209,8 +262,9 @@ func newAuthListCmd(app *App) *cobra.Command {
 						createdAt = tok.CreatedAt.UTC().Format("2006-01-02T15:04:05Z07:00")
 					}
 					accounts[i] = account{
-						Email:     tok.Email,
-						CreatedAt: createdAt,
+						Email:        tok.Email,
+						CreatedAt:    createdAt,
+						TokenCommand: tok.TokenCommand,
 					}
 				}
 				return app.PrintJSON(cmd, accounts)
@@ -221,6 +275,9 @@ func newAuthListCmd(app *App) *cobra.Command {
 				if !tok.CreatedAt.IsZero() {
 					createdAt = tok.CreatedAt.UTC().Format("2006-01-02T15:04:05Z07:00")
 				}
+				if tok.TokenCommand {
+					createdAt = "(token_command)"
+				}
 				fmt.Printf("%s\t%s\n", tok.Email, createdAt)
 			}
 			return nil
@@ -229,7 +286,9 @@ func newAuthListCmd(app *App) *cobra.Command {
 }
 
 func newAuthRemoveCmd(app *App) *cobra.Command {
-	return &cobra.Command{
+	var scopeFlag string
+
+	cmd := &cobra.Command{
 		Use:   "remove <email>",
 		Short: "Remove a configured account",
 		Args:  cobra.ExactArgs(1),
@@ -239,7 +298,29 @@ func newAuthRemoveCmd(app *App) *cobra.Command {
 				return fmt.Errorf("email cannot be empty")
 			}
 
+			if scopeFlag != "" {
+				scope, err := jmap.ParseTokenScope(scopeFlag)
+				if err != nil {
+					return fmt.Errorf("%w: %v", ErrUsage, err)
+				}
+				if err := config.DeleteScopedToken(email, scope.String()); err != nil {
+					return fmt.Errorf("failed to remove %s token: %w", scope, err)
+				}
+				if app.IsJSON(cmd.Context()) {
+					return app.PrintJSON(cmd, map[string]any{
+						"deleted": true,
+						"email":   email,
+						"scope":   scope.String(),
+					})
+				}
+				fmt.Fprintf(os.Stderr, "Removed %s token for %s\n", scope, email)
+				return nil
+			}
+
 			if err := config.DeleteToken(email); err != nil {
+				if errors.Is(err, config.ErrTokenCommand) {
+					return fmt.Errorf("%w: %w", ErrUsage, err)
+				}
 				if errors.Is(err, keyring.ErrKeyNotFound) {
 					return fmt.Errorf("account not found: %s", email)
 				}
@@ -257,12 +338,16 @@ func newAuthRemoveCmd(app *App) *cobra.Command {
 			return nil
 		}),
 	}
+
+	cmd.Flags().StringVar(&scopeFlag, "scope", "", "Only remove the account's scoped token (read, write or send)")
+
+	return cmd
 }
 
 func newAuthStatusCmd(app *App) *cobra.Command {
 	return &cobra.Command{
 		Use:   "status",
-		Short: "Show current default account",
+		Short: "Show current default account and its capabilities",
 		Args:  cobra.NoArgs,
 		RunE: runE(app, func(cmd *cobra.Command, _ []string, app *App) error {
 			logger := logging.FromContext(cmd.Context())
@@ -312,12 +397,41 @@ func newAuthStatusCmd(app *App) *cobra.Command {
 				source = "first_account"
 			}
 
+			// Capability section: best effort, so status still works offline.
+			var report *capabilityReport
+			var reportErr error
+			if client, err := app.JMAPClientFor(defaultAccount); err != nil {
+				reportErr = err
+			} else {
+				ctx, cancel := context.WithTimeout(cmd.Context(), probeTimeout)
+				session, err := client.GetSession(ctx)
+				cancel()
+				if err != nil {
+					reportErr = err
+				} else {
+					r := buildCapabilityReport(cmd.Context(), session, capabilityProbes{sieve: config.HasSieveCredentials(defaultAccount)})
+					report = &r
+				}
+			}
+			if reportErr != nil {
+				logger.Debug("capability check failed", "error", reportErr)
+			}
+
 			if app.IsJSON(cmd.Context()) {
-				return app.PrintJSON(cmd, map[string]any{
+				out := map[string]any{
 					"default":  defaultAccount,
 					"source":   source,
 					"accounts": accounts,
-				})
+				}
+				if report != nil {
+					out["capabilities"] = report
+				} else {
+					out["capabilitiesError"] = reportErr.Error()
+				}
+				if scoped := scopedTokenStatuses(tokenMap[defaultAccount]); len(scoped) > 0 {
+					out["tokens"] = scoped
+				}
+				return app.PrintJSON(cmd, out)
 			}
 
 			fmt.Printf("Default account: %s (from %s)\n", defaultAccount, source)
@@ -337,6 +451,24 @@ func newAuthStatusCmd(app *App) *cobra.Command {
 				}
 				fmt.Printf("  %s %s\n", marker, acc)
 			}
+
+			if scoped := scopedTokenStatuses(tokenMap[defaultAccount]); len(scoped) > 0 {
+				fmt.Printf("Scoped tokens:\n")
+				for _, st := range scoped {
+					access := "read-write"
+					if st.ReadOnly {
+						access = "read-only"
+					}
+					fmt.Printf("  %-5s  %s, %d capabilities\n", st.Scope, access, len(st.Capabilities))
+				}
+			}
+
+			fmt.Println()
+			if report != nil {
+				printCapabilitySummary(*report)
+			} else {
+				fmt.Printf("Capabilities: unavailable (%v)\n", reportErr)
+			}
 			return nil
 		}),
 	}
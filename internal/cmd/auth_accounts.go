@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
+	"github.com/spf13/cobra"
+)
+
+type sessionClient interface {
+	GetSession(ctx context.Context) (*jmap.Session, error)
+}
+
+// sessionAccountOutput is one JMAP account in 'auth accounts' output.
+type sessionAccountOutput struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	IsPersonal   bool     `json:"isPersonal"`
+	IsReadOnly   bool     `json:"isReadOnly"`
+	Selected     bool     `json:"selected"`
+	PrimaryFor   []string `json:"primaryFor"`
+	Capabilities []string `json:"capabilities"`
+}
+
+func newAuthAccountsCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "accounts",
+		Short: "List the JMAP accounts the current token can access",
+		Long: `List the JMAP accounts in the session of the current login, including
+shared and delegated accounts.
+
+The account marked with * is the one commands operate on: the primary mail
+account, or the one chosen with --jmap-account <id|name>.`,
+		Example: `  fastmail auth accounts
+  fastmail --jmap-account team@example.com email list`,
+		Args: cobra.NoArgs,
+		RunE: runE(app, func(cmd *cobra.Command, _ []string, app *App) error {
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+			return runAuthAccounts(cmd, app, client)
+		}),
+	}
+}
+
+func runAuthAccounts(cmd *cobra.Command, app *App, client sessionClient) error {
+	ctx := cmd.Context()
+
+	session, err := client.GetSession(ctx)
+	if err != nil {
+		return cerrors.WithContext(err, "getting session")
+	}
+
+	accounts := sessionAccounts(session)
+
+	if app.IsJSON(ctx) {
+		return app.PrintJSON(cmd, map[string]any{
+			"selected": session.AccountID,
+			"accounts": accounts,
+		})
+	}
+
+	tw := outfmt.NewTabWriter()
+	fmt.Fprintln(tw, "\tID\tNAME\tPERSONAL\tREAD-ONLY\tPRIMARY FOR")
+	for _, acc := range accounts {
+		marker := ""
+		if acc.Selected {
+			marker = "*"
+		}
+		primary := strings.Join(acc.PrimaryFor, ",")
+		if primary == "" {
+			primary = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			marker,
+			acc.ID,
+			outfmt.SanitizeTab(acc.Name),
+			yesNo(acc.IsPersonal),
+			yesNo(acc.IsReadOnly),
+			primary,
+		)
+	}
+	tw.Flush()
+
+	return nil
+}
+
+// sessionAccounts lists the session's accounts sorted with personal accounts
+// first, then by name.
+func sessionAccounts(session *jmap.Session) []sessionAccountOutput {
+	primaryFor := map[string][]string{}
+	for capability, id := range session.PrimaryAccounts {
+		primaryFor[id] = append(primaryFor[id], shortCapability(capability))
+	}
+
+	out := make([]sessionAccountOutput, 0, len(session.Accounts))
+	for id, acc := range session.Accounts {
+		caps := make([]string, 0, len(acc.AccountCapabilities))
+		for capability := range acc.AccountCapabilities {
+			caps = append(caps, capability)
+		}
+		sort.Strings(caps)
+
+		primary := primaryFor[id]
+		if primary == nil {
+			primary = []string{}
+		}
+		sort.Strings(primary)
+
+		out = append(out, sessionAccountOutput{
+			ID:           id,
+			Name:         acc.Name,
+			IsPersonal:   acc.IsPersonal,
+			IsReadOnly:   acc.IsReadOnly,
+			Selected:     id == session.AccountID,
+			PrimaryFor:   primary,
+			Capabilities: caps,
+		})
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].IsPersonal != out[j].IsPersonal {
+			return out[i].IsPersonal
+		}
+		if out[i].Name != out[j].Name {
+			return out[i].Name < out[j].Name
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// shortCapability shortens a capability URI for display, e.g.
+// "urn:ietf:params:jmap:mail" becomes "mail".
+func shortCapability(capability string) string {
+	if rest, ok := strings.CutPrefix(capability, "urn:ietf:params:jmap:"); ok {
+		return rest
+	}
+	return path.Base(capability)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/spf13/cobra"
+)
+
+type fakeSessionClient struct {
+	session *jmap.Session
+}
+
+func (f *fakeSessionClient) GetSession(_ context.Context) (*jmap.Session, error) {
+	return f.session, nil
+}
+
+func TestSessionAccounts(t *testing.T) {
+	session := &jmap.Session{
+		AccountID: "a2",
+		Accounts: map[string]jmap.SessionAccount{
+			"a2": {Name: "team@example.com", IsReadOnly: true},
+			"a1": {Name: "me@example.com", IsPersonal: true, AccountCapabilities: map[string]any{"urn:ietf:params:jmap:mail": map[string]any{}}},
+		},
+		PrimaryAccounts: map[string]string{
+			"urn:ietf:params:jmap:mail":                "a1",
+			"https://www.fastmail.com/dev/maskedemail": "a1",
+		},
+	}
+
+	accounts := sessionAccounts(session)
+
+	if len(accounts) != 2 || accounts[0].ID != "a1" || accounts[1].ID != "a2" {
+		t.Fatalf("personal account should be listed first, got %+v", accounts)
+	}
+	if !reflect.DeepEqual(accounts[0].PrimaryFor, []string{"mail", "maskedemail"}) {
+		t.Errorf("PrimaryFor = %v", accounts[0].PrimaryFor)
+	}
+	if !reflect.DeepEqual(accounts[0].Capabilities, []string{"urn:ietf:params:jmap:mail"}) {
+		t.Errorf("Capabilities = %v", accounts[0].Capabilities)
+	}
+	if accounts[0].Selected || !accounts[1].Selected {
+		t.Errorf("only the session's AccountID should be selected: %+v", accounts)
+	}
+}
+
+func TestRunAuthAccounts_Text(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeSessionClient{session: &jmap.Session{
+		AccountID: "a1",
+		Accounts:  map[string]jmap.SessionAccount{"a1": {Name: "me@example.com", IsPersonal: true}},
+	}}
+
+	out := captureStdout(t, func() {
+		if err := runAuthAccounts(cmd, app, client); err != nil {
+			t.Fatalf("runAuthAccounts error: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "*") || !strings.Contains(out, "me@example.com") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/auth"
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+)
+
+const (
+	oauthClientIDEnv = "FASTMAIL_OAUTH_CLIENT_ID"
+	oauthIssuerEnv   = "FASTMAIL_OAUTH_ISSUER"
+)
+
+type oauthLoginOptions struct {
+	OAuth    bool
+	Device   bool
+	ClientID string
+	Scopes   []string
+	Email    string
+}
+
+func runAuthOAuthLogin(cmd *cobra.Command, app *App, opts oauthLoginOptions) error {
+	ctx := cmd.Context()
+
+	clientID := strings.TrimSpace(opts.ClientID)
+	if clientID == "" {
+		clientID = strings.TrimSpace(os.Getenv(oauthClientIDEnv))
+	}
+	if clientID == "" {
+		return Suggest(
+			fmt.Errorf("%w: an OAuth client ID is required", ErrUsage),
+			"Pass --client-id or set "+oauthClientIDEnv,
+		)
+	}
+
+	noBrowser, err := cmd.Flags().GetBool("no-browser")
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := auth.DiscoverOAuth(ctx, nil, envOr(oauthIssuerEnv, auth.DefaultOAuthIssuer))
+	if err != nil {
+		return err
+	}
+
+	client := &auth.OAuthClient{
+		ClientID:  clientID,
+		Scopes:    opts.Scopes,
+		Endpoints: *endpoints,
+		Out:       os.Stderr,
+	}
+	if len(client.Scopes) == 0 {
+		client.Scopes = auth.DefaultOAuthScopes
+	}
+	if !noBrowser {
+		client.OpenBrowser = auth.OpenBrowser
+	}
+
+	issued := time.Now()
+	var token *auth.OAuthToken
+	if opts.Device {
+		token, err = client.LoginDevice(ctx)
+	} else {
+		token, err = client.LoginPKCE(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("oauth login failed: %w", err)
+	}
+
+	email := strings.TrimSpace(opts.Email)
+	if email == "" {
+		session, err := jmap.NewClient(token.AccessToken).GetSession(ctx)
+		if err != nil {
+			return cerrors.WithContext(err, "getting session")
+		}
+		email = session.Username
+	}
+	if email == "" {
+		return Suggest(
+			fmt.Errorf("the session did not include a username"),
+			"Re-run with --email <address>",
+		)
+	}
+
+	if err := config.SaveOAuthToken(email, token.AccessToken, config.OAuthCredentials{
+		RefreshToken: token.RefreshToken,
+		ExpiresAt:    token.ExpiresAt(issued),
+		TokenURL:     endpoints.TokenEndpoint,
+		ClientID:     clientID,
+	}); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+
+	if app.IsJSON(ctx) {
+		return app.PrintJSON(cmd, map[string]any{
+			"status":      "configured",
+			"email":       email,
+			"authType":    "oauth",
+			"refreshable": token.RefreshToken != "",
+		})
+	}
+
+	fmt.Fprintf(os.Stderr, "\nSigned in with OAuth. Account %s is now configured.\n", email)
+	if token.RefreshToken == "" {
+		fmt.Fprintln(os.Stderr, "Warning: no refresh token was issued; run this again when the access token expires.")
+	}
+	fmt.Fprintf(os.Stderr, "Try: fastmail --account %s email list --limit 5\n", email)
+	return nil
+}
+
+// oauthRefresher redeems the account's refresh token and stores the new
+// tokens, so later invocations start with a valid access token.
+func oauthRefresher(account string, creds config.OAuthCredentials) jmap.TokenRefresher {
+	return func(ctx context.Context) (string, error) {
+		client := &auth.OAuthClient{
+			ClientID:  creds.ClientID,
+			Endpoints: auth.OAuthEndpoints{TokenEndpoint: creds.TokenURL},
+		}
+
+		issued := time.Now()
+		token, err := client.Refresh(ctx, creds.RefreshToken)
+		if err != nil {
+			return "", err
+		}
+
+		creds.RefreshToken = token.RefreshToken
+		creds.ExpiresAt = token.ExpiresAt(issued)
+		if err := config.SaveOAuthToken(account, token.AccessToken, creds); err != nil {
+			return "", fmt.Errorf("saving refreshed token: %w", err)
+		}
+		return token.AccessToken, nil
+	}
+}
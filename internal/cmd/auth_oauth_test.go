@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRunAuthOAuthLogin_RequiresClientID(t *testing.T) {
+	t.Setenv(oauthClientIDEnv, "")
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	err := runAuthOAuthLogin(cmd, newTestApp(), oauthLoginOptions{OAuth: true})
+	if !errors.Is(err, ErrUsage) {
+		t.Fatalf("error = %v, want usage error", err)
+	}
+}
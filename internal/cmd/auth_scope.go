@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+)
+
+// scopedSession fetches the JMAP session for a token from the account's
+// server; tests replace it.
+var scopedSession = func(ctx context.Context, email, token string) (*jmap.Session, error) {
+	client := jmap.NewClient(token)
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+	if endpoint := settings.EndpointsFor(email).JMAP; endpoint != "" {
+		client.SetSessionURL(endpoint)
+	}
+	return client.GetSession(ctx)
+}
+
+// runAuthAddScoped stores token as the account's token for scope, recording
+// the capabilities its session reports. A mismatch between the named scope
+// and what the session allows is a warning, not an error: Fastmail does not
+// report every restriction (e.g. per-mailbox access) in the session.
+func runAuthAddScoped(cmd *cobra.Command, app *App, email, scopeName, token string) error {
+	ctx := cmd.Context()
+
+	scope, err := jmap.ParseTokenScope(scopeName)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+
+	session, err := scopedSession(ctx, email, token)
+	if err != nil {
+		return cerrors.WithContext(err, "checking token")
+	}
+
+	capabilities := make([]string, 0, len(session.Capabilities))
+	for capability := range session.Capabilities {
+		capabilities = append(capabilities, capability)
+	}
+	sort.Strings(capabilities)
+	readOnly := session.Accounts[session.AccountID].IsReadOnly
+	reported := jmap.SessionScope(session)
+
+	if err := config.SaveScopedToken(email, scope.String(), config.ScopedToken{
+		APIToken:     token,
+		Capabilities: capabilities,
+		ReadOnly:     readOnly,
+	}); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+
+	if app.IsJSON(ctx) {
+		return app.PrintJSON(cmd, map[string]any{
+			"saved":         true,
+			"email":         email,
+			"scope":         scope.String(),
+			"sessionScope":  reported.String(),
+			"readOnly":      readOnly,
+			"capabilities":  capabilities,
+			"scopeMismatch": reported != scope,
+		})
+	}
+
+	fmt.Fprintf(os.Stderr, "Saved %s token for %s\n", scope, email)
+	switch {
+	case reported < scope:
+		fmt.Fprintf(os.Stderr, "Warning: the session only allows %s access with this token; %s operations will fail.\n", reported, scope)
+	case reported > scope:
+		fmt.Fprintf(os.Stderr, "Warning: this token allows %s access; it will only be used for %s operations.\n", reported, scope)
+	}
+	return nil
+}
+
+// clientScopedTokens converts stored scoped tokens for jmap.Client, skipping
+// scope names this version does not know.
+func clientScopedTokens(scoped map[string]string) map[jmap.TokenScope]string {
+	if len(scoped) == 0 {
+		return nil
+	}
+	tokens := make(map[jmap.TokenScope]string, len(scoped))
+	for name, token := range scoped {
+		if scope, err := jmap.ParseTokenScope(name); err == nil {
+			tokens[scope] = token
+		}
+	}
+	return tokens
+}
+
+// tokenScopeStatus describes one of an account's tokens for auth status.
+type tokenScopeStatus struct {
+	Scope        string   `json:"scope"`
+	ReadOnly     bool     `json:"readOnly"`
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// scopedTokenStatuses lists an account's scoped tokens, least privileged
+// first.
+func scopedTokenStatuses(tok config.Token) []tokenScopeStatus {
+	statuses := make([]tokenScopeStatus, 0, len(tok.Scoped))
+	for _, scope := range []jmap.TokenScope{jmap.ScopeRead, jmap.ScopeWrite, jmap.ScopeSend} {
+		scoped, ok := tok.Scoped[scope.String()]
+		if !ok {
+			continue
+		}
+		statuses = append(statuses, tokenScopeStatus{
+			Scope:        scope.String(),
+			ReadOnly:     scoped.ReadOnly,
+			Capabilities: scoped.Capabilities,
+		})
+	}
+	return statuses
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
+)
+
+// useFileKeyring points the credential store at a temporary file keyring.
+func useFileKeyring(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv(config.CredentialsDirEnvVarName, dir)
+	t.Setenv(config.KeyringBackendEnvVarName, "file")
+	t.Setenv(config.KeyringPasswordEnvVarName, "test")
+	t.Setenv(config.ConfigFileEnvVarName, dir+"/config.json")
+	t.Setenv(config.TokenCommandEnvVarName, "")
+}
+
+func TestRunAuthAddScoped(t *testing.T) {
+	useFileKeyring(t)
+
+	old := scopedSession
+	t.Cleanup(func() { scopedSession = old })
+	scopedSession = func(_ context.Context, _, token string) (*jmap.Session, error) {
+		if token != "ro-token" {
+			t.Errorf("token = %q", token)
+		}
+		return &jmap.Session{
+			AccountID:    "u1",
+			Accounts:     map[string]jmap.SessionAccount{"u1": {IsReadOnly: true}},
+			Capabilities: map[string]any{jmap.CapabilityMail: map[string]any{}, jmap.CapabilityCore: map[string]any{}},
+		}, nil
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.WithValue(context.Background(), outputModeKey, outfmt.JSON))
+
+	out := captureStdout(t, func() {
+		if err := runAuthAddScoped(cmd, newTestApp(), "user@example.com", "read", "ro-token"); err != nil {
+			t.Fatalf("runAuthAddScoped: %v", err)
+		}
+	})
+
+	var got struct {
+		Scope         string   `json:"scope"`
+		SessionScope  string   `json:"sessionScope"`
+		ReadOnly      bool     `json:"readOnly"`
+		Capabilities  []string `json:"capabilities"`
+		ScopeMismatch bool     `json:"scopeMismatch"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output %q: %v", out, err)
+	}
+	if got.Scope != "read" || got.SessionScope != "read" || !got.ReadOnly || got.ScopeMismatch || len(got.Capabilities) != 2 {
+		t.Errorf("output = %+v", got)
+	}
+
+	creds, err := config.GetCredentials("user@example.com")
+	if err != nil {
+		t.Fatalf("GetCredentials: %v", err)
+	}
+	if creds.Scoped["read"] != "ro-token" {
+		t.Errorf("scoped = %v", creds.Scoped)
+	}
+
+	tokens := clientScopedTokens(creds.Scoped)
+	if token, err := jmap.SelectToken(creds.Token, tokens, jmap.ScopeWrite); !errors.Is(err, jmap.ErrTokenScope) || ExitCode(err) != ExitAuth {
+		t.Errorf("SelectToken(write) = %q, %v; want token scope error with auth exit code", token, err)
+	}
+}
+
+func TestRunAuthAddScoped_RejectsUnknownScope(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	err := runAuthAddScoped(cmd, newTestApp(), "user@example.com", "admin", "tok")
+	if !errors.Is(err, ErrUsage) {
+		t.Fatalf("error = %v, want usage error", err)
+	}
+}
@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
 )
 
 func TestAuthLogin_NoBrowserFlagAvailable(t *testing.T) {
@@ -74,3 +79,27 @@ func TestCheckCredentialAge(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthAddRemove_RefuseTokenCommandAccounts(t *testing.T) {
+	useFileKeyring(t)
+	marker := filepath.Join(t.TempDir(), "ran")
+	// A get-only helper: if it were run for store or erase it would exit 0
+	// and the token would seem saved or removed.
+	t.Setenv(config.TokenCommandEnvVarName, "touch "+marker+"; echo secret")
+	t.Setenv("FASTMAIL_TOKEN", "new-token")
+
+	for _, args := range [][]string{
+		{"auth", "add", "me@example.com"},
+		{"auth", "remove", "me@example.com"},
+	} {
+		captureStderr(t, func() {
+			err := Execute(args)
+			if !errors.Is(err, ErrUsage) || !errors.Is(err, config.ErrTokenCommand) {
+				t.Errorf("%v: err = %v, want token_command usage error", args, err)
+			}
+		})
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("token_command was run: %v", err)
+	}
+}
@@ -7,22 +7,28 @@ import (
 	"os"
 	"strings"
 
-	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
 	"github.com/spf13/cobra"
 )
 
 const defaultBulkBatchSize = 50
 
 type bulkInputOptions struct {
-	IDsFile   string
-	FromStdin bool
-	BatchSize int
+	IDsFile     string
+	FromStdin   bool
+	BatchSize   int
+	Concurrency int
+}
+
+// batchOptions returns the batching settings from the bulk input flags.
+func (o bulkInputOptions) batchOptions() bulkBatchOptions {
+	return bulkBatchOptions{BatchSize: o.BatchSize, Concurrency: o.Concurrency}
 }
 
 func addBulkInputFlags(cmd *cobra.Command, opts *bulkInputOptions) {
 	cmd.Flags().BoolVar(&opts.FromStdin, "stdin", false, "Read whitespace-delimited email IDs from stdin")
 	cmd.Flags().StringVar(&opts.IDsFile, "ids-file", "", "Read whitespace-delimited email IDs from file")
 	cmd.Flags().IntVar(&opts.BatchSize, "batch-size", defaultBulkBatchSize, "Email IDs per API request")
+	cmd.Flags().IntVar(&opts.Concurrency, "concurrency", defaultBulkConcurrency, "Batches to run in parallel")
 }
 
 func validateBulkInputArgs(cmd *cobra.Command, args []string) error {
@@ -77,38 +83,6 @@ func collectBulkIDs(args []string, opts bulkInputOptions) ([]string, error) {
 	return ids, nil
 }
 
-func runBulkInBatches(ids []string, batchSize int, opLabel string, op func(batch []string) (*jmap.BulkResult, error)) (*jmap.BulkResult, int, error) {
-	if batchSize <= 0 {
-		return nil, 0, fmt.Errorf("%w: --batch-size must be greater than 0", ErrUsage)
-	}
-
-	totalBatches := (len(ids) + batchSize - 1) / batchSize
-	merged := &jmap.BulkResult{
-		Succeeded: make([]string, 0, len(ids)),
-		Failed:    make(map[string]string),
-	}
-
-	for start := 0; start < len(ids); start += batchSize {
-		end := min(start+batchSize, len(ids))
-		batchNum := (start / batchSize) + 1
-
-		result, err := op(ids[start:end])
-		if err != nil {
-			return nil, totalBatches, fmt.Errorf("%s batch %d/%d: %w", opLabel, batchNum, totalBatches, err)
-		}
-		if result == nil {
-			return nil, totalBatches, fmt.Errorf("%s batch %d/%d: empty result", opLabel, batchNum, totalBatches)
-		}
-
-		merged.Succeeded = append(merged.Succeeded, result.Succeeded...)
-		for id, msg := range result.Failed {
-			merged.Failed[id] = msg
-		}
-	}
-
-	return merged, totalBatches, nil
-}
-
 func readIDsFromFile(path string) ([]string, error) {
 	f, err := os.Open(path)
 	if err != nil {
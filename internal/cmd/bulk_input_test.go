@@ -5,7 +5,6 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
 	"github.com/spf13/cobra"
 )
 
@@ -80,54 +79,3 @@ func TestCollectBulkIDs_FromAllSources(t *testing.T) {
 		}
 	}
 }
-
-func TestRunBulkInBatches(t *testing.T) {
-	t.Run("merges results across batches", func(t *testing.T) {
-		var calls int
-		result, batches, err := runBulkInBatches([]string{"id1", "id2", "id3"}, 2, "moving emails", func(batch []string) (*jmap.BulkResult, error) {
-			calls++
-			switch calls {
-			case 1:
-				return &jmap.BulkResult{
-					Succeeded: []string{"id1"},
-					Failed:    map[string]string{"id2": "notFound"},
-				}, nil
-			case 2:
-				return &jmap.BulkResult{
-					Succeeded: []string{"id3"},
-					Failed:    map[string]string{},
-				}, nil
-			default:
-				t.Fatalf("unexpected extra batch call %d", calls)
-				return nil, nil
-			}
-		})
-		if err != nil {
-			t.Fatalf("runBulkInBatches unexpected error: %v", err)
-		}
-		if batches != 2 {
-			t.Fatalf("batches=%d, want 2", batches)
-		}
-		if calls != 2 {
-			t.Fatalf("calls=%d, want 2", calls)
-		}
-		if len(result.Succeeded) != 2 {
-			t.Fatalf("succeeded=%v, want 2 entries", result.Succeeded)
-		}
-		if len(result.Failed) != 1 || result.Failed["id2"] == "" {
-			t.Fatalf("failed=%v, want id2 failure", result.Failed)
-		}
-	})
-
-	t.Run("rejects invalid batch size", func(t *testing.T) {
-		_, _, err := runBulkInBatches([]string{"id1"}, 0, "moving emails", func(batch []string) (*jmap.BulkResult, error) {
-			return &jmap.BulkResult{}, nil
-		})
-		if err == nil {
-			t.Fatal("expected batch size error")
-		}
-		if !strings.Contains(err.Error(), "--batch-size") {
-			t.Fatalf("unexpected error: %v", err)
-		}
-	})
-}
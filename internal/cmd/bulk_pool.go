@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"golang.org/x/term"
+)
+
+const (
+	defaultBulkConcurrency = 1
+
+	// bulkRateLimitRetries is how many times a batch is retried after the
+	// client has already given up on a 429.
+	bulkRateLimitRetries = 5
+
+	// Bounds for the spacing between batch requests while throttled.
+	bulkMinSpacing = 250 * time.Millisecond
+	bulkMaxSpacing = 10 * time.Second
+)
+
+// bulkBatchOptions controls how runBulkInBatches splits and schedules work.
+type bulkBatchOptions struct {
+	BatchSize   int
+	Concurrency int
+}
+
+// runBulkInBatches splits ids into batches and runs op on up to
+// opts.Concurrency batches at a time. Workers share a rate limiter that backs
+// off when the server returns 429s. Results are merged in batch order; the
+// first batch error stops the remaining work.
+func runBulkInBatches(ctx context.Context, ids []string, opts bulkBatchOptions, opLabel string, op func(batch []string) (*jmap.BulkResult, error)) (*jmap.BulkResult, int, error) {
+	if opts.BatchSize <= 0 {
+		return nil, 0, fmt.Errorf("%w: --batch-size must be greater than 0", ErrUsage)
+	}
+	if opts.Concurrency <= 0 {
+		return nil, 0, fmt.Errorf("%w: --concurrency must be greater than 0", ErrUsage)
+	}
+	concurrency := opts.Concurrency
+
+	batchSize := opts.BatchSize
+	totalBatches := (len(ids) + batchSize - 1) / batchSize
+	batchIDs := func(n int) []string {
+		return ids[n*batchSize : min((n+1)*batchSize, len(ids))]
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	limiter := &bulkRateLimiter{}
+	progress := newBulkProgress(opLabel, totalBatches, len(ids))
+	results := make([]*jmap.BulkResult, totalBatches)
+
+	var (
+		errMu    sync.Mutex
+		firstErr error
+	)
+	fail := func(err error) {
+		errMu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		errMu.Unlock()
+		cancel()
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, totalBatches) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range jobs {
+				batch := batchIDs(n)
+				result, err := runBulkBatch(ctx, limiter, batch, op)
+				if err == nil && result == nil {
+					err = errors.New("empty result")
+				}
+				if err != nil {
+					fail(fmt.Errorf("%s batch %d/%d: %w", opLabel, n+1, totalBatches, err))
+					continue
+				}
+				results[n] = result
+				progress.add(len(batch))
+			}
+		}()
+	}
+
+feed:
+	for n := range totalBatches {
+		select {
+		case jobs <- n:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	progress.finish()
+
+	if firstErr != nil {
+		return nil, totalBatches, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, totalBatches, err
+	}
+
+	merged := &jmap.BulkResult{
+		Succeeded: make([]string, 0, len(ids)),
+		Failed:    make(map[string]string),
+	}
+	for _, result := range results {
+		merged.Succeeded = append(merged.Succeeded, result.Succeeded...)
+		for id, msg := range result.Failed {
+			merged.Failed[id] = msg
+		}
+	}
+
+	return merged, totalBatches, nil
+}
+
+// runBulkBatch runs op for one batch, waiting for the shared limiter and
+// retrying when the server is still rate limiting.
+func runBulkBatch(ctx context.Context, limiter *bulkRateLimiter, batch []string, op func(batch []string) (*jmap.BulkResult, error)) (*jmap.BulkResult, error) {
+	for attempt := 0; ; attempt++ {
+		if err := limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		result, err := op(batch)
+
+		var rateErr *jmap.RateLimitError
+		if errors.As(err, &rateErr) && attempt < bulkRateLimitRetries {
+			limiter.backoff(rateErr.RetryAfter)
+			continue
+		}
+		if err == nil {
+			limiter.recover()
+		}
+		return result, err
+	}
+}
+
+// bulkRateLimiter spaces out batch requests across all workers. It starts
+// unthrottled; a rate limit pauses every worker for the server's Retry-After
+// and widens the spacing, which then shrinks again as requests succeed.
+type bulkRateLimiter struct {
+	mu      sync.Mutex
+	spacing time.Duration
+	next    time.Time
+}
+
+func (l *bulkRateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.spacing)
+	l.mu.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// backoff pauses all workers for retryAfter (from transport.RetryDelay via
+// jmap.RateLimitError) and doubles the spacing between requests.
+func (l *bulkRateLimiter) backoff(retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.spacing = min(max(l.spacing*2, bulkMinSpacing), bulkMaxSpacing)
+	if resume := time.Now().Add(retryAfter); resume.After(l.next) {
+		l.next = resume
+	}
+}
+
+// recover shrinks the spacing after a successful request.
+func (l *bulkRateLimiter) recover() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.spacing == 0 {
+		return
+	}
+	l.spacing = l.spacing * 3 / 4
+	if l.spacing < bulkMinSpacing {
+		l.spacing = 0
+	}
+}
+
+// bulkProgress writes a live progress line to stderr when it is a terminal
+// and there is more than one batch.
+type bulkProgress struct {
+	mu           sync.Mutex
+	w            io.Writer
+	label        string
+	totalBatches int
+	totalItems   int
+	batches      int
+	items        int
+}
+
+func newBulkProgress(label string, totalBatches, totalItems int) *bulkProgress {
+	p := &bulkProgress{label: label, totalBatches: totalBatches, totalItems: totalItems}
+	if totalBatches > 1 && term.IsTerminal(int(os.Stderr.Fd())) {
+		p.w = os.Stderr
+	}
+	return p
+}
+
+func (p *bulkProgress) add(items int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.batches++
+	p.items += items
+	if p.w != nil {
+		fmt.Fprintf(p.w, "\r%s: %d/%d emails (batch %d/%d)", p.label, p.items, p.totalItems, p.batches, p.totalBatches)
+	}
+}
+
+// finish clears the progress line so the final summary starts on a clean line.
+func (p *bulkProgress) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.w != nil && p.batches > 0 {
+		fmt.Fprint(p.w, "\r\033[K")
+	}
+}
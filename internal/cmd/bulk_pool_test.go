@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+)
+
+func TestRunBulkInBatches(t *testing.T) {
+	t.Run("merges results across batches", func(t *testing.T) {
+		var calls int
+		result, batches, err := runBulkInBatches(context.Background(), []string{"id1", "id2", "id3"}, bulkBatchOptions{BatchSize: 2, Concurrency: 1}, "moving emails", func(batch []string) (*jmap.BulkResult, error) {
+			calls++
+			switch calls {
+			case 1:
+				return &jmap.BulkResult{
+					Succeeded: []string{"id1"},
+					Failed:    map[string]string{"id2": "notFound"},
+				}, nil
+			case 2:
+				return &jmap.BulkResult{
+					Succeeded: []string{"id3"},
+					Failed:    map[string]string{},
+				}, nil
+			default:
+				t.Fatalf("unexpected extra batch call %d", calls)
+				return nil, nil
+			}
+		})
+		if err != nil {
+			t.Fatalf("runBulkInBatches unexpected error: %v", err)
+		}
+		if batches != 2 {
+			t.Fatalf("batches=%d, want 2", batches)
+		}
+		if calls != 2 {
+			t.Fatalf("calls=%d, want 2", calls)
+		}
+		if len(result.Succeeded) != 2 {
+			t.Fatalf("succeeded=%v, want 2 entries", result.Succeeded)
+		}
+		if len(result.Failed) != 1 || result.Failed["id2"] == "" {
+			t.Fatalf("failed=%v, want id2 failure", result.Failed)
+		}
+	})
+
+	t.Run("rejects invalid batch size", func(t *testing.T) {
+		_, _, err := runBulkInBatches(context.Background(), []string{"id1"}, bulkBatchOptions{}, "moving emails", func(batch []string) (*jmap.BulkResult, error) {
+			return &jmap.BulkResult{}, nil
+		})
+		if err == nil {
+			t.Fatal("expected batch size error")
+		}
+		if !strings.Contains(err.Error(), "--batch-size") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rejects invalid concurrency", func(t *testing.T) {
+		for _, concurrency := range []int{0, -1} {
+			_, _, err := runBulkInBatches(context.Background(), []string{"id1"}, bulkBatchOptions{BatchSize: 1, Concurrency: concurrency}, "moving emails", func(batch []string) (*jmap.BulkResult, error) {
+				return &jmap.BulkResult{}, nil
+			})
+			if !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "--concurrency") {
+				t.Fatalf("concurrency %d: unexpected error: %v", concurrency, err)
+			}
+		}
+	})
+
+	t.Run("runs batches concurrently and merges in order", func(t *testing.T) {
+		ids := make([]string, 20)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("id%02d", i)
+		}
+
+		var inFlight, peak atomic.Int32
+		result, batches, err := runBulkInBatches(context.Background(), ids, bulkBatchOptions{BatchSize: 2, Concurrency: 4}, "deleting emails", func(batch []string) (*jmap.BulkResult, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return &jmap.BulkResult{Succeeded: batch, Failed: map[string]string{}}, nil
+		})
+		if err != nil {
+			t.Fatalf("runBulkInBatches unexpected error: %v", err)
+		}
+		if batches != 10 {
+			t.Fatalf("batches=%d, want 10", batches)
+		}
+		if p := peak.Load(); p < 2 || p > 4 {
+			t.Fatalf("peak concurrency=%d, want 2..4", p)
+		}
+		for i, id := range result.Succeeded {
+			if id != ids[i] {
+				t.Fatalf("succeeded out of order: %v", result.Succeeded)
+			}
+		}
+	})
+
+	t.Run("retries rate limited batches", func(t *testing.T) {
+		var calls atomic.Int32
+		result, _, err := runBulkInBatches(context.Background(), []string{"id1", "id2"}, bulkBatchOptions{BatchSize: 1, Concurrency: 2}, "marking emails", func(batch []string) (*jmap.BulkResult, error) {
+			if calls.Add(1) == 1 {
+				return nil, &jmap.RateLimitError{RetryAfter: time.Millisecond}
+			}
+			return &jmap.BulkResult{Succeeded: batch}, nil
+		})
+		if err != nil {
+			t.Fatalf("runBulkInBatches unexpected error: %v", err)
+		}
+		if len(result.Succeeded) != 2 || calls.Load() != 3 {
+			t.Fatalf("succeeded=%v calls=%d, want 2 succeeded after 3 calls", result.Succeeded, calls.Load())
+		}
+	})
+
+	t.Run("stops on first error", func(t *testing.T) {
+		ids := []string{"id1", "id2", "id3", "id4", "id5", "id6"}
+		var calls atomic.Int32
+		_, _, err := runBulkInBatches(context.Background(), ids, bulkBatchOptions{BatchSize: 1, Concurrency: 1}, "moving emails", func(batch []string) (*jmap.BulkResult, error) {
+			calls.Add(1)
+			if batch[0] == "id2" {
+				return nil, errors.New("server error")
+			}
+			return &jmap.BulkResult{Succeeded: batch}, nil
+		})
+		if err == nil || !strings.Contains(err.Error(), "moving emails batch 2/6: server error") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls.Load() != 2 {
+			t.Fatalf("calls=%d, want remaining batches skipped", calls.Load())
+		}
+	})
+}
+
+func TestBulkRateLimiter(t *testing.T) {
+	l := &bulkRateLimiter{}
+
+	start := time.Now()
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatalf("wait error: %v", err)
+	}
+	if time.Since(start) > 50*time.Millisecond {
+		t.Fatal("unthrottled limiter should not wait")
+	}
+
+	l.backoff(20 * time.Millisecond)
+	if l.spacing != bulkMinSpacing {
+		t.Fatalf("spacing=%v, want %v after first backoff", l.spacing, bulkMinSpacing)
+	}
+	l.backoff(0)
+	if l.spacing != 2*bulkMinSpacing {
+		t.Fatalf("spacing=%v, want doubled", l.spacing)
+	}
+
+	for range 10 {
+		l.recover()
+	}
+	if l.spacing != 0 {
+		t.Fatalf("spacing=%v, want 0 after recovering", l.spacing)
+	}
+
+	l.backoff(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("wait error=%v, want context.Canceled", err)
+	}
+}
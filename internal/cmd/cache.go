@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/cache"
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newCacheCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the on-disk session and mailbox cache",
+		Long: `Commands reuse the JMAP session and mailbox list from earlier runs, stored
+per account in the cache directory (override with FASTMAIL_CACHE_DIR).
+
+The session is refetched after an hour or when the server reports a new
+session state; mailboxes are revalidated with Mailbox/changes on every use.
+Pass --no-cache to bypass the cache for a single command.`,
+	}
+
+	cmd.AddCommand(newCacheClearCmd(app))
+
+	return cmd
+}
+
+func newCacheClearCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Delete all cached sessions and mailbox lists",
+		Args:  cobra.NoArgs,
+		RunE: runE(app, func(cmd *cobra.Command, _ []string, app *App) error {
+			dir := config.CacheDir()
+			if err := cache.Clear(); err != nil {
+				return err
+			}
+
+			if app.IsJSON(cmd.Context()) {
+				return app.PrintJSON(cmd, map[string]any{
+					"cleared": true,
+					"path":    dir,
+				})
+			}
+
+			fmt.Printf("Cleared cache at %s\n", dir)
+			return nil
+		}),
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+)
+
+func TestCacheClear_RemovesAccountEntries(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	t.Setenv(config.CacheDirEnvVarName, dir)
+
+	entry := filepath.Join(dir, "me@example.com", "session.json")
+	if err := os.MkdirAll(filepath.Dir(entry), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(entry, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newCacheClearCmd(newTestApp())
+	cmd.SetContext(context.Background())
+	out := captureStdout(t, func() {
+		if err := cmd.RunE(cmd, nil); err != nil {
+			t.Fatalf("cache clear error: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "Cleared cache at "+dir) {
+		t.Errorf("output = %q", out)
+	}
+	if _, err := os.Stat(filepath.Dir(entry)); !os.IsNotExist(err) {
+		t.Errorf("account cache dir still exists: %v", err)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("cache dir itself should be kept: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
@@ -120,14 +121,11 @@ or relative expressions like yesterday, 2h ago, or monday.`,
 		Example: `  fastmail calendar events
   fastmail calendar events --calendar <id>
   fastmail calendar events --from 2025-12-01 --to 2025-12-31
-  fastmail calendar events --limit 50`,
+  fastmail calendar events --limit 50
+  fastmail calendar events --account all --from 2025-12-01 --to 2025-12-31`,
 		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
-			client, err := app.JMAPClient()
-			if err != nil {
-				return err
-			}
-
 			var from, to time.Time
+			var err error
 			if fromDate != "" {
 				from, err = parseDateTime(fromDate)
 				if err != nil {
@@ -141,6 +139,19 @@ or relative expressions like yesterday, 2h ago, or monday.`,
 				}
 			}
 
+			accounts, multi, err := app.MultiAccounts()
+			if err != nil {
+				return err
+			}
+			if multi {
+				return runCalendarEventsAccounts(cmd, app, accounts, calendarID, from, to, limit, light)
+			}
+
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+
 			events, err := client.GetEvents(cmd.Context(), calendarID, from, to, limit)
 			if err != nil {
 				return fmt.Errorf("failed to list events: %w", err)
@@ -191,6 +202,66 @@ or relative expressions like yesterday, 2h ago, or monday.`,
 	return cmd
 }
 
+// runCalendarEventsAccounts lists events across accounts sorted by start time,
+// tagged by account.
+func runCalendarEventsAccounts(cmd *cobra.Command, app *App, accounts []string, calendarID string, from, to time.Time, limit int, light bool) error {
+	ctx := cmd.Context()
+
+	results, errs := fanOut(ctx, accounts, app.JMAPClientFor, func(ctx context.Context, client *jmap.Client) ([]jmap.CalendarEvent, error) {
+		return client.GetEvents(ctx, calendarID, from, to, limit)
+	})
+	if err := checkAccountErrors(ctx, app, accounts, errs); err != nil {
+		return err
+	}
+
+	type row struct {
+		account string
+		event   jmap.CalendarEvent
+	}
+	var rows []row
+	for _, r := range results {
+		for _, event := range r.Value {
+			rows = append(rows, row{account: r.Account, event: event})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].event.Start.Before(rows[j].event.Start)
+	})
+
+	if app.IsJSON(ctx) {
+		tagged := make([]map[string]any, len(rows))
+		for i, r := range rows {
+			if light {
+				tagged[i] = withAccount(r.account, eventToLight(r.event))
+			} else {
+				tagged[i] = withAccount(r.account, r.event)
+			}
+		}
+		return app.PrintJSON(cmd, multiAccountJSON("events", tagged, errs))
+	}
+
+	if len(rows) == 0 {
+		printNoResults("No events found")
+		return nil
+	}
+
+	tw := outfmt.NewTabWriter()
+	_, _ = fmt.Fprintln(tw, "ACCOUNT\tID\tTITLE\tSTART\tEND\tSTATUS") //nolint:errcheck
+	for _, r := range rows {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", //nolint:errcheck
+			outfmt.SanitizeTab(r.account),
+			r.event.ID,
+			outfmt.SanitizeTab(r.event.Title),
+			formatEventTime(r.event.Start, r.event.IsAllDay),
+			formatEventTime(r.event.End, r.event.IsAllDay),
+			r.event.Status,
+		)
+	}
+	_ = tw.Flush() //nolint:errcheck
+
+	return nil
+}
+
 func newCalendarEventGetCmd(app *App) *cobra.Command {
 	var light bool
 
@@ -590,8 +661,15 @@ Times can be in RFC3339 format (2025-12-19T15:00:00Z) or simplified format (2025
 				return fmt.Errorf("failed to get token for %s: %w", account, err)
 			}
 
+			endpoints, err := app.Endpoints(account)
+			if err != nil {
+				return err
+			}
+
 			// Create CalDAV client
-			caldavClient := caldav.NewClient(caldav.DefaultBaseURL, account, token)
+			caldavClient := caldav.NewClient(endpoints.CalDAV, account, token)
+			caldavClient.SetReadOnly(app.ReadOnly())
+			caldavClient.SetHTTPClient(app.auditedHTTPClient(account))
 
 			// Build attendee list
 			var attendeeList []caldav.Attendee
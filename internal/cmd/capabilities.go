@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/caldav"
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
+	"github.com/salmonumbrella/fastmail-cli/internal/format"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
+	"github.com/salmonumbrella/fastmail-cli/internal/webdav"
+	"github.com/spf13/cobra"
+)
+
+// probeTimeout bounds each WebDAV/CalDAV reachability probe.
+const probeTimeout = 15 * time.Second
+
+// capabilityReport describes what the current login can do.
+type capabilityReport struct {
+	Account      string           `json:"account,omitempty"`
+	AccountID    string           `json:"accountId"`
+	Features     []featureStatus  `json:"features"`
+	Limits       map[string]int64 `json:"limits"`
+	Capabilities []string         `json:"capabilities"`
+}
+
+// featureStatus reports whether a command group can be used.
+type featureStatus struct {
+	Group     string       `json:"group"`
+	Available bool         `json:"available"`
+	Skipped   bool         `json:"skipped,omitempty"`
+	Via       string       `json:"via"`
+	Requires  []string     `json:"requires,omitempty"`
+	Missing   []string     `json:"missing,omitempty"`
+	Probe     *probeStatus `json:"probe,omitempty"`
+	Note      string       `json:"note,omitempty"`
+}
+
+// probeStatus is the result of checking a non-JMAP endpoint.
+type probeStatus struct {
+	URL       string `json:"url"`
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+}
+
+// capabilityProbes holds the checks that go beyond the JMAP session. A nil
+// probe is reported as skipped.
+type capabilityProbes struct {
+	files     func(context.Context) error
+	filesURL  string
+	caldav    func(context.Context) error
+	caldavURL string
+	sieve     bool
+}
+
+// coreLimitKeys and mailLimitKeys are the server limits shown in reports.
+var (
+	coreLimitKeys = []string{
+		"maxSizeUpload", "maxConcurrentUpload", "maxSizeRequest", "maxConcurrentRequests",
+		"maxCallsInRequest", "maxObjectsInGet", "maxObjectsInSet",
+	}
+	mailLimitKeys = []string{
+		"maxMailboxesPerEmail", "maxMailboxDepth", "maxSizeMailboxName", "maxSizeAttachmentsPerEmail",
+	}
+)
+
+func newCapabilitiesCmd(app *App) *cobra.Command {
+	var noProbe bool
+
+	cmd := &cobra.Command{
+		Use:     "capabilities",
+		Aliases: []string{"caps"},
+		Short:   "Show which features and limits the current login supports",
+		Long: `Show which command groups the current login can use, based on the JMAP
+session's capabilities, plus server limits such as maxSizeUpload and
+maxObjectsInSet.
+
+Files (WebDAV) and calendar invitations (CalDAV) are not part of JMAP, so
+their endpoints are probed; use --no-probe to skip the network checks. Sieve
+is available once browser credentials are stored with 'fastmail sieve auth'.`,
+		Example: `  fastmail capabilities
+  fastmail capabilities --no-probe
+  fastmail capabilities --output json --query '.features[] | select(.available) | .group'`,
+		Args: cobra.NoArgs,
+		RunE: runE(app, func(cmd *cobra.Command, _ []string, app *App) error {
+			account, err := app.RequireAccount()
+			if err != nil {
+				return err
+			}
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+
+			probes := capabilityProbes{sieve: config.HasSieveCredentials(account)}
+			if !noProbe {
+				token, err := config.GetToken(account)
+				if err != nil {
+					return fmt.Errorf("failed to get token for %s: %w", account, err)
+				}
+				endpoints, err := app.Endpoints(account)
+				if err != nil {
+					return err
+				}
+				probes.files = webdav.NewClientWithBaseURL(token, endpoints.WebDAV).Ping
+				probes.filesURL = endpoints.WebDAV
+				probes.caldav = caldav.NewClient(endpoints.CalDAV, account, token).Ping
+				probes.caldavURL = endpoints.CalDAV
+			}
+
+			return runCapabilities(cmd, app, client, account, probes)
+		}),
+	}
+
+	cmd.Flags().BoolVar(&noProbe, "no-probe", false, "Skip WebDAV/CalDAV reachability checks")
+
+	return cmd
+}
+
+func runCapabilities(cmd *cobra.Command, app *App, client sessionClient, account string, probes capabilityProbes) error {
+	ctx := cmd.Context()
+
+	session, err := client.GetSession(ctx)
+	if err != nil {
+		return cerrors.WithContext(err, "getting session")
+	}
+
+	report := buildCapabilityReport(ctx, session, probes)
+	report.Account = account
+
+	if app.IsJSON(ctx) {
+		return app.PrintJSON(cmd, report)
+	}
+
+	if account != "" {
+		fmt.Printf("Account: %s (JMAP account %s)\n\n", account, report.AccountID)
+	}
+
+	tw := outfmt.NewTabWriter()
+	fmt.Fprintln(tw, "FEATURE\tAVAILABLE\tVIA\tNOTES")
+	for _, f := range report.Features {
+		available := yesNo(f.Available)
+		if f.Skipped {
+			available = "unknown"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", f.Group, available, f.Via, outfmt.SanitizeTab(featureNotes(f)))
+	}
+	tw.Flush()
+
+	if len(report.Limits) > 0 {
+		fmt.Println()
+		fmt.Println("Limits:")
+		tw = outfmt.NewTabWriter()
+		for _, key := range append(append([]string{}, coreLimitKeys...), mailLimitKeys...) {
+			if v, ok := report.Limits[key]; ok {
+				fmt.Fprintf(tw, "  %s:\t%s\n", key, formatLimit(key, v))
+			}
+		}
+		tw.Flush()
+	}
+
+	fmt.Println()
+	fmt.Println("Capabilities:")
+	for _, c := range report.Capabilities {
+		fmt.Printf("  %s\n", c)
+	}
+
+	return nil
+}
+
+// buildCapabilityReport maps the session's capabilities to command groups
+// and runs the non-JMAP probes.
+func buildCapabilityReport(ctx context.Context, session *jmap.Session, probes capabilityProbes) capabilityReport {
+	has := func(capability string) bool {
+		_, ok := session.Capabilities[capability]
+		return ok
+	}
+	jmapFeature := func(group string, required ...string) featureStatus {
+		f := featureStatus{Group: group, Via: "jmap", Requires: required, Available: true}
+		for _, c := range required {
+			if !has(c) {
+				f.Available = false
+				f.Missing = append(f.Missing, c)
+			}
+		}
+		return f
+	}
+
+	email := jmapFeature("email", jmap.CapabilityMail)
+	if email.Available && !has(jmap.CapabilitySubmission) {
+		email.Note = "sending unavailable: token lacks " + jmap.CapabilitySubmission
+	}
+
+	calendar := jmapFeature("calendar", jmap.CapabilityCalendars)
+	if probes.caldav != nil {
+		calendar.Probe = runProbe(ctx, probes.caldavURL, probes.caldav)
+		if !calendar.Probe.Reachable {
+			calendar.Note = "CalDAV unreachable: invitations cannot be sent"
+		}
+	}
+
+	files := featureStatus{Group: "files", Via: "webdav"}
+	if probes.files != nil {
+		files.Probe = runProbe(ctx, probes.filesURL, probes.files)
+		files.Available = files.Probe.Reachable
+	} else {
+		files.Skipped = true
+		files.Note = "not probed"
+	}
+
+	sieve := featureStatus{Group: "sieve", Via: "browser session", Available: probes.sieve}
+	if !probes.sieve {
+		sieve.Note = "run 'fastmail sieve auth' to store browser credentials"
+	}
+
+	report := capabilityReport{
+		AccountID: session.AccountID,
+		Features: []featureStatus{
+			email,
+			jmapFeature("masked", jmap.CapabilityMaskedEmail),
+			calendar,
+			jmapFeature("contacts", jmap.CapabilityContacts),
+			jmapFeature("vacation", jmap.CapabilityVacationResponse),
+			jmapFeature("quota", jmap.CapabilityQuota),
+			files,
+			sieve,
+		},
+		Limits:       sessionLimits(session),
+		Capabilities: make([]string, 0, len(session.Capabilities)),
+	}
+	for c := range session.Capabilities {
+		report.Capabilities = append(report.Capabilities, c)
+	}
+	sort.Strings(report.Capabilities)
+
+	return report
+}
+
+// sessionLimits collects the numeric server limits from the core capability
+// and the selected account's mail capability.
+func sessionLimits(session *jmap.Session) map[string]int64 {
+	limits := map[string]int64{}
+	collect := func(raw any, keys []string) {
+		m, ok := raw.(map[string]any)
+		if !ok {
+			return
+		}
+		for _, key := range keys {
+			if v, ok := m[key].(float64); ok {
+				limits[key] = int64(v)
+			}
+		}
+	}
+
+	collect(session.Capabilities[jmap.CapabilityCore], coreLimitKeys)
+	if acc, ok := session.Accounts[session.AccountID]; ok {
+		collect(acc.AccountCapabilities[jmap.CapabilityMail], mailLimitKeys)
+	}
+	return limits
+}
+
+func runProbe(ctx context.Context, url string, probe func(context.Context) error) *probeStatus {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	status := &probeStatus{URL: url, Reachable: true}
+	if err := probe(ctx); err != nil {
+		status.Reachable = false
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// featureNotes summarises why a feature is (un)available for text output.
+func featureNotes(f featureStatus) string {
+	var notes []string
+	if len(f.Missing) > 0 {
+		short := make([]string, len(f.Missing))
+		for i, c := range f.Missing {
+			short[i] = shortCapability(c)
+		}
+		notes = append(notes, "missing "+strings.Join(short, ", "))
+	}
+	if f.Probe != nil && !f.Probe.Reachable {
+		notes = append(notes, f.Probe.Error)
+	}
+	if f.Note != "" {
+		notes = append(notes, f.Note)
+	}
+	if len(notes) == 0 {
+		return "-"
+	}
+	return strings.Join(notes, "; ")
+}
+
+func formatLimit(key string, v int64) string {
+	if strings.HasPrefix(key, "maxSize") {
+		return format.FormatBytes(v)
+	}
+	return fmt.Sprintf("%d", v)
+}
+
+// printCapabilitySummary prints the compact capability section of
+// 'auth status'.
+func printCapabilitySummary(report capabilityReport) {
+	var available, unavailable []string
+	for _, f := range report.Features {
+		switch {
+		case f.Skipped:
+		case f.Available:
+			available = append(available, f.Group)
+		default:
+			unavailable = append(unavailable, f.Group)
+		}
+	}
+
+	fmt.Println("Capabilities:")
+	if len(available) > 0 {
+		fmt.Printf("  available:   %s\n", strings.Join(available, ", "))
+	}
+	if len(unavailable) > 0 {
+		fmt.Printf("  unavailable: %s\n", strings.Join(unavailable, ", "))
+	}
+	fmt.Println("  Run 'fastmail capabilities' for limits and WebDAV/CalDAV checks.")
+}
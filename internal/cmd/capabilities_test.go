@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
+	"github.com/spf13/cobra"
+)
+
+func capabilityTestSession() *jmap.Session {
+	return &jmap.Session{
+		AccountID: "a1",
+		Accounts: map[string]jmap.SessionAccount{
+			"a1": {AccountCapabilities: map[string]any{
+				jmap.CapabilityMail: map[string]any{"maxMailboxesPerEmail": float64(1000)},
+			}},
+		},
+		Capabilities: map[string]any{
+			jmap.CapabilityCore: map[string]any{
+				"maxSizeUpload":   float64(50000000),
+				"maxObjectsInSet": float64(4096),
+			},
+			jmap.CapabilityMail:        map[string]any{},
+			jmap.CapabilityMaskedEmail: map[string]any{},
+		},
+	}
+}
+
+func findFeature(t *testing.T, report capabilityReport, group string) featureStatus {
+	t.Helper()
+	for _, f := range report.Features {
+		if f.Group == group {
+			return f
+		}
+	}
+	t.Fatalf("feature %q not in report", group)
+	return featureStatus{}
+}
+
+func TestBuildCapabilityReport(t *testing.T) {
+	probes := capabilityProbes{
+		files:  func(context.Context) error { return nil },
+		caldav: func(context.Context) error { return errors.New("401 Unauthorized") },
+	}
+
+	report := buildCapabilityReport(context.Background(), capabilityTestSession(), probes)
+
+	email := findFeature(t, report, "email")
+	if !email.Available || !strings.Contains(email.Note, "sending unavailable") {
+		t.Errorf("email = %+v, want available without sending", email)
+	}
+	if masked := findFeature(t, report, "masked"); !masked.Available {
+		t.Errorf("masked should be available: %+v", masked)
+	}
+	contacts := findFeature(t, report, "contacts")
+	if contacts.Available || len(contacts.Missing) != 1 || contacts.Missing[0] != jmap.CapabilityContacts {
+		t.Errorf("contacts = %+v, want missing contacts capability", contacts)
+	}
+	if files := findFeature(t, report, "files"); !files.Available || files.Probe == nil || !files.Probe.Reachable {
+		t.Errorf("files = %+v, want reachable", files)
+	}
+	calendar := findFeature(t, report, "calendar")
+	if calendar.Available || calendar.Probe == nil || calendar.Probe.Reachable || calendar.Probe.Error != "401 Unauthorized" {
+		t.Errorf("calendar = %+v, want unavailable with failed probe", calendar)
+	}
+	if sieve := findFeature(t, report, "sieve"); sieve.Available {
+		t.Errorf("sieve should need stored credentials: %+v", sieve)
+	}
+
+	if report.Limits["maxSizeUpload"] != 50000000 || report.Limits["maxObjectsInSet"] != 4096 || report.Limits["maxMailboxesPerEmail"] != 1000 {
+		t.Errorf("Limits = %v", report.Limits)
+	}
+	if len(report.Capabilities) != 3 || report.Capabilities[0] != jmap.CapabilityMaskedEmail {
+		t.Errorf("Capabilities should be sorted: %v", report.Capabilities)
+	}
+}
+
+func TestBuildCapabilityReport_NoProbe(t *testing.T) {
+	report := buildCapabilityReport(context.Background(), capabilityTestSession(), capabilityProbes{sieve: true})
+
+	if files := findFeature(t, report, "files"); !files.Skipped || files.Probe != nil {
+		t.Errorf("files should be skipped without a probe: %+v", files)
+	}
+	if calendar := findFeature(t, report, "calendar"); calendar.Probe != nil {
+		t.Errorf("calendar should not be probed: %+v", calendar)
+	}
+	if sieve := findFeature(t, report, "sieve"); !sieve.Available {
+		t.Errorf("sieve should be available with credentials: %+v", sieve)
+	}
+}
+
+func TestRunCapabilities_JSON(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.WithValue(context.Background(), outputModeKey, outfmt.JSON))
+
+	client := &fakeSessionClient{session: capabilityTestSession()}
+	out := captureStdout(t, func() {
+		if err := runCapabilities(cmd, app, client, "me@example.com", capabilityProbes{}); err != nil {
+			t.Fatalf("runCapabilities error: %v", err)
+		}
+	})
+
+	var report capabilityReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if report.Account != "me@example.com" || report.AccountID != "a1" || len(report.Features) != 8 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestRunCapabilities_Text(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeSessionClient{session: capabilityTestSession()}
+	out := captureStdout(t, func() {
+		if err := runCapabilities(cmd, app, client, "me@example.com", capabilityProbes{}); err != nil {
+			t.Fatalf("runCapabilities error: %v", err)
+		}
+	})
+
+	for _, want := range []string{"FEATURE", "missing contacts", "maxSizeUpload:", "maxObjectsInSet:", "4096", "unknown"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -229,6 +229,14 @@ func newDraftSendCmd(app *App) *cobra.Command {
 				return fmt.Errorf("failed to get draft: %w", err)
 			}
 
+			policy, err := app.Policy()
+			if err != nil {
+				return err
+			}
+			if err := policy.checkRecipients(emailRecipients(draft)); err != nil {
+				return err
+			}
+
 			// Confirm before sending. Only print details in interactive mode.
 			if !app.IsJSON(cmd.Context()) && (app.Flags == nil || !app.Flags.Yes) {
 				toAddrs := make([]string, len(draft.To))
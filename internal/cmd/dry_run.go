@@ -1,6 +1,19 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
+)
 
 func printDryRunList(app *App, cmd *cobra.Command, header, key string, items []string, extra map[string]any) error {
 	if app.IsJSON(cmd.Context()) {
@@ -17,3 +30,116 @@ func printDryRunList(app *App, cmd *cobra.Command, header, key string, items []s
 	printList(header, items)
 	return nil
 }
+
+// dryRun reports whether the global --dry-run (or FASTMAIL_DRY_RUN) is set.
+// Commands that can show what they would do without any requests check it
+// before doing any work; everything else goes through the recorder.
+func (a *App) dryRun() bool {
+	return a.Flags != nil && a.Flags.DryRun
+}
+
+// dryRunRecorder returns the recorder that captures write requests under the
+// global --dry-run, or nil without it.
+func (a *App) dryRunRecorder() *transport.Recorder {
+	if !a.dryRun() {
+		return nil
+	}
+	a.recorderOnce.Do(func() {
+		a.recorder = transport.NewRecorder(isWriteRequest, dryRunRespond)
+	})
+	return a.recorder
+}
+
+// dryRunHTTPClient returns an HTTP client that sends reads and records
+// writes, or nil without --dry-run.
+func (a *App) dryRunHTTPClient() *http.Client {
+	recorder := a.dryRunRecorder()
+	if recorder == nil {
+		return nil
+	}
+	return &http.Client{Timeout: 30 * time.Second, Transport: recorder}
+}
+
+// isWriteRequest classifies requests for the dry-run recorder and the audit
+// log. JMAP API calls are all POSTs, so their method calls decide. Any other
+// POST is a blob upload, which counts as a read: it changes nothing the user
+// can see, and reads such as Email/parse need the real blob.
+func isWriteRequest(req *http.Request, body []byte) bool {
+	if req.Method == http.MethodPost {
+		if jmapReq, ok := jmap.ParseRequestBody(body); ok {
+			return jmap.IsWriteRequest(jmapReq)
+		}
+		return false
+	}
+	return transport.IsWriteMethod(req.Method)
+}
+
+// dryRunRespond answers a recorded write as if it had succeeded.
+func dryRunRespond(req *http.Request, body []byte) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodPost:
+		jmapReq, _ := jmap.ParseRequestBody(body)
+		data, err := jmap.DryRunResponse(jmapReq)
+		if err != nil {
+			return nil, err
+		}
+		return transport.NewResponse(req, http.StatusOK, "application/json", data), nil
+	case http.MethodPut, "MKCOL":
+		return transport.NewResponse(req, http.StatusCreated, "", nil), nil
+	default:
+		return transport.NewResponse(req, http.StatusNoContent, "", nil), nil
+	}
+}
+
+// dryRunRequest is a recorded request as printed in JSON mode.
+type dryRunRequest struct {
+	Method      string          `json:"method"`
+	URL         string          `json:"url"`
+	ContentType string          `json:"contentType,omitempty"`
+	Size        int             `json:"size"`
+	Body        json.RawMessage `json:"body,omitempty"`
+}
+
+// printDryRunRequests prints the write requests recorded under --dry-run to
+// w (stderr, so command output on stdout stays parseable).
+func (a *App) printDryRunRequests(w io.Writer) {
+	recorder := a.dryRunRecorder()
+	if recorder == nil {
+		return
+	}
+	requests := recorder.Requests()
+
+	if a.Flags.Output == "json" {
+		// Commands that preview their own changes print a JSON document with
+		// "dryRun": true on stdout; keep stderr empty for them.
+		if len(requests) == 0 {
+			return
+		}
+		out := make([]dryRunRequest, len(requests))
+		for i, r := range requests {
+			out[i] = dryRunRequest{Method: r.Method, URL: r.URL, ContentType: r.ContentType, Size: len(r.Body)}
+			if json.Valid(r.Body) {
+				out[i].Body = r.Body
+			}
+		}
+		_ = outfmt.WriteJSON(w, map[string]any{"dryRun": map[string]any{"requests": out}})
+		return
+	}
+
+	if len(requests) == 0 {
+		fmt.Fprintln(w, "Dry run: no changes would be made")
+		return
+	}
+	fmt.Fprintf(w, "Dry run: %d request(s) not sent:\n", len(requests))
+	for _, r := range requests {
+		fmt.Fprintf(w, "\n%s %s\n", r.Method, r.URL)
+		var pretty bytes.Buffer
+		switch {
+		case len(r.Body) == 0:
+		case json.Indent(&pretty, r.Body, "", "  ") == nil:
+			fmt.Fprintln(w, pretty.String())
+		default:
+			fmt.Fprintf(w, "(%s, %d bytes)\n", r.ContentType, len(r.Body))
+		}
+	}
+}
@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
 	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
 	"github.com/spf13/cobra"
 )
@@ -60,3 +65,212 @@ func TestPrintDryRunList_JSON(t *testing.T) {
 		t.Fatalf("unexpected wouldMove payload: %#v", payload["wouldMove"])
 	}
 }
+
+func TestGlobalDryRun_RecordsWrites(t *testing.T) {
+	var sent []string
+	var apiURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"apiUrl": "` + apiURL + `", "accounts": {"u1": {"name": "me"}}, "primaryAccounts": {"urn:ietf:params:jmap:mail": "u1"}}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		sent = append(sent, string(body))
+		_, _ = w.Write([]byte(`{"methodResponses": [["Mailbox/get", {"list": []}, "0"]], "sessionState": ""}`))
+	}))
+	defer server.Close()
+	apiURL = server.URL + "/api"
+
+	app := &App{Flags: &rootFlags{DryRun: true, Output: "json"}}
+	client := jmap.NewClientWithBaseURL("token", server.URL)
+	client.SetHTTPClient(app.dryRunHTTPClient())
+
+	ctx := context.Background()
+	if _, err := client.GetMailboxes(ctx); err != nil {
+		t.Fatalf("GetMailboxes: %v", err)
+	}
+	result, err := client.MoveEmails(ctx, []string{"e1", "e2"}, "archive")
+	if err != nil {
+		t.Fatalf("MoveEmails: %v", err)
+	}
+	if len(result.Succeeded) != 2 {
+		t.Errorf("succeeded = %v, want both reported as moved", result.Succeeded)
+	}
+	if len(sent) != 1 || !strings.Contains(sent[0], "Mailbox/get") {
+		t.Fatalf("server received %v, want only the read", sent)
+	}
+
+	var out bytes.Buffer
+	app.printDryRunRequests(&out)
+	var printed struct {
+		DryRun struct {
+			Requests []struct {
+				Method string          `json:"method"`
+				URL    string          `json:"url"`
+				Body   json.RawMessage `json:"body"`
+			} `json:"requests"`
+		} `json:"dryRun"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &printed); err != nil {
+		t.Fatalf("output %q: %v", out.String(), err)
+	}
+	reqs := printed.DryRun.Requests
+	if len(reqs) != 1 || reqs[0].Method != http.MethodPost || reqs[0].URL != apiURL || !strings.Contains(string(reqs[0].Body), `"Email/set"`) {
+		t.Errorf("recorded = %+v", reqs)
+	}
+}
+
+func TestGlobalDryRun_Disabled(t *testing.T) {
+	app := newTestApp()
+	if app.dryRunHTTPClient() != nil {
+		t.Error("dryRunHTTPClient should be nil without --dry-run")
+	}
+	var out bytes.Buffer
+	app.printDryRunRequests(&out)
+	if out.Len() != 0 {
+		t.Errorf("printed %q without --dry-run", out.String())
+	}
+}
+
+// newDryRunServer starts a JMAP server for dry-run tests. Each bearer token
+// in accounts gets its own session for that account ID, with access to all of
+// them. Uploads get blob ID "uploaded", read calls are answered by read, and
+// any write that reaches the server fails the test.
+func newDryRunServer(t *testing.T, accounts map[string]string, read func(method string, args map[string]any) map[string]any) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accountID := accounts[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+		if r.Method == http.MethodGet {
+			all := map[string]any{}
+			for _, id := range accounts {
+				all[id] = map[string]any{"name": id}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"apiUrl":          server.URL + "/api",
+				"uploadUrl":       server.URL + "/upload/{accountId}/",
+				"downloadUrl":     server.URL + "/download/{accountId}/{blobId}/{name}",
+				"accounts":        all,
+				"primaryAccounts": map[string]string{jmap.CapabilityMail: accountID},
+			})
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if strings.HasPrefix(r.URL.Path, "/upload/") {
+			_ = json.NewEncoder(w).Encode(map[string]any{"accountId": accountID, "blobId": "uploaded", "type": r.Header.Get("Content-Type"), "size": len(body)})
+			return
+		}
+		req, ok := jmap.ParseRequestBody(body)
+		if !ok {
+			t.Errorf("unexpected non-JMAP request to %s", r.URL.Path)
+			http.Error(w, "unexpected", http.StatusBadRequest)
+			return
+		}
+		if jmap.IsWriteRequest(req) {
+			t.Errorf("write reached the server under --dry-run: %s", body)
+		}
+		var resp jmap.Response
+		for _, call := range req.MethodCalls {
+			method, _ := call[0].(string)
+			args, _ := call[1].(map[string]any)
+			resp.MethodResponses = append(resp.MethodResponses, jmap.MethodResponse{method, read(method, args), call[2]})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGlobalDryRun_EmailCopyWithBlobCopy(t *testing.T) {
+	server := newDryRunServer(t, map[string]string{"src": "u1", "dst": "u2"}, func(method string, args map[string]any) map[string]any {
+		switch method {
+		case "Mailbox/get":
+			account := args["accountId"].(string)
+			return map[string]any{"list": []any{
+				map[string]any{"id": "inbox-" + account, "name": "Inbox", "role": "inbox"},
+				map[string]any{"id": "trash-" + account, "name": "Trash", "role": "trash"},
+			}}
+		case "Email/get":
+			return map[string]any{"list": []any{map[string]any{"id": "e1", "blobId": "b1", "receivedAt": "2025-01-15T10:00:00Z"}}}
+		}
+		t.Errorf("unexpected read %s", method)
+		return map[string]any{}
+	})
+
+	app := &App{Flags: &rootFlags{DryRun: true, Output: "json"}}
+	src := jmap.NewClientWithBaseURL("src", server.URL)
+	src.SetHTTPClient(app.dryRunHTTPClient())
+	dst := jmap.NewClientWithBaseURL("dst", server.URL)
+	dst.SetHTTPClient(app.dryRunHTTPClient())
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.WithValue(context.Background(), outputModeKey, outfmt.JSON))
+	out := captureStdout(t, func() {
+		if err := runEmailCopy(cmd, app, src, dst, &commandPolicy{}, "dst@example.com", []string{"e1"}, "", true); err != nil {
+			t.Fatalf("runEmailCopy: %v", err)
+		}
+	})
+
+	var got struct {
+		Method string            `json:"method"`
+		Copied []copiedEmail     `json:"copied"`
+		Failed map[string]string `json:"failed"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output %q: %v", out, err)
+	}
+	if got.Method != copyMethodBlobCopy || len(got.Copied) != 1 || len(got.Failed) != 0 {
+		t.Errorf("output = %+v, want e1 copied with Blob/copy", got)
+	}
+
+	var methods []string
+	for _, r := range app.dryRunRecorder().Requests() {
+		req, _ := jmap.ParseRequestBody(r.Body)
+		methods = append(methods, req.MethodCalls[0][0].(string))
+	}
+	if strings.Join(methods, ",") != "Blob/copy,Email/import,Email/set" {
+		t.Errorf("recorded %v, want Blob/copy, Email/import and Email/set", methods)
+	}
+}
+
+func TestGlobalDryRun_NotShadowedByLocalFlags(t *testing.T) {
+	root := NewRootCmd(newTestApp())
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		if c != root && c.LocalNonPersistentFlags().Lookup("dry-run") != nil {
+			t.Errorf("%q defines its own --dry-run, hiding the global one", c.CommandPath())
+		}
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+}
+
+func TestGlobalDryRun_EmailParseSendsUpload(t *testing.T) {
+	server := newDryRunServer(t, map[string]string{"token": "u1"}, func(method string, args map[string]any) map[string]any {
+		if method != "Email/parse" {
+			t.Errorf("unexpected read %s", method)
+			return map[string]any{}
+		}
+		if ids := args["blobIds"].([]any); len(ids) != 1 || ids[0] != "uploaded" {
+			return map[string]any{"notFound": ids}
+		}
+		return map[string]any{"parsed": map[string]any{"uploaded": map[string]any{"subject": "Hello"}}}
+	})
+
+	app := &App{Flags: &rootFlags{DryRun: true}}
+	client := jmap.NewClientWithBaseURL("token", server.URL)
+	client.SetHTTPClient(app.dryRunHTTPClient())
+
+	email, err := parseEml(context.Background(), client, strings.NewReader("Subject: Hello\r\n\r\nHi\r\n"))
+	if err != nil {
+		t.Fatalf("parseEml under --dry-run: %v", err)
+	}
+	if email.Subject != "Hello" {
+		t.Errorf("subject = %q, want Hello", email.Subject)
+	}
+	if reqs := app.dryRunRecorder().Requests(); len(reqs) != 0 {
+		t.Errorf("recorded %d requests, want none for a parse", len(reqs))
+	}
+}
@@ -18,9 +18,16 @@ func newEmailCmd(app *App) *cobra.Command {
 	cmd.AddCommand(newEmailBulkDeleteCmd(app))
 	cmd.AddCommand(newEmailMoveCmd(app))
 	cmd.AddCommand(newEmailBulkMoveCmd(app))
+	cmd.AddCommand(newEmailCopyCmd(app))
 	cmd.AddCommand(newEmailBulkArchiveCmd(app))
 	cmd.AddCommand(newEmailMarkReadCmd(app))
 	cmd.AddCommand(newEmailBulkMarkReadCmd(app))
+	cmd.AddCommand(newEmailDedupeCmd(app))
+	cmd.AddCommand(newEmailDuCmd(app))
+	cmd.AddCommand(newEmailSnoozeCmd(app))
+	cmd.AddCommand(newEmailSnoozedCmd(app))
+	cmd.AddCommand(newEmailSpamCmd(app))
+	cmd.AddCommand(newEmailNotSpamCmd(app))
 	cmd.AddCommand(newEmailThreadCmd(app))
 	cmd.AddCommand(newEmailAttachmentsCmd(app))
 	cmd.AddCommand(newEmailDownloadCmd(app))
@@ -29,6 +36,7 @@ func newEmailCmd(app *App) *cobra.Command {
 	cmd.AddCommand(newMailboxDeleteCmd(app))
 	cmd.AddCommand(newMailboxRenameCmd(app))
 	cmd.AddCommand(newEmailImportCmd(app))
+	cmd.AddCommand(newEmailParseCmd(app))
 	cmd.AddCommand(newEmailIdentitiesCmd(app))
 	cmd.AddCommand(newIdentitySetDefaultCmd(app))
 	cmd.AddCommand(newEmailTrackCmd(app))
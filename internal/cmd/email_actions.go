@@ -52,7 +52,6 @@ func newEmailDeleteCmd(app *App) *cobra.Command {
 }
 
 func newEmailBulkDeleteCmd(app *App) *cobra.Command {
-	var dryRun bool
 	var input bulkInputOptions
 
 	cmd := &cobra.Command{
@@ -70,7 +69,7 @@ func newEmailBulkDeleteCmd(app *App) *cobra.Command {
 			}
 
 			// Handle dry-run mode
-			if dryRun {
+			if app.dryRun() {
 				return printDryRunList(app, cmd, fmt.Sprintf("Would delete %d emails:", len(ids)), "wouldDelete", ids, map[string]any{
 					"batchSize": input.BatchSize,
 				})
@@ -81,6 +80,17 @@ func newEmailBulkDeleteCmd(app *App) *cobra.Command {
 				return err
 			}
 
+			policy, err := app.Policy()
+			if err != nil {
+				return err
+			}
+			if err := policy.checkBulk("delete", len(ids)); err != nil {
+				return err
+			}
+			if err := policy.checkBulkDelete(cmd.Context(), client, ids); err != nil {
+				return err
+			}
+
 			// Prompt for confirmation unless --yes flag is set (global) or JSON output mode.
 			confirmed, err := app.Confirm(cmd, false, fmt.Sprintf("Delete %d emails? [y/N] ", len(ids)), "y", "yes")
 			if err != nil {
@@ -92,7 +102,7 @@ func newEmailBulkDeleteCmd(app *App) *cobra.Command {
 			}
 
 			// Delete emails using bulk API in client-side batches.
-			results, batches, err := runBulkInBatches(ids, input.BatchSize, "deleting emails", func(batch []string) (*jmap.BulkResult, error) {
+			results, batches, err := runBulkInBatches(cmd.Context(), ids, input.batchOptions(), "deleting emails", func(batch []string) (*jmap.BulkResult, error) {
 				return client.DeleteEmails(cmd.Context(), batch)
 			})
 			if err != nil {
@@ -126,7 +136,6 @@ func newEmailBulkDeleteCmd(app *App) *cobra.Command {
 		}),
 	}
 
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be deleted without making changes")
 	addBulkInputFlags(cmd, &input)
 
 	return cmd
@@ -178,7 +187,6 @@ func newEmailMoveCmd(app *App) *cobra.Command {
 
 func newEmailBulkMoveCmd(app *App) *cobra.Command {
 	var targetMailbox string
-	var dryRun bool
 	var input bulkInputOptions
 
 	cmd := &cobra.Command{
@@ -190,21 +198,19 @@ func newEmailBulkMoveCmd(app *App) *cobra.Command {
   fastmail email bulk-move --stdin --to Archive --yes < /tmp/fm-ids.txt`,
 		Args: validateBulkInputArgs,
 		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
-			return runEmailBulkMove(cmd, args, app, targetMailbox, dryRun, input)
+			return runEmailBulkMove(cmd, args, app, targetMailbox, app.dryRun(), input)
 		}),
 	}
 
 	cmd.Flags().StringVar(&targetMailbox, "to", "", "Target mailbox ID or name")
 	cmd.Flags().StringVar(&targetMailbox, "mailbox", "", "Target mailbox ID or name (alias for --to)")
 	_ = cmd.Flags().MarkHidden("mailbox") // Hidden alias for agent compatibility
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be moved without making changes")
 	addBulkInputFlags(cmd, &input)
 
 	return cmd
 }
 
 func newEmailBulkArchiveCmd(app *App) *cobra.Command {
-	var dryRun bool
 	var input bulkInputOptions
 
 	cmd := &cobra.Command{
@@ -216,11 +222,10 @@ func newEmailBulkArchiveCmd(app *App) *cobra.Command {
   fastmail email bulk-archive --stdin --yes < /tmp/fm-ids.txt`,
 		Args: validateBulkInputArgs,
 		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
-			return runEmailBulkMove(cmd, args, app, "Archive", dryRun, input)
+			return runEmailBulkMove(cmd, args, app, "Archive", app.dryRun(), input)
 		}),
 	}
 
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be moved without making changes")
 	addBulkInputFlags(cmd, &input)
 
 	return cmd
@@ -250,10 +255,18 @@ func runEmailBulkMove(cmd *cobra.Command, args []string, app *App, targetMailbox
 		return err
 	}
 
-	return runEmailBulkMoveWithClient(cmd, app, client, ids, targetMailbox, input.BatchSize)
+	policy, err := app.Policy()
+	if err != nil {
+		return err
+	}
+	if err := policy.checkBulk("move", len(ids)); err != nil {
+		return err
+	}
+
+	return runEmailBulkMoveWithClient(cmd, app, client, ids, targetMailbox, input.batchOptions())
 }
 
-func runEmailBulkMoveWithClient(cmd *cobra.Command, app *App, client bulkMoveClient, ids []string, targetMailbox string, batchSize int) error {
+func runEmailBulkMoveWithClient(cmd *cobra.Command, app *App, client bulkMoveClient, ids []string, targetMailbox string, opts bulkBatchOptions) error {
 	// Resolve target mailbox ID + display name in one mailbox fetch.
 	resolvedID, mailboxName, err := resolveMailboxTarget(cmd.Context(), client, targetMailbox)
 	if err != nil {
@@ -271,7 +284,7 @@ func runEmailBulkMoveWithClient(cmd *cobra.Command, app *App, client bulkMoveCli
 	}
 
 	// Move emails using bulk API in client-side batches.
-	results, batches, err := runBulkInBatches(ids, batchSize, "moving emails", func(batch []string) (*jmap.BulkResult, error) {
+	results, batches, err := runBulkInBatches(cmd.Context(), ids, opts, "moving emails", func(batch []string) (*jmap.BulkResult, error) {
 		return client.MoveEmails(cmd.Context(), batch, resolvedID)
 	})
 	if err != nil {
@@ -285,7 +298,7 @@ func runEmailBulkMoveWithClient(cmd *cobra.Command, app *App, client bulkMoveCli
 			"mailbox":   mailboxName,
 			"mailboxId": resolvedID,
 			"succeeded": results.Succeeded,
-			"batchSize": batchSize,
+			"batchSize": opts.BatchSize,
 			"batches":   batches,
 		}
 		if len(results.Failed) > 0 {
@@ -295,7 +308,7 @@ func runEmailBulkMoveWithClient(cmd *cobra.Command, app *App, client bulkMoveCli
 	}
 
 	if batches > 1 {
-		fmt.Printf("Processed %d emails in %d batches (batch size %d)\n", len(ids), batches, batchSize)
+		fmt.Printf("Processed %d emails in %d batches (batch size %d)\n", len(ids), batches, opts.BatchSize)
 	}
 
 	// Handle text output
@@ -382,7 +395,6 @@ func newEmailMarkReadCmd(app *App) *cobra.Command {
 
 func newEmailBulkMarkReadCmd(app *App) *cobra.Command {
 	var unread bool
-	var dryRun bool
 	var input bulkInputOptions
 
 	cmd := &cobra.Command{
@@ -405,7 +417,7 @@ func newEmailBulkMarkReadCmd(app *App) *cobra.Command {
 			}
 
 			// Handle dry-run mode
-			if dryRun {
+			if app.dryRun() {
 				return printDryRunList(app, cmd, fmt.Sprintf("Would mark %d emails as %s:", len(ids), status), "wouldMark", ids, map[string]any{
 					"status":    status,
 					"batchSize": input.BatchSize,
@@ -417,8 +429,16 @@ func newEmailBulkMarkReadCmd(app *App) *cobra.Command {
 				return err
 			}
 
+			policy, err := app.Policy()
+			if err != nil {
+				return err
+			}
+			if err := policy.checkBulk("mark", len(ids)); err != nil {
+				return err
+			}
+
 			// Mark emails using bulk API in client-side batches.
-			results, batches, err := runBulkInBatches(ids, input.BatchSize, "marking emails", func(batch []string) (*jmap.BulkResult, error) {
+			results, batches, err := runBulkInBatches(cmd.Context(), ids, input.batchOptions(), "marking emails", func(batch []string) (*jmap.BulkResult, error) {
 				return client.MarkEmailsRead(cmd.Context(), batch, !unread)
 			})
 			if err != nil {
@@ -453,7 +473,6 @@ func newEmailBulkMarkReadCmd(app *App) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&unread, "unread", false, "Mark as unread instead of read")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be changed without making changes")
 	addBulkInputFlags(cmd, &input)
 
 	return cmd
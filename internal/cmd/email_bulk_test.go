@@ -105,8 +105,8 @@ func TestEmailBulkDeleteCmd_HasRequiredFlags(t *testing.T) {
 		t.Fatal("expected bulk-delete command to exist under email")
 	}
 
-	// Verify --dry-run flag exists
-	dryRunFlag := cmd.Flags().Lookup("dry-run")
+	// Verify the global --dry-run flag reaches the command
+	dryRunFlag := cmd.Flag("dry-run")
 	if dryRunFlag == nil {
 		t.Error("expected --dry-run flag to exist")
 	}
@@ -282,7 +282,7 @@ func TestRunEmailBulkMoveWithClient_SuccessInBatches(t *testing.T) {
 	}
 
 	out := captureStdout(t, func() {
-		err := runEmailBulkMoveWithClient(cmd, app, client, []string{"id1", "id2", "id3"}, "Archive", 2)
+		err := runEmailBulkMoveWithClient(cmd, app, client, []string{"id1", "id2", "id3"}, "Archive", bulkBatchOptions{BatchSize: 2, Concurrency: 1})
 		if err != nil {
 			t.Fatalf("runEmailBulkMoveWithClient error: %v", err)
 		}
@@ -317,7 +317,7 @@ func TestRunEmailBulkMoveWithClient_PartialFailure(t *testing.T) {
 	}
 
 	out := captureStdout(t, func() {
-		err := runEmailBulkMoveWithClient(cmd, app, client, []string{"id1", "id2", "id3"}, "Archive", 2)
+		err := runEmailBulkMoveWithClient(cmd, app, client, []string{"id1", "id2", "id3"}, "Archive", bulkBatchOptions{BatchSize: 2, Concurrency: 1})
 		if err != nil {
 			t.Fatalf("runEmailBulkMoveWithClient error: %v", err)
 		}
@@ -352,7 +352,7 @@ func TestRunEmailBulkMoveWithClient_Cancelled(t *testing.T) {
 	_ = w.Close()
 
 	stderr := captureStderr(t, func() {
-		err := runEmailBulkMoveWithClient(cmd, app, client, []string{"id1"}, "Archive", 50)
+		err := runEmailBulkMoveWithClient(cmd, app, client, []string{"id1"}, "Archive", bulkBatchOptions{BatchSize: 50, Concurrency: 1})
 		if err != nil {
 			t.Fatalf("runEmailBulkMoveWithClient unexpected error: %v", err)
 		}
@@ -466,8 +466,8 @@ func TestEmailBulkMoveCmd_HasRequiredFlags(t *testing.T) {
 		t.Error("expected --to flag to exist")
 	}
 
-	// Verify --dry-run flag exists
-	dryRunFlag := cmd.Flags().Lookup("dry-run")
+	// Verify the global --dry-run flag reaches the command
+	dryRunFlag := cmd.Flag("dry-run")
 	if dryRunFlag == nil {
 		t.Error("expected --dry-run flag to exist")
 	}
@@ -638,8 +638,8 @@ func TestEmailBulkArchiveCmd_HasRequiredFlags(t *testing.T) {
 		t.Fatal("expected bulk-archive command to exist under email")
 	}
 
-	// Verify --dry-run flag exists
-	dryRunFlag := cmd.Flags().Lookup("dry-run")
+	// Verify the global --dry-run flag reaches the command
+	dryRunFlag := cmd.Flag("dry-run")
 	if dryRunFlag == nil {
 		t.Error("expected --dry-run flag to exist")
 	}
@@ -810,8 +810,8 @@ func TestEmailBulkMarkReadCmd_HasRequiredFlags(t *testing.T) {
 		t.Error("expected --unread flag to exist")
 	}
 
-	// Verify --dry-run flag exists
-	dryRunFlag := cmd.Flags().Lookup("dry-run")
+	// Verify the global --dry-run flag reaches the command
+	dryRunFlag := cmd.Flag("dry-run")
 	if dryRunFlag == nil {
 		t.Error("expected --dry-run flag to exist")
 	}
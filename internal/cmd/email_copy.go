@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/spf13/cobra"
+)
+
+const (
+	copyMethodBlobCopy = "blob-copy"
+	copyMethodDownload = "download"
+)
+
+type copySourceClient interface {
+	policyMailClient
+	GetSession(ctx context.Context) (*jmap.Session, error)
+	CopyBlobs(ctx context.Context, toAccountID string, blobIDs []string) (map[string]string, map[string]string, error)
+	DownloadBlob(ctx context.Context, blobID string) (io.ReadCloser, error)
+	DeleteEmails(ctx context.Context, ids []string) (*jmap.BulkResult, error)
+}
+
+type copyTargetClient interface {
+	mailboxLookupClient
+	GetSession(ctx context.Context) (*jmap.Session, error)
+	UploadBlob(ctx context.Context, reader io.Reader, contentType string) (*jmap.UploadBlobResult, error)
+	ImportEmail(ctx context.Context, opts jmap.ImportEmailOpts) (string, error)
+}
+
+// copiedEmail pairs a source email with the email created in the target account.
+type copiedEmail struct {
+	SourceID string `json:"sourceId"`
+	EmailID  string `json:"emailId"`
+}
+
+func newEmailCopyCmd(app *App) *cobra.Command {
+	var toAccount string
+	var mailbox string
+	var move bool
+
+	cmd := &cobra.Command{
+		Use:   "copy <emailId>...",
+		Short: "Copy or move emails to another configured account",
+		Long: `Copy emails to another account configured with 'fastmail auth add'.
+
+The copies keep the original keywords (read, flagged, ...) and received date.
+When the current account's session can also access the target account, the
+messages are copied server-side with Blob/copy. Otherwise each raw message is
+downloaded and imported using the target account's token.
+
+With --move, the originals are moved to the trash once they have been copied.`,
+		Example: `  fastmail email copy M123 --to-account shared@example.com
+  fastmail email copy M123 M456 --to-account shared@example.com --mailbox Archive --move`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
+			if toAccount == "" {
+				return fmt.Errorf("%w: --to-account is required", ErrUsage)
+			}
+
+			fromAccount, err := app.RequireAccount()
+			if err != nil {
+				return err
+			}
+			if strings.EqualFold(fromAccount, toAccount) {
+				return fmt.Errorf("%w: --to-account must differ from the current account (%s)", ErrUsage, fromAccount)
+			}
+
+			if app.dryRun() {
+				return printDryRunList(app, cmd, fmt.Sprintf("Would copy %d emails to %s:", len(args), toAccount), "wouldCopy", args, map[string]any{
+					"toAccount": toAccount,
+					"mailbox":   mailbox,
+					"move":      move,
+				})
+			}
+
+			src, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+			dst, err := app.JMAPClientFor(toAccount)
+			if err != nil {
+				return err
+			}
+			policy, err := app.Policy()
+			if err != nil {
+				return err
+			}
+
+			return runEmailCopy(cmd, app, src, dst, policy, toAccount, args, mailbox, move)
+		}),
+	}
+
+	cmd.Flags().StringVar(&toAccount, "to-account", "", "Configured account to copy the emails to (required)")
+	cmd.Flags().StringVar(&mailbox, "mailbox", "", "Mailbox in the target account (ID or name, default: Inbox)")
+	cmd.Flags().BoolVar(&move, "move", false, "Move the originals to the trash after copying")
+
+	return cmd
+}
+
+// runEmailCopy copies ids from src into mailbox in the dst account, then
+// trashes the copied originals when move is set.
+func runEmailCopy(cmd *cobra.Command, app *App, src copySourceClient, dst copyTargetClient, policy *commandPolicy, toAccount string, ids []string, mailbox string, move bool) error {
+	ctx := cmd.Context()
+
+	// --move trashes the originals, so protected mailboxes apply.
+	if move {
+		if err := policy.checkBulkDelete(ctx, src, ids); err != nil {
+			return err
+		}
+	}
+
+	srcSession, err := src.GetSession(ctx)
+	if err != nil {
+		return cerrors.WithContext(err, "getting source session")
+	}
+	dstSession, err := dst.GetSession(ctx)
+	if err != nil {
+		return cerrors.WithContext(err, "getting target session")
+	}
+	if srcSession.AccountID == dstSession.AccountID {
+		return fmt.Errorf("%w: %s is the same JMAP account as the current account", ErrUsage, toAccount)
+	}
+
+	if mailbox == "" {
+		mailbox = "inbox"
+	}
+	mailboxID, mailboxName, err := resolveMailboxTarget(ctx, dst, mailbox)
+	if err != nil {
+		return err
+	}
+
+	emails, err := src.GetEmailSummaries(ctx, ids)
+	if err != nil {
+		return cerrors.WithContext(err, "fetching emails")
+	}
+
+	failed := map[string]string{}
+	found := make(map[string]bool, len(emails))
+	for _, e := range emails {
+		found[e.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			failed[id] = "not found"
+		}
+	}
+
+	// Get a blob for each email in the target account.
+	method := copyMethodDownload
+	targetBlobs := make(map[string]string, len(emails))
+	if srcSession.HasAccount(dstSession.AccountID) {
+		method = copyMethodBlobCopy
+		blobIDs := make([]string, 0, len(emails))
+		for _, e := range emails {
+			blobIDs = append(blobIDs, e.BlobID)
+		}
+		copied, notCopied, err := src.CopyBlobs(ctx, dstSession.AccountID, blobIDs)
+		if err != nil {
+			return cerrors.WithContext(err, "copying blobs")
+		}
+		for _, e := range emails {
+			if blobID, ok := copied[e.BlobID]; ok {
+				targetBlobs[e.ID] = blobID
+			} else if reason, ok := notCopied[e.BlobID]; ok {
+				failed[e.ID] = reason
+			} else {
+				failed[e.ID] = "blob not copied"
+			}
+		}
+	} else {
+		for _, e := range emails {
+			blobID, err := transferBlob(ctx, src, dst, e.BlobID)
+			if err != nil {
+				failed[e.ID] = err.Error()
+				continue
+			}
+			targetBlobs[e.ID] = blobID
+		}
+	}
+
+	copied := []copiedEmail{}
+	var copiedIDs []string
+	for _, e := range emails {
+		blobID, ok := targetBlobs[e.ID]
+		if !ok {
+			continue
+		}
+		newID, err := dst.ImportEmail(ctx, jmap.ImportEmailOpts{
+			BlobID:     blobID,
+			MailboxIDs: map[string]bool{mailboxID: true},
+			Keywords:   e.Keywords,
+			ReceivedAt: e.ReceivedAt,
+		})
+		if err != nil {
+			failed[e.ID] = err.Error()
+			continue
+		}
+		copied = append(copied, copiedEmail{SourceID: e.ID, EmailID: newID})
+		copiedIDs = append(copiedIDs, e.ID)
+	}
+
+	if move && len(copiedIDs) > 0 {
+		result, err := src.DeleteEmails(ctx, copiedIDs)
+		if err != nil {
+			return fmt.Errorf("emails copied, but failed to remove originals: %w", err)
+		}
+		for id, reason := range result.Failed {
+			failed[id] = "copied, but not removed: " + reason
+		}
+	}
+
+	if app.IsJSON(ctx) {
+		output := map[string]any{
+			"toAccount": toAccount,
+			"mailbox":   mailboxName,
+			"method":    method,
+			"moved":     move,
+			"copied":    copied,
+		}
+		if len(failed) > 0 {
+			output["failed"] = failed
+		}
+		return app.PrintJSON(cmd, output)
+	}
+
+	action := "Copied"
+	if move {
+		action = "Moved"
+	}
+	printBulkResults(action, fmt.Sprintf("emails to %s (%s)", toAccount, mailboxName), len(copied), len(failed), failed)
+	return nil
+}
+
+// transferBlob downloads a raw message from src and uploads it to dst,
+// returning the blob ID in the target account.
+func transferBlob(ctx context.Context, src copySourceClient, dst copyTargetClient, blobID string) (string, error) {
+	body, err := src.DownloadBlob(ctx, blobID)
+	if err != nil {
+		return "", fmt.Errorf("download: %w", err)
+	}
+	defer body.Close()
+
+	upload, err := dst.UploadBlob(ctx, body, "message/rfc822")
+	if err != nil {
+		return "", fmt.Errorf("upload: %w", err)
+	}
+	return upload.BlobID, nil
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/spf13/cobra"
+)
+
+type fakeCopySource struct {
+	session    *jmap.Session
+	mailboxes  []jmap.Mailbox
+	emails     []jmap.Email
+	blobCopies []string
+	downloaded []string
+	deleted    []string
+}
+
+func (f *fakeCopySource) GetSession(_ context.Context) (*jmap.Session, error) {
+	return f.session, nil
+}
+
+func (f *fakeCopySource) GetMailboxes(_ context.Context) ([]jmap.Mailbox, error) {
+	return f.mailboxes, nil
+}
+
+func (f *fakeCopySource) GetEmailSummaries(_ context.Context, _ []string) ([]jmap.Email, error) {
+	return f.emails, nil
+}
+
+func (f *fakeCopySource) CopyBlobs(_ context.Context, _ string, blobIDs []string) (map[string]string, map[string]string, error) {
+	f.blobCopies = append(f.blobCopies, blobIDs...)
+	copied := map[string]string{}
+	for _, id := range blobIDs {
+		copied[id] = id + "-copy"
+	}
+	return copied, map[string]string{}, nil
+}
+
+func (f *fakeCopySource) DownloadBlob(_ context.Context, blobID string) (io.ReadCloser, error) {
+	f.downloaded = append(f.downloaded, blobID)
+	return io.NopCloser(strings.NewReader("raw " + blobID)), nil
+}
+
+func (f *fakeCopySource) DeleteEmails(_ context.Context, ids []string) (*jmap.BulkResult, error) {
+	f.deleted = append(f.deleted, ids...)
+	return &jmap.BulkResult{Succeeded: ids, Failed: map[string]string{}}, nil
+}
+
+type fakeCopyTarget struct {
+	session  *jmap.Session
+	uploaded []string
+	imported []jmap.ImportEmailOpts
+}
+
+func (f *fakeCopyTarget) GetMailboxes(_ context.Context) ([]jmap.Mailbox, error) {
+	return []jmap.Mailbox{{ID: "t-inbox", Name: "Inbox", Role: "inbox"}, {ID: "t-arch", Name: "Archive"}}, nil
+}
+
+func (f *fakeCopyTarget) GetSession(_ context.Context) (*jmap.Session, error) {
+	return f.session, nil
+}
+
+func (f *fakeCopyTarget) UploadBlob(_ context.Context, r io.Reader, _ string) (*jmap.UploadBlobResult, error) {
+	data, _ := io.ReadAll(r)
+	f.uploaded = append(f.uploaded, string(data))
+	return &jmap.UploadBlobResult{BlobID: "uploaded"}, nil
+}
+
+func (f *fakeCopyTarget) ImportEmail(_ context.Context, opts jmap.ImportEmailOpts) (string, error) {
+	f.imported = append(f.imported, opts)
+	return "new-" + opts.BlobID, nil
+}
+
+func TestRunEmailCopy_SameSessionMove(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	src := &fakeCopySource{
+		session: &jmap.Session{AccountID: "a1", Accounts: map[string]jmap.SessionAccount{"a1": {}, "a2": {}}},
+		emails: []jmap.Email{{
+			ID: "e1", BlobID: "b1", ReceivedAt: "2025-01-15T10:30:00Z",
+			Keywords: map[string]bool{"$seen": true, "$flagged": true},
+		}},
+	}
+	dst := &fakeCopyTarget{session: &jmap.Session{AccountID: "a2"}}
+
+	out := captureStdout(t, func() {
+		if err := runEmailCopy(cmd, app, src, dst, &commandPolicy{}, "shared@example.com", []string{"e1", "missing"}, "Archive", true); err != nil {
+			t.Fatalf("runEmailCopy error: %v", err)
+		}
+	})
+
+	if !reflect.DeepEqual(src.blobCopies, []string{"b1"}) || len(src.downloaded) != 0 {
+		t.Fatalf("expected server-side blob copy, got copies=%v downloads=%v", src.blobCopies, src.downloaded)
+	}
+	want := jmap.ImportEmailOpts{
+		BlobID:     "b1-copy",
+		MailboxIDs: map[string]bool{"t-arch": true},
+		Keywords:   map[string]bool{"$seen": true, "$flagged": true},
+		ReceivedAt: "2025-01-15T10:30:00Z",
+	}
+	if len(dst.imported) != 1 || !reflect.DeepEqual(dst.imported[0], want) {
+		t.Fatalf("imported = %+v", dst.imported)
+	}
+	if !reflect.DeepEqual(src.deleted, []string{"e1"}) {
+		t.Errorf("only copied originals should be trashed, got %v", src.deleted)
+	}
+	if !strings.Contains(out, "Moved 1 emails to shared@example.com (Archive), 1 failed") || !strings.Contains(out, "missing: not found") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestRunEmailCopy_DownloadFallback(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	src := &fakeCopySource{
+		session: &jmap.Session{AccountID: "a1", Accounts: map[string]jmap.SessionAccount{"a1": {}}},
+		emails:  []jmap.Email{{ID: "e1", BlobID: "b1"}},
+	}
+	dst := &fakeCopyTarget{session: &jmap.Session{AccountID: "a2"}}
+
+	captureStdout(t, func() {
+		if err := runEmailCopy(cmd, app, src, dst, &commandPolicy{}, "other@example.com", []string{"e1"}, "", false); err != nil {
+			t.Fatalf("runEmailCopy error: %v", err)
+		}
+	})
+
+	if len(src.blobCopies) != 0 || !reflect.DeepEqual(dst.uploaded, []string{"raw b1"}) {
+		t.Fatalf("expected download and re-upload, got copies=%v uploads=%v", src.blobCopies, dst.uploaded)
+	}
+	if len(dst.imported) != 1 || !dst.imported[0].MailboxIDs["t-inbox"] {
+		t.Fatalf("expected import into target inbox, got %+v", dst.imported)
+	}
+	if len(src.deleted) != 0 {
+		t.Errorf("copy without --move should not delete, got %v", src.deleted)
+	}
+}
@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
+	"github.com/salmonumbrella/fastmail-cli/internal/format"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/spf13/cobra"
+)
+
+const (
+	dedupeKeepOldest        = "oldest"
+	dedupeKeepMostMailboxes = "most-mailboxes"
+
+	dedupeKeyMessageID = "message-id"
+	dedupeKeyHash      = "hash"
+)
+
+type dedupeClient interface {
+	mailboxLookupClient
+	GetEmailSummaries(ctx context.Context, ids []string) ([]jmap.Email, error)
+	ScanEmails(ctx context.Context, opts jmap.ScanEmailsOpts) ([]jmap.Email, error)
+	DeleteEmails(ctx context.Context, ids []string) (*jmap.BulkResult, error)
+}
+
+// duplicateGroup is one set of emails that are copies of the same message.
+type duplicateGroup struct {
+	Key        string   `json:"key"`
+	KeyType    string   `json:"keyType"`
+	Subject    string   `json:"subject"`
+	Keep       string   `json:"keep"`
+	Duplicates []string `json:"duplicates"`
+}
+
+func newEmailDedupeCmd(app *App) *cobra.Command {
+	var mailbox string
+	var keep string
+	var limit int
+	var batchOpts bulkBatchOptions
+
+	cmd := &cobra.Command{
+		Use:     "dedupe",
+		Aliases: []string{"dedup"},
+		Short:   "Find duplicate emails and move extra copies to trash",
+		Long: `Find duplicate emails and move the extra copies to trash.
+
+Emails are grouped by their Message-ID header. Emails without a Message-ID
+are grouped by a hash of their Date, From, Subject and size instead.
+One copy per group is kept according to --keep:
+
+  oldest          Keep the copy received first (default)
+  most-mailboxes  Keep the copy filed in the most mailboxes
+
+Without --mailbox, every mailbox except Trash is scanned.
+
+Examples:
+  fastmail email dedupe --dry-run
+  fastmail email dedupe --mailbox Archive --keep most-mailboxes --yes`,
+		Args: cobra.NoArgs,
+		RunE: runE(app, func(cmd *cobra.Command, _ []string, app *App) error {
+			if keep != dedupeKeepOldest && keep != dedupeKeepMostMailboxes {
+				return fmt.Errorf("%w: --keep must be %q or %q", ErrUsage, dedupeKeepOldest, dedupeKeepMostMailboxes)
+			}
+			if batchOpts.BatchSize <= 0 {
+				return fmt.Errorf("%w: --batch-size must be greater than 0", ErrUsage)
+			}
+			if batchOpts.Concurrency <= 0 {
+				return fmt.Errorf("%w: --concurrency must be greater than 0", ErrUsage)
+			}
+
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+			policy, err := app.Policy()
+			if err != nil {
+				return err
+			}
+
+			return runEmailDedupe(cmd, app, client, policy, mailbox, keep, limit, batchOpts, app.dryRun())
+		}),
+	}
+
+	cmd.Flags().StringVar(&mailbox, "mailbox", "", "Only look for duplicates in this mailbox (ID or name)")
+	cmd.Flags().StringVar(&keep, "keep", dedupeKeepOldest, "Which copy to keep: oldest|most-mailboxes")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of emails to scan (0 = all)")
+	cmd.Flags().IntVar(&batchOpts.BatchSize, "batch-size", defaultBulkBatchSize, "Email IDs per API request when trashing")
+	cmd.Flags().IntVar(&batchOpts.Concurrency, "concurrency", defaultBulkConcurrency, "Batches to trash in parallel")
+
+	return cmd
+}
+
+func runEmailDedupe(cmd *cobra.Command, app *App, client dedupeClient, policy *commandPolicy, mailbox, keep string, limit int, batchOpts bulkBatchOptions, dryRun bool) error {
+	ctx := cmd.Context()
+
+	opts := jmap.ScanEmailsOpts{Limit: limit}
+	mailboxName := ""
+	if mailbox != "" {
+		mailboxID, name, err := resolveMailboxTarget(ctx, client, mailbox)
+		if err != nil {
+			return err
+		}
+		opts.MailboxID = mailboxID
+		mailboxName = name
+	} else {
+		// Copies already in Trash are not duplicates worth reporting.
+		mailboxes, err := client.GetMailboxes(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get mailboxes: %w", err)
+		}
+		for _, mb := range mailboxes {
+			if mb.Role == "trash" {
+				opts.ExcludeMailboxIDs = []string{mb.ID}
+				break
+			}
+		}
+	}
+
+	emails, err := client.ScanEmails(ctx, opts)
+	if err != nil {
+		return cerrors.WithContext(err, "scanning emails")
+	}
+
+	groups := groupDuplicateEmails(emails, keep)
+	var toTrash []string
+	for _, g := range groups {
+		toTrash = append(toTrash, g.Duplicates...)
+	}
+
+	output := map[string]any{
+		"scanned":    len(emails),
+		"keep":       keep,
+		"groups":     groups,
+		"duplicates": len(toTrash),
+	}
+	if mailboxName != "" {
+		output["mailbox"] = mailboxName
+	}
+
+	if len(groups) == 0 {
+		if app.IsJSON(ctx) {
+			output["status"] = "clean"
+			return app.PrintJSON(cmd, output)
+		}
+		printNoResults("No duplicates found in %d emails", len(emails))
+		return nil
+	}
+
+	if dryRun {
+		if app.IsJSON(ctx) {
+			output["dryRun"] = true
+			return app.PrintJSON(cmd, output)
+		}
+		printDuplicateGroups(groups, len(emails))
+		return nil
+	}
+
+	if err := policy.checkBulk("trash", len(toTrash)); err != nil {
+		return err
+	}
+	if err := policy.checkBulkDelete(ctx, client, toTrash); err != nil {
+		return err
+	}
+
+	if !app.IsJSON(ctx) {
+		printDuplicateGroups(groups, len(emails))
+	}
+
+	confirmed, err := app.Confirm(cmd, false, fmt.Sprintf("Move %d duplicate emails to trash? [y/N] ", len(toTrash)), "y", "yes")
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		printCancelled()
+		return nil
+	}
+
+	results, batches, err := runBulkInBatches(ctx, toTrash, batchOpts, "trashing duplicates", func(batch []string) (*jmap.BulkResult, error) {
+		return client.DeleteEmails(ctx, batch)
+	})
+	if err != nil {
+		return cerrors.WithContext(err, "trashing duplicates")
+	}
+
+	if app.IsJSON(ctx) {
+		output["status"] = "deduplicated"
+		output["succeeded"] = results.Succeeded
+		output["batchSize"] = batchOpts.BatchSize
+		output["batches"] = batches
+		if len(results.Failed) > 0 {
+			output["failed"] = results.Failed
+		}
+		return app.PrintJSON(cmd, output)
+	}
+
+	printBulkResults("Moved", "duplicate emails to trash", len(results.Succeeded), len(results.Failed), results.Failed)
+	return nil
+}
+
+// groupDuplicateEmails groups emails that are copies of the same message and
+// picks one copy to keep per group. Only groups with more than one email are
+// returned, sorted by key for stable output.
+func groupDuplicateEmails(emails []jmap.Email, keep string) []duplicateGroup {
+	byKey := make(map[string][]jmap.Email)
+	keyTypes := make(map[string]string)
+	for _, e := range emails {
+		key, keyType := dedupeKey(e)
+		byKey[key] = append(byKey[key], e)
+		keyTypes[key] = keyType
+	}
+
+	groups := make([]duplicateGroup, 0)
+	for key, members := range byKey {
+		if len(members) < 2 {
+			continue
+		}
+
+		sort.SliceStable(members, func(i, j int) bool {
+			return dedupeLess(members[i], members[j], keep)
+		})
+
+		dups := make([]string, 0, len(members)-1)
+		for _, m := range members[1:] {
+			dups = append(dups, m.ID)
+		}
+		groups = append(groups, duplicateGroup{
+			Key:        key,
+			KeyType:    keyTypes[key],
+			Subject:    members[0].Subject,
+			Keep:       members[0].ID,
+			Duplicates: dups,
+		})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+// dedupeLess orders candidates so the copy to keep sorts first.
+func dedupeLess(a, b jmap.Email, keep string) bool {
+	if keep == dedupeKeepMostMailboxes && len(a.MailboxIDs) != len(b.MailboxIDs) {
+		return len(a.MailboxIDs) > len(b.MailboxIDs)
+	}
+	if a.ReceivedAt != b.ReceivedAt {
+		return a.ReceivedAt < b.ReceivedAt
+	}
+	return a.ID < b.ID
+}
+
+// dedupeKey returns the grouping key for an email: its Message-ID when
+// present, otherwise a hash of Date, From, Subject and size.
+func dedupeKey(e jmap.Email) (string, string) {
+	if len(e.MessageID) > 0 && strings.TrimSpace(e.MessageID[0]) != "" {
+		return strings.ToLower(strings.TrimSpace(e.MessageID[0])), dedupeKeyMessageID
+	}
+
+	from := ""
+	if len(e.From) > 0 {
+		from = strings.ToLower(e.From[0].Email)
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		e.SentAt,
+		from,
+		e.Subject,
+		strconv.FormatInt(e.Size, 10),
+	}, "\x00")))
+	return "sha256:" + hex.EncodeToString(sum[:16]), dedupeKeyHash
+}
+
+func printDuplicateGroups(groups []duplicateGroup, scanned int) {
+	total := 0
+	for _, g := range groups {
+		total += len(g.Duplicates)
+	}
+	fmt.Printf("Found %d duplicate groups (%d extra copies) in %d emails\n", len(groups), total, scanned)
+	for _, g := range groups {
+		fmt.Printf("  %s  keep %s, trash %s\n",
+			format.Truncate(g.Subject, 50),
+			g.Keep,
+			strings.Join(g.Duplicates, ", "),
+		)
+	}
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/spf13/cobra"
+)
+
+func TestGroupDuplicateEmails_ByMessageID(t *testing.T) {
+	emails := []jmap.Email{
+		{ID: "b", MessageID: []string{"<m1@example.com>"}, ReceivedAt: "2025-01-02T00:00:00Z", Subject: "Hello"},
+		{ID: "a", MessageID: []string{"<M1@example.com>"}, ReceivedAt: "2025-01-01T00:00:00Z", Subject: "Hello"},
+		{ID: "c", MessageID: []string{"<m2@example.com>"}, ReceivedAt: "2025-01-01T00:00:00Z"},
+	}
+
+	groups := groupDuplicateEmails(emails, dedupeKeepOldest)
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d: %+v", len(groups), groups)
+	}
+	g := groups[0]
+	if g.KeyType != dedupeKeyMessageID {
+		t.Errorf("KeyType = %q, want %q", g.KeyType, dedupeKeyMessageID)
+	}
+	if g.Keep != "a" {
+		t.Errorf("Keep = %q, want oldest copy %q", g.Keep, "a")
+	}
+	if !reflect.DeepEqual(g.Duplicates, []string{"b"}) {
+		t.Errorf("Duplicates = %v, want [b]", g.Duplicates)
+	}
+}
+
+func TestGroupDuplicateEmails_MostMailboxes(t *testing.T) {
+	emails := []jmap.Email{
+		{ID: "old", MessageID: []string{"<m@x>"}, ReceivedAt: "2025-01-01T00:00:00Z", MailboxIDs: map[string]bool{"inbox": true}},
+		{ID: "filed", MessageID: []string{"<m@x>"}, ReceivedAt: "2025-01-03T00:00:00Z", MailboxIDs: map[string]bool{"inbox": true, "work": true}},
+	}
+
+	groups := groupDuplicateEmails(emails, dedupeKeepMostMailboxes)
+	if len(groups) != 1 || groups[0].Keep != "filed" {
+		t.Fatalf("expected copy in most mailboxes to be kept, got %+v", groups)
+	}
+}
+
+func TestGroupDuplicateEmails_HashFallback(t *testing.T) {
+	from := []jmap.EmailAddress{{Email: "Alice@Example.com"}}
+	emails := []jmap.Email{
+		{ID: "e1", From: from, Subject: "Invoice", SentAt: "2025-01-01T10:00:00Z", Size: 1234, ReceivedAt: "2025-01-01T10:00:01Z"},
+		{ID: "e2", From: from, Subject: "Invoice", SentAt: "2025-01-01T10:00:00Z", Size: 1234, ReceivedAt: "2025-01-05T10:00:00Z"},
+		{ID: "e3", From: from, Subject: "Invoice", SentAt: "2025-01-01T10:00:00Z", Size: 999},
+	}
+
+	groups := groupDuplicateEmails(emails, dedupeKeepOldest)
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+	if groups[0].KeyType != dedupeKeyHash || !strings.HasPrefix(groups[0].Key, "sha256:") {
+		t.Errorf("expected hash key, got %q (%s)", groups[0].Key, groups[0].KeyType)
+	}
+	if groups[0].Keep != "e1" || !reflect.DeepEqual(groups[0].Duplicates, []string{"e2"}) {
+		t.Errorf("unexpected group: %+v", groups[0])
+	}
+}
+
+type fakeDedupeClient struct {
+	mailboxes   []jmap.Mailbox
+	emails      []jmap.Email
+	scanOpts    jmap.ScanEmailsOpts
+	deleteCalls [][]string
+}
+
+func (f *fakeDedupeClient) GetMailboxes(_ context.Context) ([]jmap.Mailbox, error) {
+	return f.mailboxes, nil
+}
+
+func (f *fakeDedupeClient) GetEmailSummaries(_ context.Context, ids []string) ([]jmap.Email, error) {
+	var emails []jmap.Email
+	for _, e := range f.emails {
+		if slices.Contains(ids, e.ID) {
+			emails = append(emails, e)
+		}
+	}
+	return emails, nil
+}
+
+func (f *fakeDedupeClient) ScanEmails(_ context.Context, opts jmap.ScanEmailsOpts) ([]jmap.Email, error) {
+	f.scanOpts = opts
+	return f.emails, nil
+}
+
+func (f *fakeDedupeClient) DeleteEmails(_ context.Context, ids []string) (*jmap.BulkResult, error) {
+	f.deleteCalls = append(f.deleteCalls, append([]string(nil), ids...))
+	return &jmap.BulkResult{Succeeded: ids, Failed: map[string]string{}}, nil
+}
+
+func TestRunEmailDedupe_ExcludesTrashAndTrashesDuplicates(t *testing.T) {
+	app := newTestApp()
+	app.Flags.Yes = true
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeDedupeClient{
+		mailboxes: []jmap.Mailbox{{ID: "trash-1", Name: "Trash", Role: "trash"}},
+		emails: []jmap.Email{
+			{ID: "a", MessageID: []string{"<m@x>"}, ReceivedAt: "2025-01-01T00:00:00Z"},
+			{ID: "b", MessageID: []string{"<m@x>"}, ReceivedAt: "2025-01-02T00:00:00Z"},
+			{ID: "c", MessageID: []string{"<m@x>"}, ReceivedAt: "2025-01-03T00:00:00Z"},
+		},
+	}
+
+	out := captureStdout(t, func() {
+		if err := runEmailDedupe(cmd, app, client, &commandPolicy{}, "", dedupeKeepOldest, 0, bulkBatchOptions{BatchSize: 50, Concurrency: 1}, false); err != nil {
+			t.Fatalf("runEmailDedupe error: %v", err)
+		}
+	})
+
+	if !reflect.DeepEqual(client.scanOpts.ExcludeMailboxIDs, []string{"trash-1"}) {
+		t.Errorf("expected trash to be excluded from scan, got %v", client.scanOpts.ExcludeMailboxIDs)
+	}
+	if len(client.deleteCalls) != 1 || !reflect.DeepEqual(client.deleteCalls[0], []string{"b", "c"}) {
+		t.Fatalf("expected duplicates [b c] to be trashed, got %v", client.deleteCalls)
+	}
+	if !strings.Contains(out, "Moved 2 duplicate emails to trash") {
+		t.Errorf("expected summary line, got %q", out)
+	}
+}
+
+func TestRunEmailDedupe_DryRunDoesNotTrash(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeDedupeClient{
+		emails: []jmap.Email{
+			{ID: "a", MessageID: []string{"<m@x>"}},
+			{ID: "b", MessageID: []string{"<m@x>"}},
+		},
+	}
+
+	out := captureStdout(t, func() {
+		if err := runEmailDedupe(cmd, app, client, &commandPolicy{}, "", dedupeKeepOldest, 0, bulkBatchOptions{BatchSize: 50, Concurrency: 1}, true); err != nil {
+			t.Fatalf("runEmailDedupe error: %v", err)
+		}
+	})
+
+	if len(client.deleteCalls) != 0 {
+		t.Fatalf("dry-run should not trash anything, got %v", client.deleteCalls)
+	}
+	if !strings.Contains(out, "Found 1 duplicate groups (1 extra copies) in 2 emails") {
+		t.Errorf("unexpected dry-run output: %q", out)
+	}
+}
+
+func TestEmailDedupe_RejectsZeroConcurrency(t *testing.T) {
+	captureStderr(t, func() {
+		err := Execute([]string{"--account", "me@example.com", "email", "dedupe", "--concurrency", "0"})
+		if !errors.Is(err, ErrUsage) || !strings.Contains(err.Error(), "--concurrency") {
+			t.Errorf("err = %v, want --concurrency usage error", err)
+		}
+	})
+}
+
+func TestEmailBatchedCommands_HaveConcurrencyFlag(t *testing.T) {
+	emailCmd := newEmailCmd(newTestApp())
+	for _, name := range []string{"dedupe", "spam", "not-spam"} {
+		cmd, _, err := emailCmd.Find([]string{name})
+		if err != nil || cmd.Name() != name {
+			t.Fatalf("expected %s command under email: %v", name, err)
+		}
+		flag := cmd.Flags().Lookup("concurrency")
+		if flag == nil || flag.DefValue != "1" {
+			t.Errorf("%s: expected --concurrency flag defaulting to 1, got %v", name, flag)
+		}
+	}
+}
@@ -0,0 +1,516 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
+	"github.com/salmonumbrella/fastmail-cli/internal/format"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultDuTop      = 10
+	defaultStripLarge = "1M"
+	defaultStripDir   = "stripped-attachments"
+)
+
+type duClient interface {
+	mailboxLookupClient
+	GetEmailSummaries(ctx context.Context, ids []string) ([]jmap.Email, error)
+	ScanEmails(ctx context.Context, opts jmap.ScanEmailsOpts) ([]jmap.Email, error)
+	DownloadBlob(ctx context.Context, blobID string) (io.ReadCloser, error)
+	UploadBlob(ctx context.Context, reader io.Reader, contentType string) (*jmap.UploadBlobResult, error)
+	ImportEmail(ctx context.Context, opts jmap.ImportEmailOpts) (string, error)
+	DeleteEmails(ctx context.Context, ids []string) (*jmap.BulkResult, error)
+}
+
+// mailboxUsage is the total size of the emails filed in one mailbox.
+type mailboxUsage struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Emails int    `json:"emails"`
+	Size   int64  `json:"size"`
+}
+
+// largeMessage is one entry in the largest-messages listing.
+type largeMessage struct {
+	ID          string `json:"id"`
+	Subject     string `json:"subject"`
+	From        string `json:"from"`
+	ReceivedAt  string `json:"receivedAt"`
+	Size        int64  `json:"size"`
+	Attachments int    `json:"attachments"`
+}
+
+// diskUsage is the storage report produced by 'email du'.
+type diskUsage struct {
+	Scanned            int               `json:"scanned"`
+	TotalSize          int64             `json:"totalSize"`
+	Mailboxes          []mailboxUsage    `json:"mailboxes"`
+	LargestMessages    []largeMessage    `json:"largestMessages"`
+	LargestAttachments []attachmentMatch `json:"largestAttachments"`
+}
+
+// strippedEmail records the outcome of stripping one message.
+type strippedEmail struct {
+	EmailID    string         `json:"emailId"`
+	NewEmailID string         `json:"newEmailId,omitempty"`
+	Subject    string         `json:"subject"`
+	Removed    []strippedPart `json:"removed"`
+	Error      string         `json:"error,omitempty"`
+}
+
+func newEmailDuCmd(app *App) *cobra.Command {
+	var mailbox string
+	var top int
+	var limit int
+	var strip bool
+	var larger string
+	var outputDir string
+
+	cmd := &cobra.Command{
+		Use:     "du",
+		Aliases: []string{"usage"},
+		Short:   "Show which mailboxes, messages and attachments use the most storage",
+		Long: `Report storage used per mailbox and list the largest messages and attachments.
+
+Mailbox totals sum the size of every email in the mailbox, so an email filed
+in several mailboxes counts towards each of them. The overall total counts
+each email once.
+
+With --strip-attachments, attachments larger than --larger in the --top
+largest messages are saved to --dir. A copy of each message without those
+attachments is imported into the same mailboxes (keeping its keywords and
+received date), and the original is moved to trash.
+
+Examples:
+  fastmail email du
+  fastmail email du --mailbox Archive --top 20
+  fastmail email du --strip-attachments --larger 5M --dir ~/mail-attachments --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: runE(app, func(cmd *cobra.Command, _ []string, app *App) error {
+			if top <= 0 {
+				return fmt.Errorf("%w: --top must be greater than 0", ErrUsage)
+			}
+			threshold, err := format.ParseBytes(larger)
+			if err != nil {
+				return fmt.Errorf("%w: --larger: %v", ErrUsage, err)
+			}
+
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+			policy, err := app.Policy()
+			if err != nil {
+				return err
+			}
+
+			return runEmailDu(cmd, app, client, policy, mailbox, top, limit, strip, threshold, outputDir, app.dryRun())
+		}),
+	}
+
+	cmd.Flags().StringVar(&mailbox, "mailbox", "", "Only report on this mailbox (ID or name)")
+	cmd.Flags().IntVar(&top, "top", defaultDuTop, "Number of largest messages and attachments to list")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of emails to scan (0 = all)")
+	cmd.Flags().BoolVar(&strip, "strip-attachments", false, "Save large attachments locally and remove them from the largest messages")
+	cmd.Flags().StringVar(&larger, "larger", defaultStripLarge, "Only strip attachments larger than this size")
+	cmd.Flags().StringVarP(&outputDir, "dir", "d", defaultStripDir, "Directory for stripped attachments (created if it doesn't exist)")
+
+	return cmd
+}
+
+func runEmailDu(cmd *cobra.Command, app *App, client duClient, policy *commandPolicy, mailbox string, top, limit int, strip bool, threshold int64, outputDir string, dryRun bool) error {
+	ctx := cmd.Context()
+
+	mailboxes, err := client.GetMailboxes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get mailboxes: %w", err)
+	}
+
+	opts := jmap.ScanEmailsOpts{Sort: "size", IncludeAttachments: true, Limit: limit}
+	if mailbox != "" {
+		mailboxID, _, err := resolveMailboxTarget(ctx, client, mailbox)
+		if err != nil {
+			return err
+		}
+		opts.MailboxID = mailboxID
+	}
+
+	emails, err := client.ScanEmails(ctx, opts)
+	if err != nil {
+		return cerrors.WithContext(err, "scanning emails")
+	}
+
+	report := buildDiskUsage(emails, mailboxes, top)
+
+	if !strip {
+		if app.IsJSON(ctx) {
+			return app.PrintJSON(cmd, report)
+		}
+		printDiskUsage(report)
+		return nil
+	}
+
+	candidates := stripCandidates(emails, top, threshold)
+	output := map[string]any{
+		"scanned":            report.Scanned,
+		"totalSize":          report.TotalSize,
+		"mailboxes":          report.Mailboxes,
+		"largestMessages":    report.LargestMessages,
+		"largestAttachments": report.LargestAttachments,
+		"threshold":          threshold,
+		"dir":                outputDir,
+	}
+
+	if len(candidates) == 0 {
+		if app.IsJSON(ctx) {
+			output["stripped"] = []strippedEmail{}
+			return app.PrintJSON(cmd, output)
+		}
+		printDiskUsage(report)
+		printNoResults("\nNo attachments larger than %s in the %d largest messages", format.FormatBytes(threshold), top)
+		return nil
+	}
+
+	if dryRun {
+		planned := make([]strippedEmail, 0, len(candidates))
+		for _, e := range candidates {
+			planned = append(planned, plannedStrip(e, threshold))
+		}
+		if app.IsJSON(ctx) {
+			output["dryRun"] = true
+			output["stripped"] = planned
+			return app.PrintJSON(cmd, output)
+		}
+		printDiskUsage(report)
+		fmt.Printf("\nWould strip attachments from %d messages:\n", len(planned))
+		for _, p := range planned {
+			for _, part := range p.Removed {
+				fmt.Printf("  %s  %s (%s)\n", format.Truncate(p.Subject, 40), part.Name, format.FormatBytes(part.Size))
+			}
+		}
+		return nil
+	}
+
+	// Stripping trashes each original, so protected mailboxes apply.
+	candidateIDs := make([]string, 0, len(candidates))
+	for _, e := range candidates {
+		candidateIDs = append(candidateIDs, e.ID)
+	}
+	if err := policy.checkBulkDelete(ctx, client, candidateIDs); err != nil {
+		return err
+	}
+
+	if !app.IsJSON(ctx) {
+		printDiskUsage(report)
+		fmt.Println()
+	}
+
+	confirmed, err := app.Confirm(cmd, false, fmt.Sprintf("Strip attachments larger than %s from %d messages (originals go to trash)? [y/N] ", format.FormatBytes(threshold), len(candidates)), "y", "yes")
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		printCancelled()
+		return nil
+	}
+
+	if err := os.MkdirAll(outputDir, 0o750); err != nil {
+		return fmt.Errorf("failed to create directory '%s': %w", outputDir, err)
+	}
+
+	results := make([]strippedEmail, 0, len(candidates))
+	failed := 0
+	for _, e := range candidates {
+		result := stripEmailAttachments(ctx, client, e, threshold, outputDir)
+		if result.Error != "" {
+			failed++
+		}
+		results = append(results, result)
+
+		if !app.IsJSON(ctx) {
+			if result.Error != "" {
+				fmt.Printf("Error stripping %s: %s\n", e.ID, result.Error)
+				continue
+			}
+			for _, part := range result.Removed {
+				fmt.Printf("Saved %s (%s)\n", part.SavedTo, format.FormatBytes(part.Size))
+			}
+		}
+	}
+
+	if app.IsJSON(ctx) {
+		output["stripped"] = results
+		return app.PrintJSON(cmd, output)
+	}
+
+	fmt.Printf("\nStripped attachments from %d messages", len(results)-failed)
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	fmt.Println()
+	return nil
+}
+
+// buildDiskUsage summarizes scanned emails into per-mailbox totals and the
+// top largest messages and attachments.
+func buildDiskUsage(emails []jmap.Email, mailboxes []jmap.Mailbox, top int) diskUsage {
+	names := make(map[string]string, len(mailboxes))
+	for _, mb := range mailboxes {
+		names[mb.ID] = mb.Name
+	}
+
+	report := diskUsage{
+		Scanned:            len(emails),
+		Mailboxes:          []mailboxUsage{},
+		LargestMessages:    []largeMessage{},
+		LargestAttachments: []attachmentMatch{},
+	}
+
+	usage := make(map[string]*mailboxUsage)
+	var attachments []attachmentMatch
+	seenBlobs := make(map[string]bool)
+	for _, e := range emails {
+		report.TotalSize += e.Size
+		for id := range e.MailboxIDs {
+			u, ok := usage[id]
+			if !ok {
+				name := names[id]
+				if name == "" {
+					name = id
+				}
+				u = &mailboxUsage{ID: id, Name: name}
+				usage[id] = u
+			}
+			u.Emails++
+			u.Size += e.Size
+		}
+
+		from := ""
+		if len(e.From) > 0 {
+			from = e.From[0].Email
+		}
+		for _, att := range e.Attachments {
+			if att.BlobID == "" || seenBlobs[att.BlobID] {
+				continue
+			}
+			seenBlobs[att.BlobID] = true
+			attachments = append(attachments, attachmentMatch{
+				EmailID:    e.ID,
+				ReceivedAt: e.ReceivedAt,
+				From:       from,
+				Subject:    e.Subject,
+				BlobID:     att.BlobID,
+				Name:       att.Name,
+				Type:       att.Type,
+				Size:       att.Size,
+			})
+		}
+	}
+
+	for _, u := range usage {
+		report.Mailboxes = append(report.Mailboxes, *u)
+	}
+	sort.Slice(report.Mailboxes, func(i, j int) bool {
+		if report.Mailboxes[i].Size != report.Mailboxes[j].Size {
+			return report.Mailboxes[i].Size > report.Mailboxes[j].Size
+		}
+		return report.Mailboxes[i].Name < report.Mailboxes[j].Name
+	})
+
+	largest := append([]jmap.Email(nil), emails...)
+	sort.SliceStable(largest, func(i, j int) bool { return largest[i].Size > largest[j].Size })
+	for _, e := range largest[:min(top, len(largest))] {
+		from := ""
+		if len(e.From) > 0 {
+			from = e.From[0].Email
+		}
+		report.LargestMessages = append(report.LargestMessages, largeMessage{
+			ID:          e.ID,
+			Subject:     e.Subject,
+			From:        from,
+			ReceivedAt:  e.ReceivedAt,
+			Size:        e.Size,
+			Attachments: len(e.Attachments),
+		})
+	}
+
+	sort.SliceStable(attachments, func(i, j int) bool { return attachments[i].Size > attachments[j].Size })
+	report.LargestAttachments = append(report.LargestAttachments, attachments[:min(top, len(attachments))]...)
+
+	return report
+}
+
+// stripCandidates returns the top largest emails that have at least one
+// attachment larger than threshold.
+func stripCandidates(emails []jmap.Email, top int, threshold int64) []jmap.Email {
+	largest := append([]jmap.Email(nil), emails...)
+	sort.SliceStable(largest, func(i, j int) bool { return largest[i].Size > largest[j].Size })
+
+	var candidates []jmap.Email
+	for _, e := range largest[:min(top, len(largest))] {
+		if len(plannedStrip(e, threshold).Removed) > 0 {
+			candidates = append(candidates, e)
+		}
+	}
+	return candidates
+}
+
+// plannedStrip describes what stripping e would remove, based on the
+// attachment metadata returned by the server.
+func plannedStrip(e jmap.Email, threshold int64) strippedEmail {
+	planned := strippedEmail{EmailID: e.ID, Subject: e.Subject, Removed: []strippedPart{}}
+	for _, att := range e.Attachments {
+		if att.Size > threshold {
+			planned.Removed = append(planned.Removed, strippedPart{Name: att.Name, Type: att.Type, Size: att.Size})
+		}
+	}
+	return planned
+}
+
+// stripEmailAttachments saves the large attachments of e to dir, imports a
+// copy of the message without them and moves the original to trash. The
+// original is only trashed once the copy has been imported.
+func stripEmailAttachments(ctx context.Context, client duClient, e jmap.Email, threshold int64, dir string) strippedEmail {
+	result := strippedEmail{EmailID: e.ID, Subject: e.Subject, Removed: []strippedPart{}}
+	fail := func(step string, err error) strippedEmail {
+		result.Error = fmt.Sprintf("%s: %v", step, err)
+		return result
+	}
+
+	if e.BlobID == "" {
+		return fail("download", errors.New("message has no blob ID"))
+	}
+	reader, err := client.DownloadBlob(ctx, e.BlobID)
+	if err != nil {
+		return fail("download", err)
+	}
+	raw, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		return fail("download", err)
+	}
+
+	stripped, removed, err := stripAttachmentParts(raw, threshold, func(name, _ string, data []byte) (string, error) {
+		return writeUniqueFile(dir, e.ID+"_"+name, data)
+	})
+	if err != nil {
+		return fail("strip", err)
+	}
+	if len(removed) == 0 {
+		return fail("strip", errors.New("no attachments above the size limit found in the raw message"))
+	}
+	result.Removed = removed
+
+	upload, err := client.UploadBlob(ctx, bytes.NewReader(stripped), "message/rfc822")
+	if err != nil {
+		return fail("upload", err)
+	}
+
+	newID, err := client.ImportEmail(ctx, jmap.ImportEmailOpts{
+		BlobID:     upload.BlobID,
+		MailboxIDs: e.MailboxIDs,
+		Keywords:   e.Keywords,
+		ReceivedAt: e.ReceivedAt,
+	})
+	if err != nil {
+		return fail("import", err)
+	}
+	result.NewEmailID = newID
+
+	trashed, err := client.DeleteEmails(ctx, []string{e.ID})
+	if err != nil {
+		return fail("trash original", err)
+	}
+	if reason, ok := trashed.Failed[e.ID]; ok {
+		return fail("trash original", errors.New(reason))
+	}
+
+	return result
+}
+
+// writeUniqueFile writes data to dir/name, adding -2, -3, ... before the
+// extension if a file with that name already exists.
+func writeUniqueFile(dir, name string, data []byte) (string, error) {
+	name = format.SanitizeFilename(name)
+	if name == "" {
+		name = "attachment"
+	}
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	candidate := filepath.Join(dir, name)
+	for n := 2; ; n++ {
+		f, err := os.OpenFile(candidate, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			candidate = filepath.Join(dir, fmt.Sprintf("%s-%d%s", base, n, ext))
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			_ = os.Remove(candidate)
+			return "", err
+		}
+		if err := f.Close(); err != nil {
+			_ = os.Remove(candidate)
+			return "", err
+		}
+		return candidate, nil
+	}
+}
+
+func printDiskUsage(report diskUsage) {
+	fmt.Printf("Scanned %d emails, %s total\n\n", report.Scanned, format.FormatBytes(report.TotalSize))
+
+	tw := outfmt.NewTabWriter()
+	fmt.Fprintln(tw, "MAILBOX\tEMAILS\tSIZE")
+	for _, u := range report.Mailboxes {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", outfmt.SanitizeTab(u.Name), u.Emails, format.FormatBytes(u.Size))
+	}
+	tw.Flush()
+
+	if len(report.LargestMessages) > 0 {
+		fmt.Println("\nLargest messages:")
+		tw = outfmt.NewTabWriter()
+		fmt.Fprintln(tw, "ID\tSIZE\tDATE\tFROM\tSUBJECT")
+		for _, m := range report.LargestMessages {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+				m.ID,
+				format.FormatBytes(m.Size),
+				format.FormatEmailDate(m.ReceivedAt),
+				outfmt.SanitizeTab(format.Truncate(m.From, 30)),
+				outfmt.SanitizeTab(format.Truncate(m.Subject, 50)),
+			)
+		}
+		tw.Flush()
+	}
+
+	if len(report.LargestAttachments) > 0 {
+		fmt.Println("\nLargest attachments:")
+		tw = outfmt.NewTabWriter()
+		fmt.Fprintln(tw, "EMAIL ID\tSIZE\tTYPE\tNAME")
+		for _, a := range report.LargestAttachments {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+				a.EmailID,
+				format.FormatBytes(a.Size),
+				a.Type,
+				outfmt.SanitizeTab(a.Name),
+			)
+		}
+		tw.Flush()
+	}
+}
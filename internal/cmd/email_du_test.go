@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/spf13/cobra"
+)
+
+const rawMessageWithAttachment = "From: alice@example.com\r\n" +
+	"Subject: Photos\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"outer\"\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"See attached.\r\n" +
+	"--outer\r\n" +
+	"Content-Type: image/jpeg; name=\"beach.jpg\"\r\n" +
+	"Content-Disposition: attachment; filename=\"beach.jpg\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"aGVsbG8gd29ybGQgaGVsbG8gd29ybGQ=\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/plain; name=\"tiny.txt\"\r\n" +
+	"Content-Disposition: attachment; filename=\"tiny.txt\"\r\n" +
+	"\r\n" +
+	"hi\r\n" +
+	"--outer--\r\n"
+
+func TestStripAttachmentParts(t *testing.T) {
+	var saved []string
+	out, removed, err := stripAttachmentParts([]byte(rawMessageWithAttachment), 10, func(name, _ string, data []byte) (string, error) {
+		saved = append(saved, name+"="+string(data))
+		return "/tmp/" + name, nil
+	})
+	if err != nil {
+		t.Fatalf("stripAttachmentParts error: %v", err)
+	}
+
+	if len(removed) != 1 || removed[0].Name != "beach.jpg" || removed[0].Type != "image/jpeg" || removed[0].Size != 23 {
+		t.Fatalf("unexpected removed parts: %+v", removed)
+	}
+	if len(saved) != 1 || saved[0] != "beach.jpg=hello world hello world" {
+		t.Errorf("unexpected saved content: %v", saved)
+	}
+
+	msg := string(out)
+	if !strings.HasPrefix(msg, "From: alice@example.com\r\nSubject: Photos\r\n") {
+		t.Errorf("top-level headers should be preserved, got %q", msg[:60])
+	}
+	if strings.Contains(msg, "aGVsbG8") {
+		t.Error("stripped message still contains attachment data")
+	}
+	if !strings.Contains(msg, "See attached.") || !strings.Contains(msg, "tiny.txt") {
+		t.Error("stripped message lost parts below the size limit")
+	}
+	if !strings.Contains(msg, "A copy was saved to: /tmp/beach.jpg") {
+		t.Error("stripped message is missing the placeholder note")
+	}
+}
+
+func TestStripAttachmentParts_SinglePart(t *testing.T) {
+	raw := []byte("Subject: plain\r\n\r\nbody\r\n")
+	out, removed, err := stripAttachmentParts(raw, 0, func(string, string, []byte) (string, error) {
+		t.Fatal("save should not be called")
+		return "", nil
+	})
+	if err != nil || len(removed) != 0 || !bytes.Equal(out, raw) {
+		t.Fatalf("expected message to be returned unchanged, got %q %v %v", out, removed, err)
+	}
+}
+
+func TestBuildDiskUsage(t *testing.T) {
+	mailboxes := []jmap.Mailbox{{ID: "inbox", Name: "Inbox"}, {ID: "archive", Name: "Archive"}}
+	emails := []jmap.Email{
+		{ID: "small", Size: 100, MailboxIDs: map[string]bool{"inbox": true}},
+		{ID: "big", Size: 5000, MailboxIDs: map[string]bool{"archive": true, "inbox": true},
+			Attachments: []jmap.Attachment{{BlobID: "b1", Name: "a.zip", Size: 4000}, {BlobID: "b2", Name: "b.png", Size: 500}}},
+		{ID: "mid", Size: 1000, MailboxIDs: map[string]bool{"archive": true}},
+	}
+
+	report := buildDiskUsage(emails, mailboxes, 2)
+
+	if report.Scanned != 3 || report.TotalSize != 6100 {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+	if len(report.Mailboxes) != 2 || report.Mailboxes[0].Name != "Archive" || report.Mailboxes[0].Size != 6000 || report.Mailboxes[1].Size != 5100 {
+		t.Errorf("unexpected mailbox usage: %+v", report.Mailboxes)
+	}
+	if len(report.LargestMessages) != 2 || report.LargestMessages[0].ID != "big" || report.LargestMessages[1].ID != "mid" {
+		t.Errorf("unexpected largest messages: %+v", report.LargestMessages)
+	}
+	if len(report.LargestAttachments) != 2 || report.LargestAttachments[0].Name != "a.zip" {
+		t.Errorf("unexpected largest attachments: %+v", report.LargestAttachments)
+	}
+}
+
+type fakeDuClient struct {
+	fakeDedupeClient
+	raw      map[string]string
+	uploaded []byte
+	imported []jmap.ImportEmailOpts
+}
+
+func (f *fakeDuClient) DownloadBlob(_ context.Context, blobID string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(f.raw[blobID])), nil
+}
+
+func (f *fakeDuClient) UploadBlob(_ context.Context, reader io.Reader, _ string) (*jmap.UploadBlobResult, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	f.uploaded = data
+	return &jmap.UploadBlobResult{BlobID: "new-blob"}, nil
+}
+
+func (f *fakeDuClient) ImportEmail(_ context.Context, opts jmap.ImportEmailOpts) (string, error) {
+	f.imported = append(f.imported, opts)
+	return "new-email", nil
+}
+
+func TestRunEmailDu_StripAttachments(t *testing.T) {
+	dir := t.TempDir()
+	app := newTestApp()
+	app.Flags.Yes = true
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeDuClient{
+		fakeDedupeClient: fakeDedupeClient{
+			mailboxes: []jmap.Mailbox{{ID: "inbox", Name: "Inbox"}},
+			emails: []jmap.Email{{
+				ID: "e1", BlobID: "raw1", Subject: "Photos", Size: 900,
+				ReceivedAt: "2025-01-01T00:00:00Z",
+				MailboxIDs: map[string]bool{"inbox": true},
+				Keywords:   map[string]bool{"$seen": true},
+				Attachments: []jmap.Attachment{
+					{BlobID: "b1", Name: "beach.jpg", Type: "image/jpeg", Size: 23},
+				},
+			}},
+		},
+		raw: map[string]string{"raw1": rawMessageWithAttachment},
+	}
+
+	out := captureStdout(t, func() {
+		if err := runEmailDu(cmd, app, client, &commandPolicy{}, "", 10, 0, true, 10, dir, false); err != nil {
+			t.Fatalf("runEmailDu error: %v", err)
+		}
+	})
+
+	if client.scanOpts.Sort != "size" || !client.scanOpts.IncludeAttachments {
+		t.Errorf("unexpected scan options: %+v", client.scanOpts)
+	}
+	saved, err := os.ReadFile(filepath.Join(dir, "e1_beach.jpg"))
+	if err != nil || string(saved) != "hello world hello world" {
+		t.Fatalf("attachment not saved: %q %v", saved, err)
+	}
+	if len(client.imported) != 1 || client.imported[0].BlobID != "new-blob" || !client.imported[0].Keywords["$seen"] || client.imported[0].ReceivedAt != "2025-01-01T00:00:00Z" {
+		t.Fatalf("unexpected import: %+v", client.imported)
+	}
+	if bytes.Contains(client.uploaded, []byte("aGVsbG8")) {
+		t.Error("uploaded copy still contains the attachment")
+	}
+	if len(client.deleteCalls) != 1 || client.deleteCalls[0][0] != "e1" {
+		t.Fatalf("expected original to be trashed, got %v", client.deleteCalls)
+	}
+	if !strings.Contains(out, "Stripped attachments from 1 messages") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestRunEmailDu_StripDryRun(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeDuClient{fakeDedupeClient: fakeDedupeClient{
+		emails: []jmap.Email{{ID: "e1", Size: 900, Attachments: []jmap.Attachment{{BlobID: "b1", Name: "big.zip", Size: 800}}}},
+	}}
+
+	out := captureStdout(t, func() {
+		if err := runEmailDu(cmd, app, client, &commandPolicy{}, "", 10, 0, true, 100, t.TempDir(), true); err != nil {
+			t.Fatalf("runEmailDu error: %v", err)
+		}
+	})
+
+	if len(client.imported) != 0 || len(client.deleteCalls) != 0 {
+		t.Fatal("dry-run should not change anything")
+	}
+	if !strings.Contains(out, "Would strip attachments from 1 messages") || !strings.Contains(out, "big.zip") {
+		t.Errorf("unexpected dry-run output: %q", out)
+	}
+}
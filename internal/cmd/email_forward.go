@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -10,13 +11,22 @@ import (
 	"github.com/spf13/cobra"
 )
 
+type forwardAttachedClient interface {
+	GetThread(ctx context.Context, threadID string) ([]jmap.Email, error)
+	GetEmailSummaries(ctx context.Context, ids []string) ([]jmap.Email, error)
+	ResolveForwardFrom(ctx context.Context, original *jmap.Email, opts jmap.ForwardEmailOpts) (string, jmap.ForwardFromSource, error)
+	ForwardEmailsAsAttachments(ctx context.Context, originals []jmap.Email, opts jmap.ForwardEmailOpts) (string, error)
+}
+
 func newEmailForwardCmd(app *App) *cobra.Command {
 	var to []string
 	var fromIdentity string
 	var body string
+	var asAttachment bool
+	var thread bool
 
 	cmd := &cobra.Command{
-		Use:     "forward <emailId>",
+		Use:     "forward <emailId>...",
 		Aliases: []string{"fwd"},
 		Short:   "Forward an email",
 		Long: `Forward an email to one or more recipients.
@@ -27,12 +37,19 @@ Use --from to override this behavior.
 
 Attachments from the original email are automatically included.
 
+With --as-attachment, the original message is attached as a .eml file
+(message/rfc822) instead of being quoted inline. Forwarding several emails, or
+whole threads with --thread, always attaches each message as a .eml file.
+
 Examples:
   fastmail email forward Mf1234abc --to recipient@example.com
   fastmail email forward Mf1234abc --to user1@example.com --to user2@example.com
   fastmail email forward Mf1234abc --to recipient@example.com --body "FYI, see below"
-  fastmail email forward Mf1234abc --to recipient@example.com --from my.identity@fastmail.com`,
-		Args: cobra.ExactArgs(1),
+  fastmail email forward Mf1234abc --to recipient@example.com --from my.identity@fastmail.com
+  fastmail email forward Mf1234abc --to abuse@example.com --as-attachment
+  fastmail email forward Mf1 Mf2 Mf3 --to recipient@example.com
+  fastmail email forward Mf1234abc --thread --to recipient@example.com`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
 			emailID := args[0]
 
@@ -47,24 +64,35 @@ Examples:
 				}
 			}
 
-			client, err := app.JMAPClient()
+			policy, err := app.Policy()
 			if err != nil {
 				return err
 			}
+			if err := policy.checkRecipients(to); err != nil {
+				return err
+			}
 
-			// Fetch the original email
-			original, err := client.GetEmailByID(cmd.Context(), emailID)
+			client, err := app.JMAPClient()
 			if err != nil {
-				return cerrors.WithContext(err, "fetching email")
+				return err
 			}
 
-			// Build forward options
 			opts := jmap.ForwardEmailOpts{
 				To:   to,
 				From: fromIdentity,
 				Body: body,
 			}
 
+			if asAttachment || thread || len(args) > 1 {
+				return runEmailForwardAttached(cmd, app, client, args, thread, opts)
+			}
+
+			// Fetch the original email
+			original, err := client.GetEmailByID(cmd.Context(), emailID)
+			if err != nil {
+				return cerrors.WithContext(err, "fetching email")
+			}
+
 			resolvedFrom, fromSource, err := client.ResolveForwardFrom(cmd.Context(), original, opts)
 			if err != nil {
 				return cerrors.WithContext(err, "resolving forward from")
@@ -106,6 +134,87 @@ Examples:
 	cmd.Flags().StringSliceVar(&to, "to", nil, "Recipient email addresses (required)")
 	cmd.Flags().StringVar(&fromIdentity, "from", "", "Send from this identity or masked email (default: auto-detect from original)")
 	cmd.Flags().StringVar(&body, "body", "", "Optional message to prepend to the forwarded email")
+	cmd.Flags().BoolVar(&asAttachment, "as-attachment", false, "Attach the original message as a .eml file instead of quoting it inline")
+	cmd.Flags().BoolVar(&thread, "thread", false, "Forward every message in the threads of the given emails (implies --as-attachment)")
 
 	return cmd
 }
+
+// runEmailForwardAttached forwards ids as message/rfc822 attachments in a
+// single email. With thread, each ID is expanded to all emails in its thread.
+func runEmailForwardAttached(cmd *cobra.Command, app *App, client forwardAttachedClient, ids []string, thread bool, opts jmap.ForwardEmailOpts) error {
+	ctx := cmd.Context()
+
+	if thread {
+		var expanded []string
+		for _, id := range ids {
+			emails, err := client.GetThread(ctx, id)
+			if err != nil {
+				return cerrors.WithContext(err, "fetching thread")
+			}
+			for _, e := range emails {
+				expanded = append(expanded, e.ID)
+			}
+		}
+		ids = expanded
+	}
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	ids = unique
+	if len(ids) == 0 {
+		return fmt.Errorf("no emails to forward")
+	}
+
+	emails, err := client.GetEmailSummaries(ctx, ids)
+	if err != nil {
+		return cerrors.WithContext(err, "fetching emails")
+	}
+	byID := make(map[string]jmap.Email, len(emails))
+	for _, e := range emails {
+		byID[e.ID] = e
+	}
+	// Keep the attachments in the order the emails were given.
+	originals := make([]jmap.Email, 0, len(ids))
+	for _, id := range ids {
+		e, ok := byID[id]
+		if !ok {
+			return fmt.Errorf("email not found: %s", id)
+		}
+		originals = append(originals, e)
+	}
+
+	resolvedFrom, fromSource, err := client.ResolveForwardFrom(ctx, &originals[0], opts)
+	if err != nil {
+		return cerrors.WithContext(err, "resolving forward from")
+	}
+	opts.From = resolvedFrom
+
+	submissionID, err := client.ForwardEmailsAsAttachments(ctx, originals, opts)
+	if err != nil {
+		return cerrors.WithContext(err, "forwarding emails")
+	}
+
+	if app.IsJSON(ctx) {
+		return app.PrintJSON(cmd, map[string]any{
+			"submissionId":     submissionID,
+			"status":           "sent",
+			"originalEmailIds": ids,
+			"asAttachment":     true,
+			"forwardedTo":      opts.To,
+			"from":             resolvedFrom,
+			"fromSource":       fromSource,
+		})
+	}
+
+	fmt.Printf("Email forwarded successfully (submission ID: %s)\n", submissionID)
+	fmt.Printf("  From: %s (%s)\n", resolvedFrom, fromSource)
+	fmt.Printf("  To: %s\n", strings.Join(opts.To, ", "))
+	fmt.Printf("  Attached: %d messages as .eml\n", len(originals))
+	return nil
+}
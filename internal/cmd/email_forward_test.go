@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/spf13/cobra"
+)
+
+type fakeForwardClient struct {
+	threads   map[string][]jmap.Email
+	emails    map[string]jmap.Email
+	forwarded []jmap.Email
+	opts      jmap.ForwardEmailOpts
+}
+
+func (f *fakeForwardClient) GetThread(_ context.Context, id string) ([]jmap.Email, error) {
+	return f.threads[id], nil
+}
+
+func (f *fakeForwardClient) GetEmailSummaries(_ context.Context, ids []string) ([]jmap.Email, error) {
+	var out []jmap.Email
+	for _, id := range ids {
+		if e, ok := f.emails[id]; ok {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeForwardClient) ResolveForwardFrom(_ context.Context, _ *jmap.Email, _ jmap.ForwardEmailOpts) (string, jmap.ForwardFromSource, error) {
+	return "me@example.com", jmap.ForwardFromDefault, nil
+}
+
+func (f *fakeForwardClient) ForwardEmailsAsAttachments(_ context.Context, originals []jmap.Email, opts jmap.ForwardEmailOpts) (string, error) {
+	f.forwarded = originals
+	f.opts = opts
+	return "sub1", nil
+}
+
+func TestRunEmailForwardAttached_Thread(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeForwardClient{
+		threads: map[string][]jmap.Email{"e2": {{ID: "e1"}, {ID: "e2"}}},
+		emails: map[string]jmap.Email{
+			"e1": {ID: "e1", BlobID: "b1"},
+			"e2": {ID: "e2", BlobID: "b2"},
+		},
+	}
+
+	out := captureStdout(t, func() {
+		err := runEmailForwardAttached(cmd, app, client, []string{"e2", "e2"}, true, jmap.ForwardEmailOpts{To: []string{"a@example.com"}})
+		if err != nil {
+			t.Fatalf("runEmailForwardAttached error: %v", err)
+		}
+	})
+
+	var got []string
+	for _, e := range client.forwarded {
+		got = append(got, e.ID)
+	}
+	if !reflect.DeepEqual(got, []string{"e1", "e2"}) {
+		t.Fatalf("forwarded = %v, want thread order without duplicates", got)
+	}
+	if client.opts.From != "me@example.com" {
+		t.Errorf("resolved From not passed through: %+v", client.opts)
+	}
+	if !strings.Contains(out, "Attached: 2 messages as .eml") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestRunEmailForwardAttached_MissingEmail(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeForwardClient{emails: map[string]jmap.Email{"e1": {ID: "e1", BlobID: "b1"}}}
+
+	err := runEmailForwardAttached(cmd, app, client, []string{"e1", "gone"}, false, jmap.ForwardEmailOpts{To: []string{"a@example.com"}})
+	if err == nil || !strings.Contains(err.Error(), "gone") {
+		t.Fatalf("expected not-found error, got %v", err)
+	}
+	if client.forwarded != nil {
+		t.Error("nothing should be sent when an email is missing")
+	}
+}
@@ -19,7 +19,10 @@ func newEmailImportCmd(app *App) *cobra.Command {
 		Long: `Import a raw RFC 5322 email message (.eml file) into your mailbox.
 
 The email will be imported with its original headers and content.
-By default, emails are imported to the Inbox and marked as unread.`,
+By default, emails are imported to the Inbox and marked as unread.
+
+With --dry-run, the file is uploaded and parsed but not imported, showing the
+email that would be created.`,
 		Args: cobra.ExactArgs(1),
 		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
 			client, err := app.JMAPClient()
@@ -70,6 +73,27 @@ By default, emails are imported to the Inbox and marked as unread.`,
 				return fmt.Errorf("failed to upload email: %w", err)
 			}
 
+			if app.dryRun() {
+				email, err := client.ParseEmail(cmd.Context(), uploadResult.BlobID)
+				if err != nil {
+					return cerrors.WithContext(err, "parsing email")
+				}
+
+				if app.IsJSON(cmd.Context()) {
+					return app.PrintJSON(cmd, map[string]any{
+						"dryRun":    true,
+						"blobId":    uploadResult.BlobID,
+						"mailboxId": targetMailboxID,
+						"file":      emlPath,
+						"email":     email,
+					})
+				}
+
+				fmt.Printf("Would import %s into mailbox %s:\n\n", emlPath, targetMailboxID)
+				printParsedEmail(email)
+				return nil
+			}
+
 			// Build import options
 			opts := jmap.ImportEmailOpts{
 				BlobID:     uploadResult.BlobID,
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
@@ -20,7 +22,31 @@ func newEmailListCmd(app *App) *cobra.Command {
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List emails",
+		Long: `List the most recent emails, optionally in one mailbox.
+
+With --account all (or a comma-separated list of accounts), every account is
+queried concurrently and the newest --limit emails across them are shown, each
+tagged with its account.`,
 		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
+			accounts, multi, err := app.MultiAccounts()
+			if err != nil {
+				return err
+			}
+			if multi {
+				return runMultiAccountEmails(cmd, app, accounts, limit, light, false, func(ctx context.Context, client *jmap.Client) ([]jmap.Email, []jmap.SearchSnippet, error) {
+					mbID := ""
+					if mailboxID != "" {
+						resolved, err := client.ResolveMailboxID(ctx, mailboxID)
+						if err != nil {
+							return nil, nil, fmt.Errorf("invalid mailbox: %w", err)
+						}
+						mbID = resolved
+					}
+					emails, err := client.GetEmails(ctx, mbID, limit)
+					return emails, nil, err
+				})
+			}
+
 			client, err := app.JMAPClient()
 			if err != nil {
 				return err
@@ -112,23 +138,38 @@ Examples:
   fastmail email search --snippets "invoice"
   fastmail email search "subject:meeting after:2025-01-01"
   fastmail email search "subject:meeting after:yesterday"
-  fastmail email search "subject:meeting after:'2h ago'"`,
+  fastmail email search "subject:meeting after:'2h ago'"
+  fastmail email search --account all "invoice"`,
 		Args: cobra.ExactArgs(1),
 		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
-			client, err := app.JMAPClient()
+			// Parse the query into JMAP filter components
+			filter, err := parseEmailSearchFilter(args[0], time.Now())
 			if err != nil {
 				return err
 			}
 
-			var emails []jmap.Email
-			var searchSnippets []jmap.SearchSnippet
+			accounts, multi, err := app.MultiAccounts()
+			if err != nil {
+				return err
+			}
+			if multi {
+				return runMultiAccountEmails(cmd, app, accounts, limit, light, snippets, func(ctx context.Context, client *jmap.Client) ([]jmap.Email, []jmap.SearchSnippet, error) {
+					if snippets {
+						return client.SearchEmailsWithSnippets(ctx, filter, limit)
+					}
+					emails, err := client.SearchEmails(ctx, filter, limit)
+					return emails, nil, err
+				})
+			}
 
-			// Parse the query into JMAP filter components
-			filter, err := parseEmailSearchFilter(args[0], time.Now())
+			client, err := app.JMAPClient()
 			if err != nil {
 				return err
 			}
 
+			var emails []jmap.Email
+			var searchSnippets []jmap.SearchSnippet
+
 			if snippets {
 				emails, searchSnippets, err = client.SearchEmailsWithSnippets(cmd.Context(), filter, limit)
 			} else {
@@ -222,6 +263,140 @@ Examples:
 	return cmd
 }
 
+// accountEmailRow is one email in a multi-account listing.
+type accountEmailRow struct {
+	account     string
+	email       jmap.Email
+	threadCount int
+	snippet     *jmap.SearchSnippet
+}
+
+// accountEmails is one account's share of a multi-account listing.
+type accountEmails struct {
+	emails       []jmap.Email
+	threadCounts map[string]int
+	snippets     []jmap.SearchSnippet
+}
+
+// runMultiAccountEmails runs fetch against each account concurrently and
+// prints the newest limit emails across all of them, tagged by account.
+func runMultiAccountEmails(cmd *cobra.Command, app *App, accounts []string, limit int, light, snippets bool, fetch func(context.Context, *jmap.Client) ([]jmap.Email, []jmap.SearchSnippet, error)) error {
+	ctx := cmd.Context()
+
+	results, errs := fanOut(ctx, accounts, app.JMAPClientFor, func(ctx context.Context, client *jmap.Client) (accountEmails, error) {
+		emails, searchSnippets, err := fetch(ctx, client)
+		if err != nil {
+			return accountEmails{}, err
+		}
+		threadIDs := make([]string, 0, len(emails))
+		for _, email := range emails {
+			threadIDs = append(threadIDs, email.ThreadID)
+		}
+		threadCounts, err := client.GetThreadMessageCounts(ctx, threadIDs)
+		if err != nil {
+			// Non-fatal: continue without thread counts
+			threadCounts = map[string]int{}
+		}
+		return accountEmails{emails: emails, threadCounts: threadCounts, snippets: searchSnippets}, nil
+	})
+	if err := checkAccountErrors(ctx, app, accounts, errs); err != nil {
+		return err
+	}
+
+	rows := mergeAccountEmails(results, limit)
+
+	if app.IsJSON(ctx) {
+		tagged := make([]map[string]any, len(rows))
+		var taggedSnippets []map[string]any
+		for i, row := range rows {
+			var out any
+			if light {
+				lt := emailToLight(row.email)
+				lt.MsgCount = row.threadCount
+				out = lt
+			} else {
+				full := emailToOutput(row.email)
+				full.MessageCount = row.threadCount
+				out = full
+			}
+			tagged[i] = withAccount(row.account, out)
+			if row.snippet != nil {
+				taggedSnippets = append(taggedSnippets, withAccount(row.account, row.snippet))
+			}
+		}
+		result := multiAccountJSON("emails", tagged, errs)
+		if snippets && len(taggedSnippets) > 0 {
+			result["snippets"] = taggedSnippets
+		}
+		return app.PrintJSON(cmd, result)
+	}
+
+	if len(rows) == 0 {
+		printNoResults("No emails found")
+		return nil
+	}
+
+	tw := outfmt.NewTabWriter()
+	fmt.Fprintln(tw, "ACCOUNT\tID\tSUBJECT\tFROM\tDATE\tUNREAD\tTHREAD")
+	for _, row := range rows {
+		email := row.email
+		unread := ""
+		if email.Keywords != nil && !email.Keywords["$seen"] {
+			unread = "*"
+		}
+		subject := email.Subject
+		if row.snippet != nil && row.snippet.Subject != "" {
+			subject = row.snippet.Subject // Use highlighted subject
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			outfmt.SanitizeTab(row.account),
+			email.ID,
+			outfmt.SanitizeTab(format.Truncate(subject, 50)),
+			outfmt.SanitizeTab(format.Truncate(format.FormatEmailAddressList(email.From), 30)),
+			format.FormatEmailDate(email.ReceivedAt),
+			unread,
+			formatThreadCount(row.threadCount),
+		)
+		if row.snippet != nil && row.snippet.Preview != "" {
+			fmt.Fprintf(tw, "\t\t%s\t\t\t\t\n", outfmt.SanitizeTab(format.Truncate(row.snippet.Preview, 80)))
+		}
+	}
+	tw.Flush()
+
+	return nil
+}
+
+// mergeAccountEmails flattens per-account results into rows sorted newest
+// first and keeps at most limit of them.
+func mergeAccountEmails(results []accountValue[accountEmails], limit int) []accountEmailRow {
+	var rows []accountEmailRow
+	for _, r := range results {
+		snippetMap := make(map[string]jmap.SearchSnippet, len(r.Value.snippets))
+		for _, s := range r.Value.snippets {
+			snippetMap[s.EmailID] = s
+		}
+		for _, email := range r.Value.emails {
+			row := accountEmailRow{
+				account:     r.Account,
+				email:       email,
+				threadCount: r.Value.threadCounts[email.ThreadID],
+			}
+			if s, ok := snippetMap[email.ID]; ok {
+				row.snippet = &s
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].email.ReceivedAt > rows[j].email.ReceivedAt
+	})
+	if limit > 0 && len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
 // formatThreadCount formats a thread message count for display.
 // Returns "-" for single-message threads, "[N msgs]" for multi-message threads.
 func formatThreadCount(count int) string {
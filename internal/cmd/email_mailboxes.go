@@ -117,6 +117,13 @@ func newMailboxDeleteCmd(app *App) *cobra.Command {
 			for _, mb := range mailboxes {
 				if mb.ID == mailboxID {
 					mailboxName = mb.Name
+					policy, err := app.Policy()
+					if err != nil {
+						return err
+					}
+					if err := policy.checkMailboxDelete(mb); err != nil {
+						return err
+					}
 					break
 				}
 			}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/salmonumbrella/fastmail-cli/internal/format"
 	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
@@ -122,6 +123,39 @@ func printEmailDetails(email *jmap.Email) {
 	fmt.Printf("Attachments: %d\n", len(email.Attachments))
 	fmt.Println()
 
+	printEmailBody(email)
+}
+
+// printParsedEmail prints a message parsed with Email/parse. Parsed messages
+// have no ID, thread or received date, so the sent date and threading headers
+// are shown instead, along with each attachment.
+func printParsedEmail(email *jmap.Email) {
+	fmt.Printf("Subject:     %s\n", email.Subject)
+	fmt.Printf("From:        %s\n", format.FormatEmailAddressList(email.From))
+	fmt.Printf("To:          %s\n", format.FormatEmailAddressList(email.To))
+	if len(email.CC) > 0 {
+		fmt.Printf("CC:          %s\n", format.FormatEmailAddressList(email.CC))
+	}
+	fmt.Printf("Date:        %s\n", email.SentAt)
+	if len(email.MessageID) > 0 {
+		fmt.Printf("Message-ID:  %s\n", strings.Join(email.MessageID, " "))
+	}
+	if len(email.InReplyTo) > 0 {
+		fmt.Printf("In-Reply-To: %s\n", strings.Join(email.InReplyTo, " "))
+	}
+	if len(email.References) > 0 {
+		fmt.Printf("References:  %s\n", strings.Join(email.References, " "))
+	}
+	fmt.Printf("Attachments: %d\n", len(email.Attachments))
+	for _, att := range email.Attachments {
+		fmt.Printf("  - %s (%s, %s)\n", att.Name, att.Type, format.FormatBytes(att.Size))
+	}
+	fmt.Println()
+
+	printEmailBody(email)
+}
+
+func printEmailBody(email *jmap.Email) {
 	if len(email.TextBody) > 0 && len(email.BodyValues) > 0 {
 		for _, part := range email.TextBody {
 			if body, ok := email.BodyValues[part.PartID]; ok {
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/spf13/cobra"
+)
+
+type emailParseClient interface {
+	UploadBlob(ctx context.Context, reader io.Reader, contentType string) (*jmap.UploadBlobResult, error)
+	ParseEmail(ctx context.Context, blobID string) (*jmap.Email, error)
+}
+
+func newEmailParseCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "parse <file.eml|->",
+		Short: "Parse a .eml file without importing it",
+		Long: `Parse a raw RFC 5322 message (.eml file, or - for stdin) and show its
+headers, bodies, attachments and threading headers.
+
+The file is uploaded as a blob and parsed by the server with Email/parse; no
+email is created in the account. Use it to inspect suspicious messages or to
+preview what 'email import' would create.`,
+		Example: `  fastmail email parse suspicious.eml
+  cat message.eml | fastmail email parse -
+  fastmail email parse message.eml --output json`,
+		Args: cobra.ExactArgs(1),
+		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
+			reader, err := openEmlInput(args[0])
+			if err != nil {
+				return err
+			}
+			defer reader.Close()
+
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+
+			email, err := parseEml(cmd.Context(), client, reader)
+			if err != nil {
+				return err
+			}
+
+			if app.IsJSON(cmd.Context()) {
+				return app.PrintJSON(cmd, email)
+			}
+
+			printParsedEmail(email)
+			return nil
+		}),
+	}
+
+	return cmd
+}
+
+// openEmlInput opens path for reading, or stdin when path is "-".
+func openEmlInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access file '%s': %w", path, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%w: %s is a directory", ErrUsage, path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file '%s': %w", path, err)
+	}
+	return file, nil
+}
+
+// parseEml uploads a raw message and parses it with Email/parse.
+func parseEml(ctx context.Context, client emailParseClient, r io.Reader) (*jmap.Email, error) {
+	upload, err := client.UploadBlob(ctx, r, "message/rfc822")
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload email: %w", err)
+	}
+
+	email, err := client.ParseEmail(ctx, upload.BlobID)
+	if err != nil {
+		return nil, cerrors.WithContext(err, "parsing email")
+	}
+	return email, nil
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+)
+
+type fakeParseClient struct {
+	uploaded string
+	parsed   string
+}
+
+func (f *fakeParseClient) UploadBlob(_ context.Context, r io.Reader, contentType string) (*jmap.UploadBlobResult, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	f.uploaded = contentType + ":" + string(data)
+	return &jmap.UploadBlobResult{BlobID: "B1"}, nil
+}
+
+func (f *fakeParseClient) ParseEmail(_ context.Context, blobID string) (*jmap.Email, error) {
+	f.parsed = blobID
+	return &jmap.Email{
+		BlobID:      blobID,
+		Subject:     "Invoice",
+		SentAt:      "2025-01-15T10:30:00Z",
+		MessageID:   []string{"abc@example.com"},
+		InReplyTo:   []string{"parent@example.com"},
+		Attachments: []jmap.Attachment{{Name: "invoice.pdf", Type: "application/pdf", Size: 2048}},
+	}, nil
+}
+
+func TestParseEml(t *testing.T) {
+	client := &fakeParseClient{}
+
+	email, err := parseEml(context.Background(), client, strings.NewReader("Subject: Invoice\r\n\r\nhi"))
+	if err != nil {
+		t.Fatalf("parseEml error: %v", err)
+	}
+	if client.uploaded != "message/rfc822:Subject: Invoice\r\n\r\nhi" || client.parsed != "B1" {
+		t.Fatalf("unexpected upload/parse: %q %q", client.uploaded, client.parsed)
+	}
+
+	out := captureStdout(t, func() { printParsedEmail(email) })
+	for _, want := range []string{
+		"Subject:     Invoice",
+		"Date:        2025-01-15T10:30:00Z",
+		"Message-ID:  abc@example.com",
+		"In-Reply-To: parent@example.com",
+		"  - invoice.pdf (application/pdf, 2.0 KB)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "ID:        ") {
+		t.Errorf("parsed email has no ID line, got:\n%s", out)
+	}
+	// Every label is padded to the width of the longest, "In-Reply-To:".
+	for _, label := range []string{"Subject:", "From:", "To:", "Date:", "Message-ID:", "In-Reply-To:", "Attachments:"} {
+		if padded := label + strings.Repeat(" ", len("In-Reply-To: ")-len(label)); !strings.Contains(out, "\n"+padded) && !strings.HasPrefix(out, padded) {
+			t.Errorf("label %q is not padded to %q:\n%s", label, padded, out)
+		}
+	}
+}
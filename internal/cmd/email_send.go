@@ -68,8 +68,25 @@ Examples:
 				}
 			}
 
+			if !draft {
+				policy, err := app.Policy()
+				if err != nil {
+					return err
+				}
+				if err := policy.checkRecipients(allAddrs); err != nil {
+					return err
+				}
+			}
+
 			// Process attachments
 			var attachmentOpts []jmap.AttachmentOpts
+			var uploadLimit int64
+			if len(attachments) > 0 {
+				uploadLimit, err = client.UploadLimit(cmd.Context())
+				if err != nil {
+					return err
+				}
+			}
 			for _, att := range attachments {
 				var attPath, attName string
 				attPath, attName, err = format.ParseAttachmentFlag(att)
@@ -88,8 +105,8 @@ Examples:
 				}
 
 				// Check file size before upload
-				if fileInfo.Size() > jmap.MaxUploadSize {
-					return fmt.Errorf("attachment '%s' too large (%s, max 50 MB)", attPath, format.FormatBytes(fileInfo.Size()))
+				if fileInfo.Size() > uploadLimit {
+					return fmt.Errorf("attachment '%s' too large (%s, max %s)", attPath, format.FormatBytes(fileInfo.Size()), format.FormatBytes(uploadLimit))
 				}
 
 				// Open and upload the file
@@ -0,0 +1,446 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/dateparse"
+	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
+	"github.com/salmonumbrella/fastmail-cli/internal/format"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
+	"github.com/salmonumbrella/fastmail-cli/internal/snooze"
+	"github.com/spf13/cobra"
+)
+
+const (
+	snoozedMailboxName = "Snoozed"
+
+	snoozeModeServer = "server"
+	snoozeModeLocal  = "local"
+)
+
+type snoozeClient interface {
+	mailboxLookupClient
+	SupportsSnooze(ctx context.Context) (bool, error)
+	SnoozeEmails(ctx context.Context, ids []string, until time.Time, snoozedMailboxID string) (*jmap.BulkResult, error)
+	GetSnoozedEmails(ctx context.Context, snoozedMailboxID string, limit int) ([]jmap.Email, error)
+	GetEmailSummaries(ctx context.Context, ids []string) ([]jmap.Email, error)
+	MoveEmails(ctx context.Context, ids []string, targetMailboxID string) (*jmap.BulkResult, error)
+	RestoreEmails(ctx context.Context, mailboxes map[string]map[string]bool) (*jmap.BulkResult, error)
+	CreateMailbox(ctx context.Context, opts jmap.CreateMailboxOpts) (*jmap.Mailbox, error)
+}
+
+// snoozedItem is one row of 'email snoozed' output.
+type snoozedItem struct {
+	EmailID string `json:"emailId"`
+	Subject string `json:"subject"`
+	Until   string `json:"until"`
+	Mode    string `json:"mode"`
+}
+
+func newEmailSnoozeCmd(app *App) *cobra.Command {
+	var until string
+	var local bool
+
+	cmd := &cobra.Command{
+		Use:   "snooze <emailId>...",
+		Short: "Hide emails until a later time",
+		Long: `Snooze emails until a later time.
+
+When the server supports Fastmail's snooze extension, the email's "snoozed"
+property is set and Fastmail brings it back on its own. Otherwise (or with
+--local) the email is moved to a "Snoozed" mailbox and recorded in a local
+schedule; run 'fastmail email snooze wake' (for example from cron) to move
+due emails back to their original mailboxes and mark them unread.
+
+--until accepts dates like "monday 9am", "tomorrow 17:30", "2h", "3d" or
+RFC3339 timestamps. A weekday or time of day that has already passed means
+next week or tomorrow, unless "this" or "today" is given.
+
+Examples:
+  fastmail email snooze M123 --until "monday 9am"
+  fastmail email snooze M123 M456 --until 3d
+  fastmail email snooze wake`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
+			if strings.TrimSpace(until) == "" {
+				return fmt.Errorf("%w: --until is required", ErrUsage)
+			}
+			now := time.Now()
+			untilTime, err := dateparse.ParseFutureDateTime(until, now)
+			if err != nil {
+				return fmt.Errorf("%w: --until: %v", ErrUsage, err)
+			}
+			if !untilTime.After(now) {
+				return fmt.Errorf("%w: --until must be in the future (got %s)", ErrUsage, untilTime.Format(time.RFC3339))
+			}
+
+			account, err := app.RequireAccount()
+			if err != nil {
+				return err
+			}
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+
+			return runEmailSnooze(cmd, app, client, account, snooze.DefaultPath(), args, untilTime, local, now)
+		}),
+	}
+
+	cmd.Flags().StringVar(&until, "until", "", "When the email should come back (e.g. \"monday 9am\", 3d)")
+	cmd.Flags().BoolVar(&local, "local", false, "Use the Snoozed mailbox and local schedule even if the server supports snooze")
+
+	cmd.AddCommand(newEmailSnoozeWakeCmd(app))
+
+	return cmd
+}
+
+func newEmailSnoozeWakeCmd(app *App) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "wake",
+		Short: "Move locally snoozed emails that are due back to their mailboxes",
+		Long: `Move emails whose local snooze has expired back to their original
+mailboxes and mark them unread.
+
+Only emails snoozed without server support (see 'fastmail email snooze')
+need waking. The command is safe to run repeatedly, e.g. every 5 minutes:
+
+  */5 * * * * fastmail email snooze wake`,
+		Args: cobra.NoArgs,
+		RunE: runE(app, func(cmd *cobra.Command, _ []string, app *App) error {
+			account, err := app.RequireAccount()
+			if err != nil {
+				return err
+			}
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+
+			return runEmailSnoozeWake(cmd, app, client, account, snooze.DefaultPath(), time.Now(), app.dryRun())
+		}),
+	}
+
+	return cmd
+}
+
+func newEmailSnoozedCmd(app *App) *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "snoozed",
+		Short: "List snoozed emails",
+		Args:  cobra.NoArgs,
+		RunE: runE(app, func(cmd *cobra.Command, _ []string, app *App) error {
+			account, err := app.RequireAccount()
+			if err != nil {
+				return err
+			}
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+
+			return runEmailSnoozed(cmd, app, client, account, snooze.DefaultPath(), limit)
+		}),
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 100, "Maximum number of server-snoozed emails to list")
+
+	return cmd
+}
+
+func runEmailSnooze(cmd *cobra.Command, app *App, client snoozeClient, account, schedulePath string, ids []string, until time.Time, local bool, now time.Time) error {
+	ctx := cmd.Context()
+
+	mode := snoozeModeLocal
+	if !local {
+		supported, err := client.SupportsSnooze(ctx)
+		if err != nil {
+			return err
+		}
+		if supported {
+			mode = snoozeModeServer
+		}
+	}
+
+	mailbox, err := findSnoozedMailbox(ctx, client, mode == snoozeModeLocal)
+	if err != nil {
+		return err
+	}
+	mailboxID := ""
+	if mailbox != nil {
+		mailboxID = mailbox.ID
+	}
+
+	var results *jmap.BulkResult
+	if mode == snoozeModeServer {
+		results, err = client.SnoozeEmails(ctx, ids, until, mailboxID)
+		if err != nil {
+			return cerrors.WithContext(err, "snoozing emails")
+		}
+	} else {
+		results, err = snoozeLocally(ctx, client, account, schedulePath, ids, until, mailboxID, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	if app.IsJSON(ctx) {
+		output := map[string]any{
+			"mode":      mode,
+			"until":     until.Format(time.RFC3339),
+			"succeeded": results.Succeeded,
+		}
+		if mailbox != nil {
+			output["mailbox"] = mailbox.Name
+		}
+		if len(results.Failed) > 0 {
+			output["failed"] = results.Failed
+		}
+		return app.PrintJSON(cmd, output)
+	}
+
+	target := "emails until " + until.Format("Mon Jan 2 15:04")
+	printBulkResults("Snoozed", target, len(results.Succeeded), len(results.Failed), results.Failed)
+	if mode == snoozeModeLocal && len(results.Succeeded) > 0 {
+		fmt.Println("Run 'fastmail email snooze wake' regularly (e.g. from cron) to bring them back.")
+	}
+	return nil
+}
+
+// snoozeLocally moves emails into the Snoozed mailbox and records where they
+// came from in the local schedule.
+func snoozeLocally(ctx context.Context, client snoozeClient, account, schedulePath string, ids []string, until time.Time, snoozedID string, now time.Time) (*jmap.BulkResult, error) {
+	summaries, err := client.GetEmailSummaries(ctx, ids)
+	if err != nil {
+		return nil, cerrors.WithContext(err, "getting emails")
+	}
+
+	results := &jmap.BulkResult{Succeeded: []string{}, Failed: map[string]string{}}
+	byID := make(map[string]jmap.Email, len(summaries))
+	for _, e := range summaries {
+		byID[e.ID] = e
+	}
+
+	var found []string
+	for _, id := range ids {
+		if _, ok := byID[id]; ok {
+			found = append(found, id)
+		} else {
+			results.Failed[id] = "email not found"
+		}
+	}
+	if len(found) == 0 {
+		return results, nil
+	}
+
+	schedule, err := snooze.Load(schedulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	inboxID := ""
+	if mailboxes, err := client.GetMailboxes(ctx); err == nil {
+		for _, mb := range mailboxes {
+			if mb.Role == "inbox" {
+				inboxID = mb.ID
+				break
+			}
+		}
+	}
+
+	moved, err := client.MoveEmails(ctx, found, snoozedID)
+	if err != nil {
+		return nil, cerrors.WithContext(err, "moving emails to Snoozed")
+	}
+
+	for _, id := range moved.Succeeded {
+		e := byID[id]
+		original := make(map[string]bool, len(e.MailboxIDs))
+		for mbID, in := range e.MailboxIDs {
+			if in && mbID != snoozedID {
+				original[mbID] = true
+			}
+		}
+		if len(original) == 0 && inboxID != "" {
+			original[inboxID] = true
+		}
+
+		schedule.Add(snooze.Entry{
+			Account:    account,
+			EmailID:    id,
+			Subject:    e.Subject,
+			Until:      until,
+			SnoozedAt:  now,
+			MailboxIDs: original,
+		})
+	}
+	if err := schedule.Save(schedulePath); err != nil {
+		return nil, err
+	}
+
+	results.Succeeded = moved.Succeeded
+	for id, reason := range moved.Failed {
+		results.Failed[id] = reason
+	}
+	return results, nil
+}
+
+func runEmailSnoozeWake(cmd *cobra.Command, app *App, client snoozeClient, account, schedulePath string, now time.Time, dryRun bool) error {
+	ctx := cmd.Context()
+
+	schedule, err := snooze.Load(schedulePath)
+	if err != nil {
+		return err
+	}
+
+	due := schedule.Due(account, now)
+	dueIDs := make([]string, 0, len(due))
+	restore := make(map[string]map[string]bool, len(due))
+	for _, e := range due {
+		dueIDs = append(dueIDs, e.EmailID)
+		restore[e.EmailID] = e.MailboxIDs
+	}
+
+	if len(due) == 0 {
+		if app.IsJSON(ctx) {
+			return app.PrintJSON(cmd, map[string]any{"woken": []string{}})
+		}
+		printNoResults("No snoozed emails are due")
+		return nil
+	}
+	if dryRun {
+		return printDryRunList(app, cmd, fmt.Sprintf("Would wake %d emails:", len(due)), "due", dueIDs, nil)
+	}
+
+	results, err := client.RestoreEmails(ctx, restore)
+	if err != nil {
+		return cerrors.WithContext(err, "waking snoozed emails")
+	}
+
+	// Emails that no longer exist can never be woken, so drop them too.
+	done := append([]string(nil), results.Succeeded...)
+	for id, reason := range results.Failed {
+		if strings.Contains(reason, "notFound") {
+			done = append(done, id)
+		}
+	}
+	schedule.Remove(account, done...)
+	if err := schedule.Save(schedulePath); err != nil {
+		return err
+	}
+
+	if app.IsJSON(ctx) {
+		output := map[string]any{"woken": results.Succeeded}
+		if len(results.Failed) > 0 {
+			output["failed"] = results.Failed
+		}
+		return app.PrintJSON(cmd, output)
+	}
+
+	printBulkResults("Woke", "snoozed emails", len(results.Succeeded), len(results.Failed), results.Failed)
+	return nil
+}
+
+func runEmailSnoozed(cmd *cobra.Command, app *App, client snoozeClient, account, schedulePath string, limit int) error {
+	ctx := cmd.Context()
+
+	items := make([]snoozedItem, 0)
+
+	supported, err := client.SupportsSnooze(ctx)
+	if err != nil {
+		return err
+	}
+	if supported {
+		mailbox, err := findSnoozedMailbox(ctx, client, false)
+		if err != nil {
+			return err
+		}
+		if mailbox != nil {
+			emails, err := client.GetSnoozedEmails(ctx, mailbox.ID, limit)
+			if err != nil {
+				return cerrors.WithContext(err, "listing snoozed emails")
+			}
+			for _, e := range emails {
+				if e.Snoozed == nil {
+					continue
+				}
+				items = append(items, snoozedItem{EmailID: e.ID, Subject: e.Subject, Until: e.Snoozed.Until, Mode: snoozeModeServer})
+			}
+		}
+	}
+
+	schedule, err := snooze.Load(schedulePath)
+	if err != nil {
+		return err
+	}
+	for _, e := range schedule.ForAccount(account) {
+		items = append(items, snoozedItem{EmailID: e.EmailID, Subject: e.Subject, Until: e.Until.Format(time.RFC3339), Mode: snoozeModeLocal})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return snoozeUntil(items[i].Until).Before(snoozeUntil(items[j].Until))
+	})
+
+	if app.IsJSON(ctx) {
+		return app.PrintJSON(cmd, map[string]any{"snoozed": items})
+	}
+
+	if len(items) == 0 {
+		printNoResults("No snoozed emails")
+		return nil
+	}
+
+	tw := outfmt.NewTabWriter()
+	fmt.Fprintln(tw, "ID\tUNTIL\tMODE\tSUBJECT")
+	for _, item := range items {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			item.EmailID,
+			snoozeUntil(item.Until).Local().Format("2006-01-02 15:04"),
+			item.Mode,
+			outfmt.SanitizeTab(format.Truncate(item.Subject, 50)),
+		)
+	}
+	tw.Flush()
+	return nil
+}
+
+// findSnoozedMailbox returns the mailbox with the "snoozed" role, falling back
+// to one named "Snoozed". If none exists and create is set, it is created.
+func findSnoozedMailbox(ctx context.Context, client snoozeClient, create bool) (*jmap.Mailbox, error) {
+	mailboxes, err := client.GetMailboxes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mailboxes: %w", err)
+	}
+
+	var byName *jmap.Mailbox
+	for i := range mailboxes {
+		if mailboxes[i].Role == "snoozed" {
+			return &mailboxes[i], nil
+		}
+		if byName == nil && strings.EqualFold(mailboxes[i].Name, snoozedMailboxName) {
+			byName = &mailboxes[i]
+		}
+	}
+	if byName != nil || !create {
+		return byName, nil
+	}
+
+	created, err := client.CreateMailbox(ctx, jmap.CreateMailboxOpts{Name: snoozedMailboxName})
+	if err != nil {
+		return nil, cerrors.WithContext(err, "creating Snoozed mailbox")
+	}
+	return created, nil
+}
+
+func snoozeUntil(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
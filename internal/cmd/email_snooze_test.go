@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/salmonumbrella/fastmail-cli/internal/snooze"
+	"github.com/spf13/cobra"
+)
+
+type fakeSnoozeClient struct {
+	supported bool
+	mailboxes []jmap.Mailbox
+	emails    map[string]jmap.Email
+
+	snoozed  []string
+	moved    []string
+	movedTo  string
+	restored map[string]map[string]bool
+	created  []string
+}
+
+func (f *fakeSnoozeClient) GetMailboxes(_ context.Context) ([]jmap.Mailbox, error) {
+	return f.mailboxes, nil
+}
+
+func (f *fakeSnoozeClient) SupportsSnooze(_ context.Context) (bool, error) {
+	return f.supported, nil
+}
+
+func (f *fakeSnoozeClient) SnoozeEmails(_ context.Context, ids []string, _ time.Time, _ string) (*jmap.BulkResult, error) {
+	f.snoozed = append(f.snoozed, ids...)
+	return &jmap.BulkResult{Succeeded: ids, Failed: map[string]string{}}, nil
+}
+
+func (f *fakeSnoozeClient) GetSnoozedEmails(_ context.Context, _ string, _ int) ([]jmap.Email, error) {
+	return nil, nil
+}
+
+func (f *fakeSnoozeClient) GetEmailSummaries(_ context.Context, ids []string) ([]jmap.Email, error) {
+	var out []jmap.Email
+	for _, id := range ids {
+		if e, ok := f.emails[id]; ok {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSnoozeClient) MoveEmails(_ context.Context, ids []string, target string) (*jmap.BulkResult, error) {
+	f.moved = append(f.moved, ids...)
+	f.movedTo = target
+	return &jmap.BulkResult{Succeeded: ids, Failed: map[string]string{}}, nil
+}
+
+func (f *fakeSnoozeClient) RestoreEmails(_ context.Context, mailboxes map[string]map[string]bool) (*jmap.BulkResult, error) {
+	f.restored = mailboxes
+	ids := make([]string, 0, len(mailboxes))
+	for id := range mailboxes {
+		ids = append(ids, id)
+	}
+	return &jmap.BulkResult{Succeeded: ids, Failed: map[string]string{}}, nil
+}
+
+func (f *fakeSnoozeClient) CreateMailbox(_ context.Context, opts jmap.CreateMailboxOpts) (*jmap.Mailbox, error) {
+	f.created = append(f.created, opts.Name)
+	return &jmap.Mailbox{ID: "new-snoozed", Name: opts.Name}, nil
+}
+
+func TestRunEmailSnooze_ServerMode(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeSnoozeClient{
+		supported: true,
+		mailboxes: []jmap.Mailbox{{ID: "snz", Name: "Snoozed", Role: "snoozed"}},
+	}
+	schedulePath := filepath.Join(t.TempDir(), snooze.FileName)
+	now := time.Now()
+
+	out := captureStdout(t, func() {
+		if err := runEmailSnooze(cmd, app, client, "me@example.com", schedulePath, []string{"e1"}, now.Add(time.Hour), false, now); err != nil {
+			t.Fatalf("runEmailSnooze error: %v", err)
+		}
+	})
+
+	if !reflect.DeepEqual(client.snoozed, []string{"e1"}) || len(client.moved) != 0 {
+		t.Fatalf("expected server snooze only, got snoozed=%v moved=%v", client.snoozed, client.moved)
+	}
+	if !strings.Contains(out, "Snoozed 1 emails until") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestRunEmailSnooze_LocalFallbackAndWake(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeSnoozeClient{
+		mailboxes: []jmap.Mailbox{{ID: "inbox", Name: "Inbox", Role: "inbox"}, {ID: "work", Name: "Work"}},
+		emails: map[string]jmap.Email{
+			"e1": {ID: "e1", Subject: "Report", MailboxIDs: map[string]bool{"work": true}},
+		},
+	}
+	schedulePath := filepath.Join(t.TempDir(), snooze.FileName)
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	until := now.Add(2 * time.Hour)
+
+	captureStdout(t, func() {
+		if err := runEmailSnooze(cmd, app, client, "me@example.com", schedulePath, []string{"e1", "missing"}, until, false, now); err != nil {
+			t.Fatalf("runEmailSnooze error: %v", err)
+		}
+	})
+
+	if !reflect.DeepEqual(client.created, []string{"Snoozed"}) || client.movedTo != "new-snoozed" {
+		t.Fatalf("expected Snoozed mailbox to be created and used, got created=%v movedTo=%q", client.created, client.movedTo)
+	}
+	schedule, err := snooze.Load(schedulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule.Entries) != 1 || !schedule.Entries[0].MailboxIDs["work"] || !schedule.Entries[0].Until.Equal(until) {
+		t.Fatalf("unexpected schedule: %+v", schedule.Entries)
+	}
+
+	// Not due yet: nothing is restored.
+	captureStderr(t, func() {
+		if err := runEmailSnoozeWake(cmd, app, client, "me@example.com", schedulePath, now.Add(time.Hour), false); err != nil {
+			t.Fatalf("runEmailSnoozeWake error: %v", err)
+		}
+	})
+	if client.restored != nil {
+		t.Fatalf("nothing should be woken early, got %v", client.restored)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runEmailSnoozeWake(cmd, app, client, "me@example.com", schedulePath, until, false); err != nil {
+			t.Fatalf("runEmailSnoozeWake error: %v", err)
+		}
+	})
+	if !reflect.DeepEqual(client.restored, map[string]map[string]bool{"e1": {"work": true}}) {
+		t.Fatalf("expected e1 restored to work, got %v", client.restored)
+	}
+	if !strings.Contains(out, "Woke 1 snoozed emails") {
+		t.Errorf("unexpected wake output: %q", out)
+	}
+	schedule, err = snooze.Load(schedulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule.Entries) != 0 {
+		t.Errorf("woken entries should be removed, got %+v", schedule.Entries)
+	}
+}
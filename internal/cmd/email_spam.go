@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/spf13/cobra"
+)
+
+const (
+	blockedSendersStart = "### fastmail-cli blocked senders (managed by 'fastmail email spam --block-sender') ###"
+	blockedSendersEnd   = "### end fastmail-cli blocked senders ###"
+)
+
+var sieveQuotedStringRE = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+
+type spamClient interface {
+	mailboxLookupClient
+	ReportSpam(ctx context.Context, ids []string, opts jmap.ReportSpamOpts) (*jmap.BulkResult, error)
+	GetEmailSummaries(ctx context.Context, ids []string) ([]jmap.Email, error)
+}
+
+type sieveBlocksClient interface {
+	GetSieveBlocks(ctx context.Context) (*jmap.SieveBlocks, error)
+	SetSieveBlocks(ctx context.Context, opts jmap.SetSieveBlocksOpts) error
+}
+
+func newEmailSpamCmd(app *App) *cobra.Command {
+	var blockSender bool
+	var input bulkInputOptions
+
+	cmd := &cobra.Command{
+		Use:     "spam <emailId>...",
+		Aliases: []string{"junk"},
+		Short:   "Report emails as spam and move them to the junk mailbox",
+		Long: `Report emails as spam.
+
+Emails are moved to the junk mailbox and tagged with the $junk keyword, which
+is what Fastmail's spam filter learns from. Moving an email with
+'email move --to Spam' does not train the filter.
+
+With --block-sender, the senders are also added to a managed block rule at the
+start of your Sieve script so future mail from them is discarded. This needs
+Sieve credentials (see 'fastmail sieve auth').`,
+		Example: `  fastmail email spam M123
+  fastmail email spam M123 M456 --block-sender
+  fastmail email spam --ids-file /tmp/fm-ids.txt`,
+		Args: validateBulkInputArgs,
+		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
+			ids, err := collectBulkIDs(args, input)
+			if err != nil {
+				return err
+			}
+
+			if app.dryRun() {
+				return printDryRunList(app, cmd, fmt.Sprintf("Would report %d emails as spam:", len(ids)), "wouldReport", ids, map[string]any{
+					"blockSender": blockSender,
+					"batchSize":   input.BatchSize,
+				})
+			}
+
+			policy, err := app.Policy()
+			if err != nil {
+				return err
+			}
+			if err := policy.checkBulk("report", len(ids)); err != nil {
+				return err
+			}
+
+			// Check Sieve credentials before touching any email.
+			var sieve sieveBlocksClient
+			if blockSender {
+				sieveClient, err := app.SieveClient()
+				if err != nil {
+					return err
+				}
+				sieve = sieveClient
+			}
+
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+
+			return runEmailReportSpam(cmd, app, client, sieve, ids, true, "", input.batchOptions())
+		}),
+	}
+
+	cmd.Flags().BoolVar(&blockSender, "block-sender", false, "Also block future mail from the senders (requires Sieve credentials)")
+	addBulkInputFlags(cmd, &input)
+
+	return cmd
+}
+
+func newEmailNotSpamCmd(app *App) *cobra.Command {
+	var target string
+	var input bulkInputOptions
+
+	cmd := &cobra.Command{
+		Use:     "not-spam <emailId>...",
+		Aliases: []string{"not-junk", "ham"},
+		Short:   "Report emails as not spam and move them out of the junk mailbox",
+		Long: `Report emails as not spam.
+
+Emails are moved to the inbox (or --to) and tagged with the $notjunk keyword,
+which teaches Fastmail's spam filter to let similar mail through.`,
+		Example: `  fastmail email not-spam M123
+  fastmail email not-spam M123 --to Newsletters`,
+		Args: validateBulkInputArgs,
+		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
+			ids, err := collectBulkIDs(args, input)
+			if err != nil {
+				return err
+			}
+
+			if app.dryRun() {
+				return printDryRunList(app, cmd, fmt.Sprintf("Would report %d emails as not spam:", len(ids)), "wouldReport", ids, map[string]any{
+					"to":        target,
+					"batchSize": input.BatchSize,
+				})
+			}
+
+			policy, err := app.Policy()
+			if err != nil {
+				return err
+			}
+			if err := policy.checkBulk("report", len(ids)); err != nil {
+				return err
+			}
+
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+
+			return runEmailReportSpam(cmd, app, client, nil, ids, false, target, input.batchOptions())
+		}),
+	}
+
+	cmd.Flags().StringVar(&target, "to", "", "Mailbox to move the emails to (ID or name, default: Inbox)")
+	addBulkInputFlags(cmd, &input)
+
+	return cmd
+}
+
+// runEmailReportSpam reports ids as spam or not spam. When sieve is non-nil,
+// the senders of the reported emails are added to the managed block rule.
+func runEmailReportSpam(cmd *cobra.Command, app *App, client spamClient, sieve sieveBlocksClient, ids []string, spam bool, target string, batchOpts bulkBatchOptions) error {
+	ctx := cmd.Context()
+
+	opts := jmap.ReportSpamOpts{Spam: spam}
+	targetName := ""
+	if target != "" {
+		targetID, name, err := resolveMailboxTarget(ctx, client, target)
+		if err != nil {
+			return err
+		}
+		opts.TargetMailboxID = targetID
+		targetName = name
+	} else {
+		// Resolve the default mailbox once rather than in every batch.
+		targetID, err := spamTargetMailboxID(ctx, client, spam)
+		if err != nil {
+			return err
+		}
+		opts.TargetMailboxID = targetID
+	}
+
+	// Look up senders before moving so we block exactly what was reported.
+	var senders []string
+	if sieve != nil {
+		emails, err := client.GetEmailSummaries(ctx, ids)
+		if err != nil {
+			return cerrors.WithContext(err, "getting senders")
+		}
+		for _, e := range emails {
+			if len(e.From) > 0 && e.From[0].Email != "" {
+				senders = append(senders, strings.ToLower(e.From[0].Email))
+			}
+		}
+	}
+
+	opLabel := "reporting spam"
+	if !spam {
+		opLabel = "reporting not spam"
+	}
+	results, batches, err := runBulkInBatches(ctx, ids, batchOpts, opLabel, func(batch []string) (*jmap.BulkResult, error) {
+		return client.ReportSpam(ctx, batch, opts)
+	})
+	if err != nil {
+		return cerrors.WithContext(err, opLabel)
+	}
+
+	blocked := []string{}
+	if sieve != nil && len(senders) > 0 {
+		blocks, err := sieve.GetSieveBlocks(ctx)
+		if err != nil {
+			return fmt.Errorf("emails reported, but failed to read sieve blocks: %w", err)
+		}
+		updated, added := addBlockedSenders(blocks.SieveAtStart, senders)
+		if len(added) > 0 {
+			if err := sieve.SetSieveBlocks(ctx, jmap.SetSieveBlocksOpts{SieveAtStart: &updated}); err != nil {
+				return fmt.Errorf("emails reported, but failed to block senders: %w", err)
+			}
+		}
+		if len(added) > 0 {
+			blocked = added
+		}
+	}
+
+	status := "spam"
+	if !spam {
+		status = "not-spam"
+	}
+
+	if app.IsJSON(ctx) {
+		output := map[string]any{
+			"status":    status,
+			"succeeded": results.Succeeded,
+			"batchSize": batchOpts.BatchSize,
+			"batches":   batches,
+		}
+		if targetName != "" {
+			output["to"] = targetName
+		}
+		if sieve != nil {
+			output["blockedSenders"] = blocked
+		}
+		if len(results.Failed) > 0 {
+			output["failed"] = results.Failed
+		}
+		return app.PrintJSON(cmd, output)
+	}
+
+	if batches > 1 {
+		fmt.Printf("Processed %d emails in %d batches (batch size %d)\n", len(ids), batches, batchOpts.BatchSize)
+	}
+	printBulkResults("Reported", "emails as "+strings.ReplaceAll(status, "-", " "), len(results.Succeeded), len(results.Failed), results.Failed)
+	if sieve != nil {
+		if len(blocked) > 0 {
+			fmt.Printf("Blocked senders: %s\n", strings.Join(blocked, ", "))
+		} else {
+			fmt.Println("Senders were already blocked")
+		}
+	}
+	return nil
+}
+
+// spamTargetMailboxID returns the junk mailbox when reporting spam, or the
+// inbox when reporting not spam.
+func spamTargetMailboxID(ctx context.Context, client mailboxLookupClient, spam bool) (string, error) {
+	mailboxes, err := client.GetMailboxes(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get mailboxes: %w", err)
+	}
+
+	role := "inbox"
+	if spam {
+		role = "junk"
+	}
+	for _, mb := range mailboxes {
+		if mb.Role == role {
+			return mb.ID, nil
+		}
+	}
+	if spam {
+		return "", jmap.ErrNoJunkMailbox
+	}
+	return "", jmap.ErrNoInboxMailbox
+}
+
+// addBlockedSenders adds senders to the managed block rule in script, creating
+// the rule at the top of the script if needed. It returns the updated script
+// and the senders that were not already blocked.
+func addBlockedSenders(script string, senders []string) (string, []string) {
+	existing := map[string]bool{}
+	rest := script
+
+	if start := strings.Index(script, blockedSendersStart); start >= 0 {
+		if end := strings.Index(script[start:], blockedSendersEnd); end >= 0 {
+			end += start + len(blockedSendersEnd)
+			section := script[start:end]
+			if lb, rb := strings.Index(section, "["), strings.LastIndex(section, "]"); lb >= 0 && rb > lb {
+				for _, m := range sieveQuotedStringRE.FindAllStringSubmatch(section[lb:rb], -1) {
+					existing[sieveUnquote(m[1])] = true
+				}
+			}
+			rest = script[:start] + strings.TrimLeft(script[end:], "\r\n")
+		}
+	}
+
+	var added []string
+	for _, s := range senders {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "" || existing[s] {
+			continue
+		}
+		existing[s] = true
+		added = append(added, s)
+	}
+	if len(added) == 0 {
+		return script, nil
+	}
+
+	all := make([]string, 0, len(existing))
+	for s := range existing {
+		all = append(all, s)
+	}
+	sort.Strings(all)
+
+	quoted := make([]string, len(all))
+	for i, s := range all {
+		quoted[i] = sieveQuote(s)
+	}
+
+	var b strings.Builder
+	b.WriteString(blockedSendersStart + "\n")
+	b.WriteString("if address :is \"from\" [" + strings.Join(quoted, ", ") + "] {\n")
+	b.WriteString("  discard;\n")
+	b.WriteString("  stop;\n")
+	b.WriteString("}\n")
+	b.WriteString(blockedSendersEnd + "\n")
+	if rest != "" {
+		b.WriteString(rest)
+	}
+
+	return b.String(), added
+}
+
+func sieveQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func sieveUnquote(s string) string {
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(s)
+}
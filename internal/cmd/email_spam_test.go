@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/spf13/cobra"
+)
+
+type fakeSpamClient struct {
+	mailboxes    []jmap.Mailbox
+	mailboxCalls int
+	emails       []jmap.Email
+	reported     []string
+	opts         jmap.ReportSpamOpts
+
+	// overlap, if set, is closed once two ReportSpam calls are in flight;
+	// each call waits for it, so only concurrent batches finish quickly.
+	overlap     chan struct{}
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (f *fakeSpamClient) GetMailboxes(_ context.Context) ([]jmap.Mailbox, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mailboxCalls++
+	return f.mailboxes, nil
+}
+
+func (f *fakeSpamClient) ReportSpam(_ context.Context, ids []string, opts jmap.ReportSpamOpts) (*jmap.BulkResult, error) {
+	f.mu.Lock()
+	f.inFlight++
+	f.maxInFlight = max(f.maxInFlight, f.inFlight)
+	if f.inFlight == 2 && f.overlap != nil {
+		close(f.overlap)
+	}
+	f.mu.Unlock()
+	if f.overlap != nil {
+		select {
+		case <-f.overlap:
+		case <-time.After(2 * time.Second):
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inFlight--
+	f.reported = append(f.reported, ids...)
+	f.opts = opts
+	return &jmap.BulkResult{Succeeded: ids, Failed: map[string]string{}}, nil
+}
+
+func (f *fakeSpamClient) GetEmailSummaries(_ context.Context, _ []string) ([]jmap.Email, error) {
+	return f.emails, nil
+}
+
+type fakeSieveBlocks struct {
+	blocks jmap.SieveBlocks
+	set    *string
+}
+
+func (f *fakeSieveBlocks) GetSieveBlocks(_ context.Context) (*jmap.SieveBlocks, error) {
+	return &f.blocks, nil
+}
+
+func (f *fakeSieveBlocks) SetSieveBlocks(_ context.Context, opts jmap.SetSieveBlocksOpts) error {
+	f.set = opts.SieveAtStart
+	return nil
+}
+
+func TestAddBlockedSenders(t *testing.T) {
+	script, added := addBlockedSenders("# my rules\n", []string{"Spam@Example.com", "spam@example.com"})
+	if !reflect.DeepEqual(added, []string{"spam@example.com"}) {
+		t.Fatalf("added = %v", added)
+	}
+	if !strings.HasPrefix(script, blockedSendersStart) || !strings.HasSuffix(script, "# my rules\n") {
+		t.Fatalf("managed block should be prepended, got:\n%s", script)
+	}
+
+	script, added = addBlockedSenders(script, []string{"other@example.com", "spam@example.com"})
+	if !reflect.DeepEqual(added, []string{"other@example.com"}) {
+		t.Fatalf("added = %v", added)
+	}
+	if strings.Count(script, blockedSendersStart) != 1 {
+		t.Fatalf("managed block should be replaced, not duplicated:\n%s", script)
+	}
+	if !strings.Contains(script, `["other@example.com", "spam@example.com"]`) {
+		t.Errorf("expected merged sender list, got:\n%s", script)
+	}
+
+	unchanged, added := addBlockedSenders(script, []string{"spam@example.com"})
+	if len(added) != 0 || unchanged != script {
+		t.Errorf("already-blocked sender should not change the script")
+	}
+}
+
+func TestRunEmailReportSpam_BlockSender(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeSpamClient{
+		mailboxes: []jmap.Mailbox{{ID: "junk", Name: "Spam", Role: "junk"}},
+		emails:    []jmap.Email{{ID: "e1", From: []jmap.EmailAddress{{Email: "Bad@Example.com"}}}},
+	}
+	sieve := &fakeSieveBlocks{}
+
+	out := captureStdout(t, func() {
+		if err := runEmailReportSpam(cmd, app, client, sieve, []string{"e1"}, true, "", bulkBatchOptions{BatchSize: 50, Concurrency: 1}); err != nil {
+			t.Fatalf("runEmailReportSpam error: %v", err)
+		}
+	})
+
+	if !client.opts.Spam || client.opts.TargetMailboxID != "junk" || !reflect.DeepEqual(client.reported, []string{"e1"}) {
+		t.Fatalf("unexpected report: %v %+v", client.reported, client.opts)
+	}
+	if sieve.set == nil || !strings.Contains(*sieve.set, `"bad@example.com"`) {
+		t.Fatalf("expected sender to be blocked, got %v", sieve.set)
+	}
+	if !strings.Contains(out, "Reported 1 emails as spam") || !strings.Contains(out, "Blocked senders: bad@example.com") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestRunEmailReportSpam_NotSpamWithTarget(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeSpamClient{mailboxes: []jmap.Mailbox{{ID: "news", Name: "Newsletters"}}}
+
+	captureStdout(t, func() {
+		if err := runEmailReportSpam(cmd, app, client, nil, []string{"e1"}, false, "Newsletters", bulkBatchOptions{BatchSize: 50, Concurrency: 1}); err != nil {
+			t.Fatalf("runEmailReportSpam error: %v", err)
+		}
+	})
+
+	if client.opts.Spam || client.opts.TargetMailboxID != "news" {
+		t.Fatalf("unexpected options: %+v", client.opts)
+	}
+}
+
+func TestRunEmailReportSpam_HonorsConcurrency(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeSpamClient{
+		mailboxes: []jmap.Mailbox{{ID: "junk", Name: "Spam", Role: "junk"}},
+		overlap:   make(chan struct{}),
+	}
+	captureStdout(t, func() {
+		err := runEmailReportSpam(cmd, app, client, nil, []string{"e1", "e2", "e3", "e4"}, true, "", bulkBatchOptions{BatchSize: 2, Concurrency: 2})
+		if err != nil {
+			t.Fatalf("runEmailReportSpam error: %v", err)
+		}
+	})
+
+	if client.maxInFlight != 2 {
+		t.Errorf("max batches in flight = %d, want 2 with --concurrency 2", client.maxInFlight)
+	}
+	if len(client.reported) != 4 {
+		t.Errorf("reported = %v, want all 4 emails", client.reported)
+	}
+	if client.mailboxCalls != 1 || client.opts.TargetMailboxID != "junk" {
+		t.Errorf("junk mailbox should be resolved once, got %d lookups and target %q", client.mailboxCalls, client.opts.TargetMailboxID)
+	}
+}
+
+func TestRunEmailReportSpam_NoJunkMailbox(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeSpamClient{mailboxes: []jmap.Mailbox{{ID: "inbox", Name: "Inbox", Role: "inbox"}}}
+	err := runEmailReportSpam(cmd, app, client, nil, []string{"e1"}, true, "", bulkBatchOptions{BatchSize: 50, Concurrency: 1})
+	if !errors.Is(err, jmap.ErrNoJunkMailbox) {
+		t.Fatalf("err = %v, want ErrNoJunkMailbox", err)
+	}
+	if len(client.reported) != 0 {
+		t.Errorf("nothing should be reported, got %v", client.reported)
+	}
+}
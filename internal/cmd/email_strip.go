@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/format"
+)
+
+// strippedPart describes an attachment removed from a raw message.
+type strippedPart struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Size    int64  `json:"size"`
+	SavedTo string `json:"savedTo"`
+}
+
+// saveStrippedPart stores the decoded content of a removed attachment and
+// returns where it was written.
+type saveStrippedPart func(name, contentType string, data []byte) (string, error)
+
+// stripAttachmentParts removes attachments larger than minSize from a raw
+// RFC 5322 message. Each removed part is handed to save and replaced with a
+// short text/plain note pointing at the saved copy. The top-level headers are
+// kept byte-for-byte; only multipart bodies are rewritten.
+func stripAttachmentParts(raw []byte, minSize int64, save saveStrippedPart) ([]byte, []strippedPart, error) {
+	header, body := splitRawMessage(raw)
+
+	tp := textproto.NewReader(bufio.NewReader(bytes.NewReader(header)))
+	mimeHeader, err := tp.ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return nil, nil, fmt.Errorf("parsing message headers: %w", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(mimeHeader.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		// Single-part messages have no separate attachment parts to remove.
+		return raw, nil, nil
+	}
+
+	newBody, removed, err := stripMultipart(body, params["boundary"], minSize, save)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(removed) == 0 {
+		return raw, nil, nil
+	}
+
+	out := make([]byte, 0, len(header)+len(newBody))
+	out = append(out, header...)
+	out = append(out, newBody...)
+	return out, removed, nil
+}
+
+func stripMultipart(body []byte, boundary string, minSize int64, save saveStrippedPart) ([]byte, []strippedPart, error) {
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if err := writer.SetBoundary(boundary); err != nil {
+		return nil, nil, fmt.Errorf("invalid boundary %q: %w", boundary, err)
+	}
+
+	var removed []strippedPart
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading MIME part: %w", err)
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading MIME part: %w", err)
+		}
+
+		partHeader := part.Header
+		mediaType, params, _ := mime.ParseMediaType(partHeader.Get("Content-Type"))
+
+		switch {
+		case strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "":
+			nested, nestedRemoved, err := stripMultipart(content, params["boundary"], minSize, save)
+			if err != nil {
+				return nil, nil, err
+			}
+			content = nested
+			removed = append(removed, nestedRemoved...)
+
+		case isAttachmentPart(partHeader):
+			data, err := decodePartBody(partHeader.Get("Content-Transfer-Encoding"), content)
+			if err != nil || int64(len(data)) <= minSize {
+				break
+			}
+
+			name := partFilename(partHeader)
+			if name == "" {
+				name = "attachment"
+			}
+			savedTo, err := save(name, mediaType, data)
+			if err != nil {
+				return nil, nil, fmt.Errorf("saving %s: %w", name, err)
+			}
+			removed = append(removed, strippedPart{Name: name, Type: mediaType, Size: int64(len(data)), SavedTo: savedTo})
+
+			partHeader = textproto.MIMEHeader{
+				"Content-Type":        {"text/plain; charset=utf-8"},
+				"Content-Disposition": {mime.FormatMediaType("inline", map[string]string{"filename": name + ".removed.txt"})},
+			}
+			content = []byte(fmt.Sprintf("The attachment %q (%s, %s) was removed from this message.\r\nA copy was saved to: %s\r\n",
+				name, mediaType, format.FormatBytes(int64(len(data))), savedTo))
+		}
+
+		w, err := writer.CreatePart(partHeader)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, err := w.Write(content); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), removed, nil
+}
+
+func isAttachmentPart(h textproto.MIMEHeader) bool {
+	disposition, _, _ := mime.ParseMediaType(h.Get("Content-Disposition"))
+	if strings.EqualFold(disposition, "attachment") {
+		return true
+	}
+	return partFilename(h) != ""
+}
+
+func partFilename(h textproto.MIMEHeader) string {
+	if _, params, err := mime.ParseMediaType(h.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		return params["filename"]
+	}
+	if _, params, err := mime.ParseMediaType(h.Get("Content-Type")); err == nil && params["name"] != "" {
+		return params["name"]
+	}
+	return ""
+}
+
+func decodePartBody(encoding string, content []byte) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		cleaned := strings.Map(func(r rune) rune {
+			if r == '\r' || r == '\n' || r == ' ' || r == '\t' {
+				return -1
+			}
+			return r
+		}, string(content))
+		return base64.StdEncoding.DecodeString(cleaned)
+	case "quoted-printable":
+		return io.ReadAll(quotedprintable.NewReader(bytes.NewReader(content)))
+	default:
+		return content, nil
+	}
+}
+
+// splitRawMessage splits a raw message into its header block (including the
+// blank separator line) and its body.
+func splitRawMessage(raw []byte) ([]byte, []byte) {
+	end := -1
+	for _, sep := range [][]byte{[]byte("\r\n\r\n"), []byte("\n\n")} {
+		if i := bytes.Index(raw, sep); i >= 0 && (end < 0 || i+len(sep) < end) {
+			end = i + len(sep)
+		}
+	}
+	if end < 0 {
+		return raw, nil
+	}
+	return raw[:end], raw[end:]
+}
@@ -11,10 +11,14 @@ import (
 
 	"github.com/salmonumbrella/fastmail-cli/internal/dateparse"
 	"github.com/salmonumbrella/fastmail-cli/internal/tracking"
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
 	"github.com/spf13/cobra"
 )
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+// trackingHTTPClient returns the client for the tracking worker's API.
+func trackingHTTPClient() *http.Client {
+	return transport.NewHTTPClient(30 * time.Second)
+}
 
 func newEmailTrackOpensCmd(app *App) *cobra.Command {
 	var to, since string
@@ -57,7 +61,7 @@ func queryByTrackingID(cmd *cobra.Command, app *App, cfg *tracking.Config, track
 		return fmt.Errorf("build request: %w", err)
 	}
 
-	resp, err := httpClient.Do(req)
+	resp, err := trackingHTTPClient().Do(req)
 	if err != nil {
 		return fmt.Errorf("query tracker: %w", err)
 	}
@@ -142,7 +146,7 @@ func queryAdmin(cmd *cobra.Command, app *App, cfg *tracking.Config, to, since st
 	req, _ := http.NewRequestWithContext(cmd.Context(), "GET", reqURL.String(), nil)
 	req.Header.Set("Authorization", "Bearer "+cfg.AdminKey)
 
-	resp, err := httpClient.Do(req)
+	resp, err := trackingHTTPClient().Do(req)
 	if err != nil {
 		return fmt.Errorf("query tracker: %w", err)
 	}
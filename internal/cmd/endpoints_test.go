@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/caldav"
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+)
+
+func TestJMAPClientFor_ConfiguredEndpoint(t *testing.T) {
+	useFileKeyring(t)
+
+	var sessionAuth string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/jmap":
+			http.Redirect(w, r, "/jmap/session-resource", http.StatusTemporaryRedirect)
+		case "/jmap/session-resource":
+			sessionAuth = r.Header.Get("Authorization")
+			_, _ = w.Write([]byte(`{"apiUrl": "` + server.URL + `/jmap/api", "accounts": {"a1": {"name": "me@example.com"}}, "primaryAccounts": {"urn:ietf:params:jmap:mail": "a1"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	settings := `{"accounts": {"me@example.com": {"endpoints": {"jmap": "` + server.URL + `", "webdav": "https://dav.example.com"}}}}`
+	if err := os.WriteFile(os.Getenv(config.ConfigFileEnvVarName), []byte(settings), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveToken("me@example.com", "tok"); err != nil {
+		t.Fatalf("SaveToken: %v", err)
+	}
+
+	app := &App{Flags: &rootFlags{NoCache: true}}
+	client, err := app.JMAPClientFor("me@example.com")
+	if err != nil {
+		t.Fatalf("JMAPClientFor: %v", err)
+	}
+	session, err := client.GetSession(context.Background())
+	if err != nil {
+		t.Fatalf("GetSession: %v", err)
+	}
+	if session.AccountID != "a1" || session.APIUrl != server.URL+"/jmap/api" {
+		t.Errorf("session = %+v", session)
+	}
+	if sessionAuth != "Bearer tok" {
+		t.Errorf("session request Authorization = %q", sessionAuth)
+	}
+
+	endpoints, err := app.Endpoints("me@example.com")
+	if err != nil {
+		t.Fatalf("Endpoints: %v", err)
+	}
+	if endpoints.WebDAV != "https://dav.example.com" || endpoints.CalDAV != caldav.DefaultBaseURL {
+		t.Errorf("endpoints = %+v, want configured WebDAV and default CalDAV", endpoints)
+	}
+	if other, _ := app.Endpoints("other@example.com"); other.JMAP != "" {
+		t.Errorf("other account endpoints = %+v, want Fastmail defaults", other)
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"errors"
 
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
 	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
 	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
 	"github.com/salmonumbrella/fastmail-cli/internal/transport"
@@ -18,10 +19,16 @@ func mapCommandError(err error) error {
 	}
 
 	switch {
-	case transport.IsUnauthorized(err):
+	case transport.IsUnauthorized(err), jmap.IsAuthError(err):
 		return cerrors.WithSuggestion(err, cerrors.SuggestionReauth)
 	case jmap.IsInvalidFromAddressError(err):
 		return cerrors.WithSuggestion(err, cerrors.SuggestionListIdentity)
+	case errors.Is(err, jmap.ErrTokenScope):
+		return cerrors.WithSuggestion(err, "Add a token with that scope: fastmail auth add <email> --scope <scope>, or a full-access token without --scope")
+	case transport.IsReadOnly(err):
+		return cerrors.WithSuggestion(err, "Re-run without --read-only (and unset FASTMAIL_READ_ONLY) to allow changes")
+	case errors.Is(err, ErrPolicyViolation):
+		return cerrors.WithSuggestion(err, "Change the \"policy\" section of "+config.SettingsPath()+" if this operation is intended")
 	case errors.Is(err, jmap.ErrNoIdentities):
 		return cerrors.WithSuggestion(err, cerrors.SuggestionListIdentity)
 	}
@@ -20,6 +20,8 @@ const (
 	ExitNotFound    = 4
 	ExitRateLimited = 5
 	ExitTemporary   = 6
+	ExitReadOnly    = 7
+	ExitPolicy      = 8
 	ExitCanceled    = 130
 )
 
@@ -37,6 +39,12 @@ func ExitCode(err error) int {
 	if isUsageError(err) {
 		return ExitUsage
 	}
+	if transport.IsReadOnly(err) {
+		return ExitReadOnly
+	}
+	if errors.Is(err, ErrPolicyViolation) {
+		return ExitPolicy
+	}
 	if isAuthFailure(err) {
 		return ExitAuth
 	}
@@ -78,7 +86,7 @@ func isUsageError(err error) bool {
 }
 
 func isAuthFailure(err error) bool {
-	if jmap.IsAuthError(err) || transport.IsUnauthorized(err) {
+	if jmap.IsAuthError(err) || transport.IsUnauthorized(err) || errors.Is(err, jmap.ErrTokenScope) {
 		return true
 	}
 
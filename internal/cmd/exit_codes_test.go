@@ -52,6 +52,11 @@ func TestExitCode(t *testing.T) {
 			err:  &transport.HTTPError{StatusCode: 503, Status: "503 Service Unavailable"},
 			want: ExitTemporary,
 		},
+		{
+			name: "read-only",
+			err:  fmt.Errorf("archiving: %w", &transport.ReadOnlyError{Operation: "call Email/set"}),
+			want: ExitReadOnly,
+		},
 		{
 			name: "canceled",
 			err:  context.Canceled,
@@ -289,6 +289,14 @@ WARNING: This operation cannot be undone. Use with caution.`,
 
 			path := args[0]
 
+			policy, err := app.Policy()
+			if err != nil {
+				return err
+			}
+			if err := policy.checkFilesDelete(path); err != nil {
+				return err
+			}
+
 			confirmed, err := app.Confirm(cmd, false, fmt.Sprintf("Are you sure you want to delete %s? [y/N] ", path), "y", "yes")
 			if err != nil {
 				return err
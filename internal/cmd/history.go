@@ -0,0 +1,385 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/audit"
+	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
+	"github.com/salmonumbrella/fastmail-cli/internal/format"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
+)
+
+// undoEntryKey marks the context of an undo so its own audit entry records
+// which entry it reverses.
+const undoEntryKey contextKey = "undoEntry"
+
+// revertClient is what undo needs from the JMAP client.
+type revertClient interface {
+	RevertCalls(ctx context.Context, calls []jmap.AuditedCall) (*jmap.BulkResult, error)
+}
+
+// auditor returns the jmap.Auditor that collects account's writes for this
+// command's audit entry, or nil under --dry-run, when nothing is written.
+// Writes are held until flushAudit so that a command sending several
+// requests, such as a bulk move in batches, is one entry and one undo.
+func (a *App) auditor(account string) jmap.Auditor {
+	if a.Flags != nil && a.Flags.DryRun {
+		return nil
+	}
+	return func(ctx context.Context, calls []jmap.AuditedCall) {
+		a.auditMu.Lock()
+		defer a.auditMu.Unlock()
+		entry := a.pendingAuditEntry(account)
+		entry.Calls = append(entry.Calls, calls...)
+		if undoes, ok := ctx.Value(undoEntryKey).(int); ok {
+			entry.Undoes = undoes
+		}
+	}
+}
+
+// pendingAuditEntry returns the entry collecting account's writes for this
+// command, starting it on the first write. The caller holds auditMu.
+func (a *App) pendingAuditEntry(account string) *audit.Entry {
+	if a.auditEntries == nil {
+		a.auditEntries = make(map[string]*audit.Entry)
+	}
+	entry, ok := a.auditEntries[account]
+	if !ok {
+		entry = &audit.Entry{Time: time.Now().UTC(), Command: append([]string{"fastmail"}, os.Args[1:]...)}
+		a.auditEntries[account] = entry
+		a.auditAccounts = append(a.auditAccounts, account)
+	}
+	return entry
+}
+
+// auditedHTTPClient returns the HTTP client for account's WebDAV, CalDAV and
+// Sieve requests: the dry-run client under --dry-run, or else one that adds
+// each successful write to the command's audit entry, like auditor does for
+// JMAP.
+func (a *App) auditedHTTPClient(account string) *http.Client {
+	if httpClient := a.dryRunHTTPClient(); httpClient != nil {
+		return httpClient
+	}
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &auditTransport{next: transport.NewRoundTripper(), app: a, account: account},
+	}
+}
+
+// auditTransport records the write requests sent through it that succeed.
+type auditTransport struct {
+	next    http.RoundTripper
+	app     *App
+	account string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Method == http.MethodPost {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode >= http.StatusMultipleChoices || !isWriteRequest(req, body) {
+		return resp, err
+	}
+
+	t.app.auditMu.Lock()
+	entry := t.app.pendingAuditEntry(t.account)
+	entry.Requests = append(entry.Requests, audit.Request{Method: req.Method, URL: transport.RedactURL(req.URL)})
+	t.app.auditMu.Unlock()
+	return resp, nil
+}
+
+// flushAudit appends the writes collected by auditor to each account's
+// audit log, one entry per account. Execute calls it once the command has
+// finished, whether or not it succeeded.
+func (a *App) flushAudit() {
+	a.auditMu.Lock()
+	defer a.auditMu.Unlock()
+	for _, account := range a.auditAccounts {
+		if err := audit.ForAccount(account).Append(*a.auditEntries[account]); err != nil {
+			outfmt.Errorf("Warning: %v", err)
+		}
+	}
+	a.auditEntries = nil
+	a.auditAccounts = nil
+}
+
+func newHistoryCmd(app *App) *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history [<entry>]",
+		Short: "Browse the audit log of changes made by this CLI",
+		Long: `List the changes fastmail-cli has made to the account, newest first, or
+show one entry in full.
+
+Every command that changes data appends an entry to a per-account log in the
+state directory (override with FASTMAIL_STATE_DIR), with the command line, the
+methods called, the IDs affected and, for emails and masked emails, the
+previous mailboxes, keywords and state. File, calendar and Sieve changes are
+logged by method and URL and cannot be undone. Use 'fastmail undo' to reverse
+an entry.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
+			account, err := app.RequireAccount()
+			if err != nil {
+				return err
+			}
+			log := audit.ForAccount(account)
+			if len(args) == 1 {
+				return runHistoryShow(cmd, app, log, args[0])
+			}
+			return runHistory(cmd, app, log, limit)
+		}),
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of entries to list (0 for all)")
+
+	return cmd
+}
+
+func runHistory(cmd *cobra.Command, app *App, log *audit.Log, limit int) error {
+	entries, err := log.Entries()
+	if err != nil {
+		return err
+	}
+
+	// Newest first.
+	recent := make([]audit.Entry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0 && (limit <= 0 || len(recent) < limit); i-- {
+		recent = append(recent, entries[i])
+	}
+
+	if app.IsJSON(cmd.Context()) {
+		return app.PrintJSON(cmd, map[string]any{"entries": recent, "path": log.Path()})
+	}
+
+	if len(recent) == 0 {
+		printNoResults("No changes recorded")
+		return nil
+	}
+
+	tw := outfmt.NewTabWriter()
+	fmt.Fprintln(tw, "ENTRY\tTIME\tCHANGES\tSTATUS\tCOMMAND")
+	for _, e := range recent {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n",
+			e.ID,
+			e.Time.Local().Format("2006-01-02 15:04"),
+			summarizeEntry(e),
+			historyStatus(e),
+			outfmt.SanitizeTab(format.Truncate(strings.Join(e.Command, " "), 60)),
+		)
+	}
+	tw.Flush()
+	return nil
+}
+
+func runHistoryShow(cmd *cobra.Command, app *App, log *audit.Log, arg string) error {
+	entries, err := log.Entries()
+	if err != nil {
+		return err
+	}
+	entry, err := findAuditEntry(entries, arg)
+	if err != nil {
+		return err
+	}
+
+	if app.IsJSON(cmd.Context()) {
+		return app.PrintJSON(cmd, entry)
+	}
+
+	fmt.Printf("Entry:   %d\n", entry.ID)
+	fmt.Printf("Time:    %s\n", entry.Time.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("Command: %s\n", strings.Join(entry.Command, " "))
+	if status := historyStatus(entry); status != "" {
+		fmt.Printf("Status:  %s\n", status)
+	}
+	for _, call := range entry.Calls {
+		fmt.Printf("\n%s\n", call.Method)
+		printAuditIDs("created", call.Created)
+		printAuditIDs("updated", call.Updated)
+		printAuditIDs("destroyed", call.Destroyed)
+	}
+	for _, r := range entry.Requests {
+		fmt.Printf("\n%s %s\n", r.Method, r.URL)
+	}
+	return nil
+}
+
+func printAuditIDs(label string, ids []string) {
+	if len(ids) > 0 {
+		fmt.Printf("  %s: %s\n", label, strings.Join(ids, ", "))
+	}
+}
+
+// summarizeEntry describes an entry's changes, e.g. "Email/set: 3 updated"
+// or "PUT: 2 sent".
+func summarizeEntry(e audit.Entry) string {
+	parts := make([]string, 0, len(e.Calls)+len(e.Requests))
+	for _, call := range e.Calls {
+		var counts []string
+		for _, c := range []struct {
+			label string
+			n     int
+		}{{"created", len(call.Created)}, {"updated", len(call.Updated)}, {"destroyed", len(call.Destroyed)}} {
+			if c.n > 0 {
+				counts = append(counts, fmt.Sprintf("%d %s", c.n, c.label))
+			}
+		}
+		parts = append(parts, call.Method+": "+strings.Join(counts, ", "))
+	}
+
+	var methods []string
+	requests := make(map[string]int)
+	for _, r := range e.Requests {
+		if requests[r.Method] == 0 {
+			methods = append(methods, r.Method)
+		}
+		requests[r.Method]++
+	}
+	for _, method := range methods {
+		parts = append(parts, fmt.Sprintf("%s: %d sent", method, requests[method]))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func historyStatus(e audit.Entry) string {
+	var status []string
+	if e.Undoes != 0 {
+		status = append(status, fmt.Sprintf("undo of %d", e.Undoes))
+	}
+	if e.UndoneBy != 0 {
+		status = append(status, fmt.Sprintf("undone by %d", e.UndoneBy))
+	}
+	return strings.Join(status, ", ")
+}
+
+func findAuditEntry(entries []audit.Entry, arg string) (audit.Entry, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id <= 0 {
+		return audit.Entry{}, fmt.Errorf("%w: invalid entry %q: must be a positive number from 'fastmail history'", ErrUsage, arg)
+	}
+	entry, ok := audit.Find(entries, id)
+	if !ok {
+		return audit.Entry{}, fmt.Errorf("audit entry %d not found", id)
+	}
+	return entry, nil
+}
+
+func newUndoCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "undo [<entry>]",
+		Short: "Reverse a change recorded in the audit log",
+		Long: `Put emails and masked emails back the way they were before an entry in
+'fastmail history': moves, archives, deletions to Trash, read/unread,
+flags and other keywords, and masked email enable/disable/description
+changes. Without an entry, the most recent change that has not been undone
+is reversed.
+
+Creations, permanent deletions and sent mail cannot be undone. An undo is
+itself recorded, so undoing it redoes the original change.`,
+		Example: `  fastmail history
+  fastmail undo
+  fastmail undo 42`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
+			account, err := app.RequireAccount()
+			if err != nil {
+				return err
+			}
+			log := audit.ForAccount(account)
+			entries, err := log.Entries()
+			if err != nil {
+				return err
+			}
+
+			var entry audit.Entry
+			if len(args) == 1 {
+				if entry, err = findAuditEntry(entries, args[0]); err != nil {
+					return err
+				}
+			} else {
+				var ok bool
+				if entry, ok = audit.Latest(entries); !ok {
+					return fmt.Errorf("nothing to undo: no changes recorded in %s", log.Path())
+				}
+			}
+
+			client, err := app.JMAPClientFor(account)
+			if err != nil {
+				return err
+			}
+			return runUndo(cmd, app, client, entry)
+		}),
+	}
+}
+
+func runUndo(cmd *cobra.Command, app *App, client revertClient, entry audit.Entry) error {
+	if entry.UndoneBy != 0 {
+		return Suggest(
+			fmt.Errorf("entry %d was already undone by entry %d", entry.ID, entry.UndoneBy),
+			fmt.Sprintf("Run 'fastmail undo %d' to redo it", entry.UndoneBy),
+		)
+	}
+	if !entry.Undoable() {
+		return Suggest(
+			fmt.Errorf("entry %d (%s) cannot be undone", entry.ID, summarizeEntry(entry)),
+			"Only moves, keyword changes and masked email state changes can be undone",
+		)
+	}
+
+	var count int
+	for _, call := range entry.Calls {
+		count += len(call.Revert())
+	}
+	prompt := fmt.Sprintf("Undo entry %d (%s), restoring %d items? [y/N] ", entry.ID, strings.Join(entry.Command, " "), count)
+	confirmed, err := app.Confirm(cmd, false, prompt, "y", "yes")
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		printCancelled()
+		return nil
+	}
+
+	ctx := context.WithValue(cmd.Context(), undoEntryKey, entry.ID)
+	results, err := client.RevertCalls(ctx, entry.Calls)
+	if err != nil {
+		return cerrors.WithContext(err, fmt.Sprintf("undoing entry %d", entry.ID))
+	}
+
+	if app.IsJSON(cmd.Context()) {
+		output := map[string]any{
+			"entry":     entry.ID,
+			"succeeded": results.Succeeded,
+		}
+		if len(results.Failed) > 0 {
+			output["failed"] = results.Failed
+		}
+		return app.PrintJSON(cmd, output)
+	}
+
+	printBulkResults("Restored", fmt.Sprintf("items from entry %d", entry.ID), len(results.Succeeded), len(results.Failed), results.Failed)
+	return nil
+}
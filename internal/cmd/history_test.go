@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/audit"
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
+	"github.com/salmonumbrella/fastmail-cli/internal/webdav"
+	"github.com/spf13/cobra"
+)
+
+type fakeRevertClient struct {
+	calls   []jmap.AuditedCall
+	undoing any
+}
+
+func (f *fakeRevertClient) RevertCalls(ctx context.Context, calls []jmap.AuditedCall) (*jmap.BulkResult, error) {
+	f.calls = calls
+	f.undoing = ctx.Value(undoEntryKey)
+	return &jmap.BulkResult{Succeeded: []string{"e1"}, Failed: map[string]string{}}, nil
+}
+
+var archiveCall = jmap.AuditedCall{
+	Method:  "Email/set",
+	Updated: []string{"e1"},
+	Update:  map[string]map[string]any{"e1": {"mailboxIds": map[string]any{"archive": true}}},
+	Prior:   map[string]map[string]any{"e1": {"mailboxIds": map[string]any{"inbox": true}}},
+}
+
+func TestRunUndo(t *testing.T) {
+	app := &App{Flags: &rootFlags{Yes: true}}
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeRevertClient{}
+	entry := audit.Entry{ID: 7, Command: []string{"fastmail", "email", "archive", "e1"}, Calls: []jmap.AuditedCall{archiveCall}}
+	out := captureStdout(t, func() {
+		if err := runUndo(cmd, app, client, entry); err != nil {
+			t.Fatalf("runUndo: %v", err)
+		}
+	})
+	if len(client.calls) != 1 || client.undoing != 7 {
+		t.Errorf("reverted %+v with undo entry %v", client.calls, client.undoing)
+	}
+	if !strings.Contains(out, "Restored 1 items from entry 7") {
+		t.Errorf("output = %q", out)
+	}
+
+	client = &fakeRevertClient{}
+	entry.UndoneBy = 8
+	if err := runUndo(cmd, app, client, entry); err == nil || !strings.Contains(err.Error(), "already undone by entry 8") {
+		t.Errorf("undoing an undone entry: err = %v", err)
+	}
+
+	send := audit.Entry{ID: 9, Calls: []jmap.AuditedCall{{Method: "EmailSubmission/set", Created: []string{"s1"}}}}
+	if err := runUndo(cmd, app, client, send); err == nil || !strings.Contains(err.Error(), "cannot be undone") {
+		t.Errorf("undoing a send: err = %v", err)
+	}
+	if client.calls != nil {
+		t.Error("RevertCalls should not be called for entries that cannot be undone")
+	}
+}
+
+func TestRunHistory_JSONNewestFirst(t *testing.T) {
+	log := audit.New(filepath.Join(t.TempDir(), "a.jsonl"))
+	for _, command := range []string{"archive", "move"} {
+		if err := log.Append(audit.Entry{Time: time.Now(), Command: []string{"fastmail", "email", command}, Calls: []jmap.AuditedCall{archiveCall}}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.WithValue(context.Background(), outputModeKey, outfmt.JSON))
+
+	out := captureStdout(t, func() {
+		if err := runHistory(cmd, app, log, 1); err != nil {
+			t.Fatalf("runHistory: %v", err)
+		}
+	})
+	var got struct {
+		Entries []audit.Entry `json:"entries"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output %q: %v", out, err)
+	}
+	if len(got.Entries) != 1 || got.Entries[0].ID != 2 || got.Entries[0].Command[2] != "move" {
+		t.Errorf("entries = %+v, want only the newest", got.Entries)
+	}
+}
+
+func TestFindAuditEntry(t *testing.T) {
+	entries := []audit.Entry{{ID: 1}}
+	if _, err := findAuditEntry(entries, "x"); !errors.Is(err, ErrUsage) {
+		t.Errorf("non-numeric entry: err = %v, want usage error", err)
+	}
+	if _, err := findAuditEntry(entries, "2"); ExitCode(err) != ExitNotFound {
+		t.Errorf("missing entry: err = %v, want not-found exit code", err)
+	}
+	if e, err := findAuditEntry(entries, "1"); err != nil || e.ID != 1 {
+		t.Errorf("findAuditEntry(1) = %+v, %v", e, err)
+	}
+}
+
+func TestAuditor_DisabledInDryRun(t *testing.T) {
+	app := &App{Flags: &rootFlags{DryRun: true}}
+	if app.auditor("me@example.com") != nil {
+		t.Error("auditor should be nil under --dry-run")
+	}
+}
+
+func TestAuditor_BulkMoveInBatchesIsOneUndoableEntry(t *testing.T) {
+	t.Setenv(config.StateDirEnvVarName, t.TempDir())
+
+	var apiURL string
+	var mu sync.Mutex
+	location := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"apiUrl": "` + apiURL + `", "accounts": {"acc1": {"name": "me"}}, "primaryAccounts": {"urn:ietf:params:jmap:mail": "acc1"}}`))
+			return
+		}
+		var req jmap.Request
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		defer mu.Unlock()
+		var responses []any
+		for _, call := range req.MethodCalls {
+			args, _ := call[1].(map[string]any)
+			var result map[string]any
+			switch call[0] {
+			case "Mailbox/get":
+				result = map[string]any{"list": []any{
+					map[string]any{"id": "inbox", "name": "Inbox", "role": "inbox"},
+					map[string]any{"id": "archive", "name": "Archive", "role": "archive"},
+				}}
+			case "Email/get":
+				var list []any
+				for _, id := range args["ids"].([]any) {
+					list = append(list, map[string]any{"id": id, "mailboxIds": map[string]any{location[id.(string)]: true}})
+				}
+				result = map[string]any{"list": list}
+			case "Email/set":
+				updated := map[string]any{}
+				for id, patch := range args["update"].(map[string]any) {
+					for mailbox := range patch.(map[string]any)["mailboxIds"].(map[string]any) {
+						location[id] = mailbox
+					}
+					updated[id] = nil
+				}
+				result = map[string]any{"updated": updated}
+			}
+			responses = append(responses, []any{call[0], result, call[2]})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"methodResponses": responses, "sessionState": ""})
+	}))
+	defer server.Close()
+	apiURL = server.URL + "/api"
+
+	ids := []string{"e1", "e2", "e3", "e4", "e5"}
+	for _, id := range ids {
+		location[id] = "inbox"
+	}
+
+	app := newTestApp()
+	app.Flags.Yes = true
+	client := jmap.NewClientWithBaseURL("token", server.URL)
+	client.SetAuditor(app.auditor("me@example.com"))
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	captureStdout(t, func() {
+		if err := runEmailBulkMoveWithClient(cmd, app, client, ids, "Archive", bulkBatchOptions{BatchSize: 2, Concurrency: 1}); err != nil {
+			t.Fatalf("bulk move: %v", err)
+		}
+	})
+	app.flushAudit()
+
+	entries, err := audit.ForAccount("me@example.com").Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	if len(entries) != 1 || len(entries[0].Calls) != 3 {
+		t.Fatalf("entries = %+v, want one entry with three batches", entries)
+	}
+
+	captureStdout(t, func() {
+		if err := runUndo(cmd, app, client, entries[0]); err != nil {
+			t.Fatalf("runUndo: %v", err)
+		}
+	})
+	for _, id := range ids {
+		if location[id] != "inbox" {
+			t.Errorf("%s is in %s after undo, want inbox", id, location[id])
+		}
+	}
+}
+
+func TestAuditedHTTPClient_RecordsWebDAVWrites(t *testing.T) {
+	t.Setenv(config.StateDirEnvVarName, t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "MKCOL":
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	app := newTestApp()
+	client := webdav.NewClientWithBaseURL("token", server.URL)
+	client.SetHTTPClient(app.auditedHTTPClient("me@example.com"))
+
+	ctx := context.Background()
+	if err := client.Mkdir(ctx, "/reports"); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+	if err := client.Delete(ctx, "/missing.txt"); err == nil {
+		t.Fatal("Delete of a missing file should fail")
+	}
+	app.flushAudit()
+
+	entries, err := audit.ForAccount("me@example.com").Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	entry := entries[0]
+	want := []audit.Request{{Method: "MKCOL", URL: server.URL + "/reports/"}}
+	if len(entry.Requests) != 1 || entry.Requests[0] != want[0] {
+		t.Errorf("requests = %+v, want only the successful MKCOL %+v", entry.Requests, want)
+	}
+	if entry.Undoable() {
+		t.Error("a WebDAV write should not be undoable")
+	}
+	if got := summarizeEntry(entry); got != "MKCOL: 1 sent" {
+		t.Errorf("summarizeEntry = %q", got)
+	}
+	if err := runUndo(&cobra.Command{}, app, &fakeRevertClient{}, entry); err == nil || !strings.Contains(err.Error(), "cannot be undone") {
+		t.Errorf("runUndo error = %v, want cannot be undone", err)
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/spf13/cobra"
+)
+
+type rawCallClient interface {
+	CallRaw(ctx context.Context, req *jmap.Request) (*jmap.Response, error)
+}
+
+func newJMAPCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jmap",
+		Short: "Low-level JMAP access",
+	}
+
+	cmd.AddCommand(newJMAPCallCmd(app))
+
+	return cmd
+}
+
+func newJMAPCallCmd(app *App) *cobra.Command {
+	var file string
+	var using []string
+
+	cmd := &cobra.Command{
+		Use:   "call [method] [argsJSON]",
+		Short: "Send a raw JMAP request and print the response",
+		Long: `Send a raw JMAP request using the current login, retries and circuit breaker,
+and print the server's response as JSON.
+
+Pass a single method and its arguments on the command line, or a full request
+with --file (use - for stdin). A request may be a JMAP request object
+({"using": [...], "methodCalls": [...]}) or just the methodCalls array, and
+may use back-references ("#ids": {"resultOf": "c0", ...}).
+
+Missing values are filled in: core and each method's capability are added to
+"using", "accountId" is set to the session's account for the method's
+capability, and empty call IDs become c0, c1, ...
+
+The command exits non-zero if any method call returns an error response.`,
+		Example: `  fastmail jmap call Mailbox/get '{"ids":null}'
+  fastmail jmap call Email/query '{"limit":5}' --query '.methodResponses[0][1].ids'
+  fastmail jmap call --file request.json
+  cat request.json | fastmail jmap call --file -`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
+			req, err := buildRawRequest(args, file, os.Stdin)
+			if err != nil {
+				return err
+			}
+			req.Using = append(req.Using, using...)
+
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
+			}
+			return runJMAPCall(cmd, app, client, req)
+		}),
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Read the request from a file (- for stdin)")
+	cmd.Flags().StringSliceVar(&using, "using", nil, "Extra capabilities to add to \"using\"")
+
+	return cmd
+}
+
+func runJMAPCall(cmd *cobra.Command, app *App, client rawCallClient, req *jmap.Request) error {
+	resp, err := client.CallRaw(cmd.Context(), req)
+	if err != nil {
+		return cerrors.WithContext(err, "sending JMAP request")
+	}
+
+	if err := app.PrintJSON(cmd, resp); err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, mr := range resp.MethodResponses {
+		if name, _ := mr[0].(string); name == "error" {
+			callID, _ := mr[2].(string)
+			failed = append(failed, callID)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d method call(s) returned an error: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// buildRawRequest builds a request from either a method and JSON arguments or
+// a request read from file ("-" for stdin).
+func buildRawRequest(args []string, file string, stdin io.Reader) (*jmap.Request, error) {
+	if file != "" {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("%w: pass either a method or --file, not both", ErrUsage)
+		}
+		var data []byte
+		var err error
+		if file == "-" {
+			data, err = io.ReadAll(stdin)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading request: %w", err)
+		}
+		return parseRawRequest(data)
+	}
+
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: a method (e.g. Mailbox/get) or --file is required", ErrUsage)
+	}
+
+	method := args[0]
+	if !strings.Contains(method, "/") {
+		return nil, fmt.Errorf("%w: invalid method %q: expected Type/method, e.g. Mailbox/get", ErrUsage, method)
+	}
+
+	callArgs := map[string]any{}
+	if len(args) == 2 {
+		if err := decodeJSON([]byte(args[1]), &callArgs); err != nil {
+			return nil, fmt.Errorf("%w: invalid arguments JSON: %v", ErrUsage, err)
+		}
+	}
+
+	return &jmap.Request{
+		MethodCalls: []jmap.MethodCall{{method, callArgs, "c0"}},
+	}, nil
+}
+
+// parseRawRequest accepts a full JMAP request object or a bare methodCalls
+// array.
+func parseRawRequest(data []byte) (*jmap.Request, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: request is empty", ErrUsage)
+	}
+
+	req := &jmap.Request{}
+	var err error
+	if data[0] == '[' {
+		err = decodeJSON(data, &req.MethodCalls)
+	} else {
+		err = decodeJSON(data, req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid request JSON: %v", ErrUsage, err)
+	}
+	if len(req.MethodCalls) == 0 {
+		return nil, fmt.Errorf("%w: request has no method calls", ErrUsage)
+	}
+	return req, nil
+}
+
+// decodeJSON decodes data keeping numbers exact, so large integers survive
+// the round trip to the server.
+func decodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
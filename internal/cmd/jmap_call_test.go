@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/spf13/cobra"
+)
+
+type fakeRawCallClient struct {
+	req  *jmap.Request
+	resp *jmap.Response
+}
+
+func (f *fakeRawCallClient) CallRaw(_ context.Context, req *jmap.Request) (*jmap.Response, error) {
+	f.req = req
+	return f.resp, nil
+}
+
+func TestBuildRawRequest_Shorthand(t *testing.T) {
+	req, err := buildRawRequest([]string{"Email/query", `{"limit":5,"filter":{"inMailbox":"m1"}}`}, "", nil)
+	if err != nil {
+		t.Fatalf("buildRawRequest error: %v", err)
+	}
+	if len(req.MethodCalls) != 1 || req.MethodCalls[0][0] != "Email/query" || req.MethodCalls[0][2] != "c0" {
+		t.Fatalf("unexpected calls: %v", req.MethodCalls)
+	}
+	args := req.MethodCalls[0][1].(map[string]any)
+	if args["limit"] != json.Number("5") {
+		t.Errorf("limit = %#v, want json.Number(5)", args["limit"])
+	}
+}
+
+func TestBuildRawRequest_Errors(t *testing.T) {
+	tests := map[string][]string{
+		"no method":      nil,
+		"invalid method": {"Mailbox"},
+		"invalid args":   {"Mailbox/get", "{"},
+	}
+	for name, args := range tests {
+		if _, err := buildRawRequest(args, "", nil); !errors.Is(err, ErrUsage) {
+			t.Errorf("%s: expected usage error, got %v", name, err)
+		}
+	}
+
+	if _, err := buildRawRequest([]string{"Mailbox/get"}, "-", strings.NewReader("[]")); !errors.Is(err, ErrUsage) {
+		t.Errorf("method with --file: expected usage error, got %v", err)
+	}
+}
+
+func TestBuildRawRequest_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.json")
+	body := `{"using":["urn:ietf:params:jmap:mail"],"methodCalls":[
+		["Email/query",{"limit":1},"q"],
+		["Email/get",{"#ids":{"resultOf":"q","name":"Email/query","path":"/ids"}},"g"]]}`
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := buildRawRequest(nil, path, nil)
+	if err != nil {
+		t.Fatalf("buildRawRequest error: %v", err)
+	}
+	if len(req.Using) != 1 || len(req.MethodCalls) != 2 || req.MethodCalls[1][2] != "g" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+}
+
+func TestBuildRawRequest_StdinArray(t *testing.T) {
+	req, err := buildRawRequest(nil, "-", strings.NewReader(`[["Mailbox/get",{"ids":null},"0"]]`))
+	if err != nil {
+		t.Fatalf("buildRawRequest error: %v", err)
+	}
+	if len(req.MethodCalls) != 1 || req.MethodCalls[0][0] != "Mailbox/get" {
+		t.Errorf("unexpected calls: %v", req.MethodCalls)
+	}
+
+	if _, err := buildRawRequest(nil, "-", strings.NewReader("[]")); !errors.Is(err, ErrUsage) {
+		t.Errorf("empty methodCalls: expected usage error, got %v", err)
+	}
+}
+
+func TestRunJMAPCall(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeRawCallClient{resp: &jmap.Response{
+		MethodResponses: []jmap.MethodResponse{{"Mailbox/get", map[string]any{"list": []any{}}, "c0"}},
+		SessionState:    "s1",
+	}}
+
+	out := captureStdout(t, func() {
+		if err := runJMAPCall(cmd, app, client, &jmap.Request{}); err != nil {
+			t.Fatalf("runJMAPCall error: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"methodResponses"`) || !strings.Contains(out, `"Mailbox/get"`) {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestRunJMAPCall_MethodError(t *testing.T) {
+	app := newTestApp()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeRawCallClient{resp: &jmap.Response{
+		MethodResponses: []jmap.MethodResponse{{"error", map[string]any{"type": "unknownMethod"}, "c0"}},
+	}}
+
+	var err error
+	out := captureStdout(t, func() {
+		err = runJMAPCall(cmd, app, client, &jmap.Request{})
+	})
+	if err == nil || !strings.Contains(err.Error(), "c0") {
+		t.Errorf("expected error naming the failed call, got %v", err)
+	}
+	if !strings.Contains(out, "unknownMethod") {
+		t.Errorf("response should still be printed: %q", out)
+	}
+}
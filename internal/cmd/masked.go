@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sort"
@@ -43,34 +44,29 @@ func newMaskedListCmd(app *App) *cobra.Command {
 Without a domain argument, lists all masked emails.
 With a domain, lists only aliases for that domain.`,
 		Example: `  fastmail masked list
-  fastmail masked list example.com`,
+  fastmail masked list example.com
+  fastmail masked list --account all`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
-			client, err := app.JMAPClient()
+			var domain string
+			if len(args) > 0 {
+				domain = args[0]
+			}
+
+			accounts, multi, err := app.MultiAccounts()
 			if err != nil {
 				return err
 			}
+			if multi {
+				return runMaskedListAccounts(cmd, app, accounts, domain, all)
+			}
 
-			var aliases []jmap.MaskedEmail
-			var domain string
-
-			if len(args) > 0 {
-				domain = args[0]
-				aliases, err = client.GetMaskedEmailsForDomain(cmd.Context(), domain)
-			} else {
-				aliases, err = client.GetMaskedEmails(cmd.Context())
-				// Filter out deleted unless --all
-				if !all {
-					var filtered []jmap.MaskedEmail
-					for _, a := range aliases {
-						if a.State != jmap.MaskedEmailDeleted {
-							filtered = append(filtered, a)
-						}
-					}
-					aliases = filtered
-				}
+			client, err := app.JMAPClient()
+			if err != nil {
+				return err
 			}
 
+			aliases, err := listMaskedEmails(cmd.Context(), client, domain, all)
 			if err != nil {
 				return fmt.Errorf("failed to list masked emails: %w", err)
 			}
@@ -129,6 +125,99 @@ With a domain, lists only aliases for that domain.`,
 	return cmd
 }
 
+// listMaskedEmails returns the masked emails for domain, or all of them
+// (without deleted ones unless includeDeleted) when domain is empty.
+func listMaskedEmails(ctx context.Context, client *jmap.Client, domain string, includeDeleted bool) ([]jmap.MaskedEmail, error) {
+	if domain != "" {
+		return client.GetMaskedEmailsForDomain(ctx, domain)
+	}
+
+	aliases, err := client.GetMaskedEmails(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if includeDeleted {
+		return aliases, nil
+	}
+
+	var filtered []jmap.MaskedEmail
+	for _, a := range aliases {
+		if a.State != jmap.MaskedEmailDeleted {
+			filtered = append(filtered, a)
+		}
+	}
+	return filtered, nil
+}
+
+// runMaskedListAccounts lists masked emails across accounts, tagged by account.
+func runMaskedListAccounts(cmd *cobra.Command, app *App, accounts []string, domain string, includeDeleted bool) error {
+	ctx := cmd.Context()
+
+	results, errs := fanOut(ctx, accounts, app.JMAPClientFor, func(ctx context.Context, client *jmap.Client) ([]jmap.MaskedEmail, error) {
+		return listMaskedEmails(ctx, client, domain, includeDeleted)
+	})
+	if err := checkAccountErrors(ctx, app, accounts, errs); err != nil {
+		return err
+	}
+
+	type row struct {
+		account string
+		alias   jmap.MaskedEmail
+	}
+	var rows []row
+	for _, r := range results {
+		for _, alias := range r.Value {
+			rows = append(rows, row{account: r.Account, alias: alias})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i].alias, rows[j].alias
+		if a.ForDomain != b.ForDomain {
+			return a.ForDomain < b.ForDomain
+		}
+		return a.Email < b.Email
+	})
+
+	if app.IsJSON(ctx) {
+		tagged := make([]map[string]any, len(rows))
+		for i, r := range rows {
+			tagged[i] = withAccount(r.account, r.alias)
+		}
+		return app.PrintJSON(cmd, multiAccountJSON("maskedEmails", tagged, errs))
+	}
+
+	if len(rows) == 0 {
+		printNoResults("No masked emails found")
+		return nil
+	}
+
+	tw := outfmt.NewTabWriter()
+	fmt.Fprintln(tw, "ACCOUNT\tEMAIL\tDOMAIN\tSTATE\tDESCRIPTION")
+	for _, r := range rows {
+		desc := r.alias.Description
+		if desc == "" {
+			desc = "-"
+		}
+		if len(desc) > 40 {
+			desc = desc[:37] + "..."
+		}
+		forDomain := r.alias.ForDomain
+		if forDomain == "" {
+			forDomain = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			outfmt.SanitizeTab(r.account),
+			r.alias.Email,
+			outfmt.SanitizeTab(forDomain),
+			r.alias.State,
+			outfmt.SanitizeTab(desc),
+		)
+	}
+	tw.Flush()
+
+	return nil
+}
+
 func newMaskedCreateCmd(app *App) *cobra.Command {
 	var description string
 
@@ -254,7 +343,6 @@ func newMaskedGetCmd(app *App) *cobra.Command {
 
 func newMaskedEnableCmd(app *App) *cobra.Command {
 	var domain string
-	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "enable [email]",
@@ -277,21 +365,19 @@ Use this command to manually enable an alias.`,
 			}
 
 			if domain != "" {
-				return bulkUpdateMaskedEmailState(cmd, app, domain, jmap.MaskedEmailEnabled, dryRun)
+				return bulkUpdateMaskedEmailState(cmd, app, domain, jmap.MaskedEmailEnabled, app.dryRun())
 			}
-			return updateMaskedEmailState(cmd, app, args[0], jmap.MaskedEmailEnabled, dryRun)
+			return updateMaskedEmailState(cmd, app, args[0], jmap.MaskedEmailEnabled, app.dryRun())
 		}),
 	}
 
 	cmd.Flags().StringVar(&domain, "domain", "", "Enable all aliases for this domain")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be changed without making changes")
 
 	return cmd
 }
 
 func newMaskedDisableCmd(app *App) *cobra.Command {
 	var domain string
-	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "disable [email]",
@@ -312,21 +398,19 @@ When disabled, emails sent to the alias are moved to trash.`,
 			}
 
 			if domain != "" {
-				return bulkUpdateMaskedEmailState(cmd, app, domain, jmap.MaskedEmailDisabled, dryRun)
+				return bulkUpdateMaskedEmailState(cmd, app, domain, jmap.MaskedEmailDisabled, app.dryRun())
 			}
-			return updateMaskedEmailState(cmd, app, args[0], jmap.MaskedEmailDisabled, dryRun)
+			return updateMaskedEmailState(cmd, app, args[0], jmap.MaskedEmailDisabled, app.dryRun())
 		}),
 	}
 
 	cmd.Flags().StringVar(&domain, "domain", "", "Disable all aliases for this domain")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be changed without making changes")
 
 	return cmd
 }
 
 func newMaskedDeleteCmd(app *App) *cobra.Command {
 	var domain string
-	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "delete [email]",
@@ -346,15 +430,22 @@ When deleted, emails sent to the alias will bounce.`,
 				return fmt.Errorf("cannot use both email argument and --domain flag")
 			}
 
+			policy, err := app.Policy()
+			if err != nil {
+				return err
+			}
+			if err := policy.checkMaskedDelete(); err != nil {
+				return err
+			}
+
 			if domain != "" {
-				return bulkUpdateMaskedEmailState(cmd, app, domain, jmap.MaskedEmailDeleted, dryRun)
+				return bulkUpdateMaskedEmailState(cmd, app, domain, jmap.MaskedEmailDeleted, app.dryRun())
 			}
-			return updateMaskedEmailState(cmd, app, args[0], jmap.MaskedEmailDeleted, dryRun)
+			return updateMaskedEmailState(cmd, app, args[0], jmap.MaskedEmailDeleted, app.dryRun())
 		}),
 	}
 
 	cmd.Flags().StringVar(&domain, "domain", "", "Delete all aliases for this domain")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be changed without making changes")
 
 	return cmd
 }
@@ -497,6 +588,14 @@ func bulkUpdateMaskedEmailState(cmd *cobra.Command, app *App, domain string, sta
 		return printMaskedDryRunBulk(app, cmd, domain, state, toUpdate)
 	}
 
+	policy, err := app.Policy()
+	if err != nil {
+		return err
+	}
+	if err := policy.checkBulk(stateActionVerb(state), len(toUpdate)); err != nil {
+		return err
+	}
+
 	// Perform the updates
 	var succeeded, failed int
 	var errors []string
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+)
+
+// ErrPolicyViolation marks operations refused by the safety policy in the
+// config file.
+var ErrPolicyViolation = errors.New("policy violation")
+
+// commandPolicy checks mutating commands against an account's safety policy.
+// The zero policy permits everything.
+type commandPolicy struct {
+	config.Policy
+	account string
+}
+
+// policyMailClient is what checkBulkDelete needs from the JMAP client.
+type policyMailClient interface {
+	GetMailboxes(ctx context.Context) ([]jmap.Mailbox, error)
+	GetEmailSummaries(ctx context.Context, ids []string) ([]jmap.Email, error)
+}
+
+// Policy loads the safety policy for the selected account.
+func (a *App) Policy() (*commandPolicy, error) {
+	account, err := a.RequireAccount()
+	if err != nil {
+		return nil, err
+	}
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &commandPolicy{account: account}
+	if policy := settings.PolicyFor(account); policy != nil {
+		p.Policy = *policy
+	}
+	return p, nil
+}
+
+func (p *commandPolicy) violation(format string, args ...any) error {
+	return fmt.Errorf("%w: %s (safety policy for %s)", ErrPolicyViolation, fmt.Sprintf(format, args...), p.account)
+}
+
+// checkBulk enforces max_bulk_items for an operation changing n items; verb
+// describes it for the error ("delete", "move", ...).
+func (p *commandPolicy) checkBulk(verb string, n int) error {
+	if p.MaxBulkItems > 0 && n > p.MaxBulkItems {
+		return p.violation("cannot %s %d items: max_bulk_items is %d", verb, n, p.MaxBulkItems)
+	}
+	return nil
+}
+
+// isProtected reports whether mb matches protected_mailboxes by ID, name or
+// role.
+func (p *commandPolicy) isProtected(mb jmap.Mailbox) bool {
+	for _, protected := range p.ProtectedMailboxes {
+		if protected == mb.ID || strings.EqualFold(protected, mb.Name) || (mb.Role != "" && strings.EqualFold(protected, mb.Role)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMailboxDelete refuses to delete a protected mailbox.
+func (p *commandPolicy) checkMailboxDelete(mb jmap.Mailbox) error {
+	if p.isProtected(mb) {
+		return p.violation("mailbox %q is protected", mb.Name)
+	}
+	return nil
+}
+
+// checkBulkDelete refuses to bulk-delete emails in a protected mailbox.
+func (p *commandPolicy) checkBulkDelete(ctx context.Context, client policyMailClient, ids []string) error {
+	if len(p.ProtectedMailboxes) == 0 {
+		return nil
+	}
+
+	mailboxes, err := client.GetMailboxes(ctx)
+	if err != nil {
+		return fmt.Errorf("checking safety policy: %w", err)
+	}
+	protected := make(map[string]string)
+	for _, mb := range mailboxes {
+		if p.isProtected(mb) {
+			protected[mb.ID] = mb.Name
+		}
+	}
+	if len(protected) == 0 {
+		return nil
+	}
+
+	emails, err := client.GetEmailSummaries(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("checking safety policy: %w", err)
+	}
+	var count int
+	var name string
+	for _, email := range emails {
+		for mailboxID := range email.MailboxIDs {
+			if n, ok := protected[mailboxID]; ok {
+				count++
+				name = n
+				break
+			}
+		}
+	}
+	if count > 0 {
+		return p.violation("%d of the emails are in protected mailbox %q", count, name)
+	}
+	return nil
+}
+
+// checkRecipients enforces allowed_recipient_domains.
+func (p *commandPolicy) checkRecipients(addrs []string) error {
+	if len(p.AllowedRecipientDomains) == 0 {
+		return nil
+	}
+	for _, addr := range addrs {
+		at := strings.LastIndex(addr, "@")
+		domain := strings.ToLower(strings.TrimSpace(addr[at+1:]))
+		if !p.allowedDomain(domain) {
+			return p.violation("recipient %s is not in allowed_recipient_domains", addr)
+		}
+	}
+	return nil
+}
+
+// emailRecipients returns the To, Cc and Bcc addresses of e, for
+// checkRecipients on an email that is sent as stored, such as a draft.
+func emailRecipients(e *jmap.Email) []string {
+	var addrs []string
+	for _, group := range [][]jmap.EmailAddress{e.To, e.CC, e.BCC} {
+		for _, addr := range group {
+			addrs = append(addrs, addr.Email)
+		}
+	}
+	return addrs
+}
+
+func (p *commandPolicy) allowedDomain(domain string) bool {
+	for _, allowed := range p.AllowedRecipientDomains {
+		allowed = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(allowed), "@"))
+		if domain == allowed || strings.HasSuffix(domain, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMaskedDelete enforces allow_masked_delete.
+func (p *commandPolicy) checkMaskedDelete() error {
+	if p.AllowMaskedDelete != nil && !*p.AllowMaskedDelete {
+		return p.violation("deleting masked emails is not allowed")
+	}
+	return nil
+}
+
+// checkFilesDelete refuses to delete a protected path, anything under it, or
+// a directory containing it.
+func (p *commandPolicy) checkFilesDelete(remotePath string) error {
+	target := path.Clean("/" + remotePath)
+	for _, protected := range p.ProtectedPaths {
+		protected = path.Clean("/" + protected)
+		if target == protected || isUnderPath(target, protected) || isUnderPath(protected, target) {
+			return p.violation("path %s is protected by %s", target, protected)
+		}
+	}
+	return nil
+}
+
+// isUnderPath reports whether the clean absolute path p is inside dir.
+func isUnderPath(p, dir string) bool {
+	return strings.HasPrefix(p, strings.TrimSuffix(dir, "/")+"/")
+}
+
+// checkSieveChanges enforces allow_sieve_changes.
+func (p *commandPolicy) checkSieveChanges() error {
+	if p.AllowSieveChanges != nil && !*p.AllowSieveChanges {
+		return p.violation("changing Sieve scripts is not allowed")
+	}
+	return nil
+}
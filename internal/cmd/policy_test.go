@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+	"github.com/salmonumbrella/fastmail-cli/internal/jmap"
+	"github.com/spf13/cobra"
+)
+
+type fakePolicyMailClient struct {
+	mailboxes []jmap.Mailbox
+	emails    []jmap.Email
+}
+
+func (f *fakePolicyMailClient) GetMailboxes(context.Context) ([]jmap.Mailbox, error) {
+	return f.mailboxes, nil
+}
+
+func (f *fakePolicyMailClient) GetEmailSummaries(_ context.Context, ids []string) ([]jmap.Email, error) {
+	var out []jmap.Email
+	for _, e := range f.emails {
+		for _, id := range ids {
+			if e.ID == id {
+				out = append(out, e)
+			}
+		}
+	}
+	return out, nil
+}
+
+func TestAppPolicy_LoadsAccountPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv(config.ConfigFileEnvVarName, path)
+	data := `{
+  "policy": {"max_bulk_items": 100},
+  "accounts": {"Work@Example.com": {"policy": {"max_bulk_items": 5}}}
+}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	app := &App{Flags: &rootFlags{Account: "work@example.com"}}
+	policy, err := app.Policy()
+	if err != nil {
+		t.Fatalf("Policy: %v", err)
+	}
+	if policy.MaxBulkItems != 5 {
+		t.Errorf("MaxBulkItems = %d, want the account's 5", policy.MaxBulkItems)
+	}
+
+	app.Flags.Account = "other@example.com"
+	if policy, _ = app.Policy(); policy.MaxBulkItems != 100 {
+		t.Errorf("MaxBulkItems = %d, want the default 100", policy.MaxBulkItems)
+	}
+}
+
+func TestCommandPolicy_Checks(t *testing.T) {
+	deny := false
+	p := &commandPolicy{account: "me@example.com", Policy: config.Policy{
+		ProtectedMailboxes:      []string{"inbox", "Receipts"},
+		MaxBulkItems:            2,
+		AllowedRecipientDomains: []string{"example.com"},
+		AllowMaskedDelete:       &deny,
+		ProtectedPaths:          []string{"/Documents/Tax"},
+		AllowSieveChanges:       &deny,
+	}}
+
+	violations := map[string]error{
+		"bulk":          p.checkBulk("delete", 3),
+		"mailbox role":  p.checkMailboxDelete(jmap.Mailbox{ID: "m1", Name: "Inbox", Role: "inbox"}),
+		"mailbox name":  p.checkMailboxDelete(jmap.Mailbox{ID: "m2", Name: "receipts"}),
+		"recipient":     p.checkRecipients([]string{"a@example.com", "b@evil.com"}),
+		"masked":        p.checkMaskedDelete(),
+		"path":          p.checkFilesDelete("/Documents/Tax/2024.pdf"),
+		"parent path":   p.checkFilesDelete("/Documents"),
+		"sieve":         p.checkSieveChanges(),
+		"lookalike dom": p.checkRecipients([]string{"a@notexample.com"}),
+	}
+	for name, err := range violations {
+		if !errors.Is(err, ErrPolicyViolation) || ExitCode(err) != ExitPolicy {
+			t.Errorf("%s: error = %v, want policy violation", name, err)
+		}
+	}
+
+	allowed := map[string]error{
+		"bulk":      p.checkBulk("delete", 2),
+		"mailbox":   p.checkMailboxDelete(jmap.Mailbox{ID: "m3", Name: "Newsletters"}),
+		"recipient": p.checkRecipients([]string{"a@example.com", "b@mail.example.com"}),
+		"path":      p.checkFilesDelete("/Documents/Taxes.txt"),
+	}
+	for name, err := range allowed {
+		if err != nil {
+			t.Errorf("%s: error = %v, want allowed", name, err)
+		}
+	}
+
+	if err := (&commandPolicy{}).checkMaskedDelete(); err != nil {
+		t.Errorf("empty policy: %v", err)
+	}
+}
+
+func TestCommandPolicy_CheckBulkDelete(t *testing.T) {
+	p := &commandPolicy{account: "me@example.com", Policy: config.Policy{ProtectedMailboxes: []string{"inbox"}}}
+	client := &fakePolicyMailClient{
+		mailboxes: []jmap.Mailbox{{ID: "mb-inbox", Name: "Inbox", Role: "inbox"}, {ID: "mb-news", Name: "News"}},
+		emails: []jmap.Email{
+			{ID: "e1", MailboxIDs: map[string]bool{"mb-news": true}},
+			{ID: "e2", MailboxIDs: map[string]bool{"mb-inbox": true}},
+		},
+	}
+
+	ctx := context.Background()
+	if err := p.checkBulkDelete(ctx, client, []string{"e1"}); err != nil {
+		t.Errorf("unprotected: %v", err)
+	}
+	if err := p.checkBulkDelete(ctx, client, []string{"e1", "e2"}); !errors.Is(err, ErrPolicyViolation) {
+		t.Errorf("protected: error = %v, want policy violation", err)
+	}
+}
+
+func TestEmailForward_RefusesDisallowedRecipients(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv(config.ConfigFileEnvVarName, path)
+	if err := os.WriteFile(path, []byte(`{"policy": {"allowed_recipient_domains": ["example.com"]}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{
+		{"email", "forward", "M1", "--to", "x@evil.com"},
+		{"email", "forward", "M1", "--to", "x@evil.com", "--as-attachment"},
+	} {
+		captureStderr(t, func() {
+			err := Execute(append([]string{"--account", "me@example.com"}, args...))
+			if !errors.Is(err, ErrPolicyViolation) {
+				t.Errorf("%v: err = %v, want policy violation", args, err)
+			}
+		})
+	}
+}
+
+func TestEmailRecipients(t *testing.T) {
+	draft := &jmap.Email{
+		To:  []jmap.EmailAddress{{Email: "a@example.com"}},
+		CC:  []jmap.EmailAddress{{Email: "b@example.com"}},
+		BCC: []jmap.EmailAddress{{Email: "c@evil.com"}},
+	}
+	got := emailRecipients(draft)
+	if len(got) != 3 || got[2] != "c@evil.com" {
+		t.Fatalf("emailRecipients = %v", got)
+	}
+	p := &commandPolicy{account: "me@example.com", Policy: config.Policy{AllowedRecipientDomains: []string{"example.com"}}}
+	if err := p.checkRecipients(got); !errors.Is(err, ErrPolicyViolation) {
+		t.Errorf("a draft with a Bcc outside the allowed domains: err = %v", err)
+	}
+}
+
+func TestEmailSpam_RefusesOverMaxBulkItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv(config.ConfigFileEnvVarName, path)
+	if err := os.WriteFile(path, []byte(`{"policy": {"max_bulk_items": 1}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, command := range []string{"spam", "not-spam"} {
+		captureStderr(t, func() {
+			err := Execute([]string{"--account", "me@example.com", "email", command, "M1", "M2"})
+			if !errors.Is(err, ErrPolicyViolation) {
+				t.Errorf("%s: err = %v, want policy violation", command, err)
+			}
+		})
+	}
+}
+
+func TestRunEmailDedupe_RefusesOverMaxBulkItems(t *testing.T) {
+	app := newTestApp()
+	app.Flags.Yes = true
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	client := &fakeDedupeClient{
+		emails: []jmap.Email{
+			{ID: "a", MessageID: []string{"<m@x>"}, ReceivedAt: "2025-01-01T00:00:00Z"},
+			{ID: "b", MessageID: []string{"<m@x>"}, ReceivedAt: "2025-01-02T00:00:00Z"},
+			{ID: "c", MessageID: []string{"<m@x>"}, ReceivedAt: "2025-01-03T00:00:00Z"},
+		},
+	}
+	policy := &commandPolicy{account: "me@example.com", Policy: config.Policy{MaxBulkItems: 1}}
+
+	err := runEmailDedupe(cmd, app, client, policy, "", dedupeKeepOldest, 0, bulkBatchOptions{BatchSize: 50, Concurrency: 1}, false)
+	if !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("err = %v, want policy violation", err)
+	}
+	if len(client.deleteCalls) != 0 {
+		t.Errorf("nothing should be trashed, got %v", client.deleteCalls)
+	}
+}
+
+func TestBulkTrashCommands_RespectProtectedMailboxes(t *testing.T) {
+	app := newTestApp()
+	app.Flags.Yes = true
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	policy := &commandPolicy{account: "me@example.com", Policy: config.Policy{ProtectedMailboxes: []string{"Legal"}}}
+	mailboxes := []jmap.Mailbox{{ID: "inbox", Name: "Inbox", Role: "inbox"}, {ID: "legal", Name: "Legal"}}
+	inLegal := map[string]bool{"legal": true}
+
+	t.Run("dedupe", func(t *testing.T) {
+		client := &fakeDedupeClient{
+			mailboxes: mailboxes,
+			emails: []jmap.Email{
+				{ID: "a", MessageID: []string{"<m@x>"}, ReceivedAt: "2025-01-01T00:00:00Z", MailboxIDs: inLegal},
+				{ID: "b", MessageID: []string{"<m@x>"}, ReceivedAt: "2025-01-02T00:00:00Z", MailboxIDs: inLegal},
+			},
+		}
+		err := runEmailDedupe(cmd, app, client, policy, "", dedupeKeepOldest, 0, bulkBatchOptions{BatchSize: 50, Concurrency: 1}, false)
+		if !errors.Is(err, ErrPolicyViolation) {
+			t.Fatalf("err = %v, want policy violation", err)
+		}
+		if len(client.deleteCalls) != 0 {
+			t.Errorf("nothing should be trashed, got %v", client.deleteCalls)
+		}
+	})
+
+	t.Run("du --strip-attachments", func(t *testing.T) {
+		client := &fakeDuClient{
+			fakeDedupeClient: fakeDedupeClient{
+				mailboxes: mailboxes,
+				emails: []jmap.Email{{
+					ID: "e1", BlobID: "raw1", Size: 900, MailboxIDs: inLegal,
+					Attachments: []jmap.Attachment{{BlobID: "b1", Name: "scan.pdf", Size: 500}},
+				}},
+			},
+		}
+		err := runEmailDu(cmd, app, client, policy, "", 10, 0, true, 10, t.TempDir(), false)
+		if !errors.Is(err, ErrPolicyViolation) {
+			t.Fatalf("err = %v, want policy violation", err)
+		}
+		if len(client.imported) != 0 || len(client.deleteCalls) != 0 {
+			t.Errorf("nothing should change, got imports %v and trash %v", client.imported, client.deleteCalls)
+		}
+	})
+
+	t.Run("copy --move", func(t *testing.T) {
+		src := &fakeCopySource{
+			session:   &jmap.Session{AccountID: "a1", Accounts: map[string]jmap.SessionAccount{"a1": {}, "a2": {}}},
+			mailboxes: mailboxes,
+			emails:    []jmap.Email{{ID: "e1", BlobID: "b1", MailboxIDs: inLegal}},
+		}
+		dst := &fakeCopyTarget{session: &jmap.Session{AccountID: "a2"}}
+		err := runEmailCopy(cmd, app, src, dst, policy, "shared@example.com", []string{"e1"}, "", true)
+		if !errors.Is(err, ErrPolicyViolation) {
+			t.Fatalf("err = %v, want policy violation", err)
+		}
+		if len(src.blobCopies) != 0 || len(src.deleted) != 0 {
+			t.Errorf("nothing should be copied or trashed, got copies %v and trash %v", src.blobCopies, src.deleted)
+		}
+	})
+}
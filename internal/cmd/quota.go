@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -24,8 +25,17 @@ Shows used and available storage with a visual progress bar.
 Examples:
   fastmail quota                    # Show quotas with human-readable sizes
   fastmail quota --format bytes     # Show raw byte values
-  fastmail quota --format human     # Explicitly use human-readable format`,
+  fastmail quota --format human     # Explicitly use human-readable format
+  fastmail quota --account all      # Show quotas for every configured account`,
 		RunE: runE(app, func(cmd *cobra.Command, args []string, app *App) error {
+			accounts, multi, err := app.MultiAccounts()
+			if err != nil {
+				return err
+			}
+			if multi {
+				return runQuotaAccounts(cmd, app, accounts, formatFlag)
+			}
+
 			client, err := app.JMAPClient()
 			if err != nil {
 				return err
@@ -66,6 +76,47 @@ Examples:
 	return cmd
 }
 
+// runQuotaAccounts shows quotas for several accounts, grouped by account.
+func runQuotaAccounts(cmd *cobra.Command, app *App, accounts []string, formatMode string) error {
+	ctx := cmd.Context()
+
+	results, errs := fanOut(ctx, accounts, app.JMAPClientFor, func(ctx context.Context, client *jmap.Client) ([]jmap.Quota, error) {
+		return client.GetQuotas(ctx)
+	})
+	if err := checkAccountErrors(ctx, app, accounts, errs); err != nil {
+		return err
+	}
+
+	if app.IsJSON(ctx) {
+		var tagged []map[string]any
+		for _, r := range results {
+			for _, quota := range r.Value {
+				tagged = append(tagged, withAccount(r.Account, quota))
+			}
+		}
+		return app.PrintJSON(cmd, multiAccountJSON("quotas", tagged, errs))
+	}
+
+	for i, r := range results {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("== %s ==\n", r.Account)
+		if len(r.Value) == 0 {
+			fmt.Println("No quota information available")
+			continue
+		}
+		for j, quota := range r.Value {
+			if j > 0 {
+				fmt.Println()
+			}
+			displayQuota(quota, formatMode)
+		}
+	}
+
+	return nil
+}
+
 // displayQuota displays a single quota with formatting
 func displayQuota(quota jmap.Quota, formatMode string) {
 	tw := outfmt.NewTabWriter()
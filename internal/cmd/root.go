@@ -11,6 +11,7 @@ import (
 	cerrors "github.com/salmonumbrella/fastmail-cli/internal/errors"
 	"github.com/salmonumbrella/fastmail-cli/internal/logging"
 	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
 	"github.com/salmonumbrella/fastmail-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -28,12 +29,23 @@ var (
 type rootFlags struct {
 	Color          string
 	Account        string
+	JMAPAccount    string
 	Output         string
 	Debug          bool
 	Query          string
 	Yes            bool
 	NoInput        bool
 	NonInteractive bool
+	NoCache        bool
+	ReadOnly       bool
+	DryRun         bool
+	Proxy          string
+	CAFile         string
+	ClientCert     string
+	ClientKey      string
+	Trace          bool
+	TraceFile      string
+	LogFormat      string
 }
 
 type contextKey string
@@ -54,6 +66,9 @@ func Execute(args []string) error {
 	root.SetArgs(args)
 
 	err := root.Execute()
+	app.flushAudit()
+	app.printDryRunRequests(os.Stderr)
+	app.writeTraceFile()
 	if err != nil {
 		if app.Flags.Output == "json" {
 			payload := map[string]any{
@@ -111,10 +126,25 @@ func NewRootCmd(app *App) *cobra.Command {
 			}
 
 			// Logging
-			logger := logging.Setup(app.Flags.Debug)
+			format, err := logging.ParseFormat(app.Flags.LogFormat)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrUsage, err)
+			}
+			logger := logging.New(os.Stderr, app.Flags.Debug || app.Flags.Trace, format)
 			ctx = logging.WithLogger(ctx, logger)
 			app.Logger = logger
 
+			// HTTP transport shared by all API clients
+			if err := transport.Configure(transport.Options{
+				Proxy:    app.Flags.Proxy,
+				CAFile:   app.Flags.CAFile,
+				CertFile: app.Flags.ClientCert,
+				KeyFile:  app.Flags.ClientKey,
+				Tracer:   app.newTracer(logger),
+			}); err != nil {
+				return fmt.Errorf("%w: %w", ErrUsage, err)
+			}
+
 			ctx = WithApp(ctx, app)
 			cmd.SetContext(ctx)
 			return nil
@@ -122,9 +152,20 @@ func NewRootCmd(app *App) *cobra.Command {
 	}
 	root.PersistentFlags().StringVar(&app.Flags.Color, "color", app.Flags.Color, "Color output: auto|always|never")
 	root.PersistentFlags().StringVar(&app.Flags.Account, "account", envOr("FASTMAIL_ACCOUNT", ""), "Account email for API commands")
+	root.PersistentFlags().StringVar(&app.Flags.JMAPAccount, "jmap-account", envOr("FASTMAIL_JMAP_ACCOUNT", ""), "JMAP account ID or name within the session (shared/delegated accounts)")
 	root.PersistentFlags().StringVar(&app.Flags.Output, "output", app.Flags.Output, "Output format: text|json")
 	root.PersistentFlags().BoolVar(&app.Flags.Debug, "debug", false, "Enable debug logging")
+	root.PersistentFlags().BoolVar(&app.Flags.Trace, "trace", false, "Log every HTTP request, JMAP method call and retry (credentials redacted)")
+	root.PersistentFlags().StringVar(&app.Flags.TraceFile, "trace-file", "", "Write all HTTP traffic to a HAR file (credentials redacted)")
+	root.PersistentFlags().StringVar(&app.Flags.LogFormat, "log-format", envOr("FASTMAIL_LOG_FORMAT", "text"), "Log format: text|json")
 	root.PersistentFlags().StringVar(&app.Flags.Query, "query", "", "JQ filter expression for JSON output")
+	root.PersistentFlags().BoolVar(&app.Flags.NoCache, "no-cache", false, "Don't read or write the on-disk session/mailbox cache")
+	root.PersistentFlags().BoolVar(&app.Flags.ReadOnly, "read-only", envBool("FASTMAIL_READ_ONLY", false), "Refuse any request that would change data (exit code 7)")
+	root.PersistentFlags().BoolVar(&app.Flags.DryRun, "dry-run", envBool("FASTMAIL_DRY_RUN", false), "Send only read requests; print the write requests instead of sending them")
+	root.PersistentFlags().StringVar(&app.Flags.Proxy, "proxy", envOr("FASTMAIL_PROXY", ""), "Proxy URL for all requests (default: HTTPS_PROXY/HTTP_PROXY/NO_PROXY)")
+	root.PersistentFlags().StringVar(&app.Flags.CAFile, "ca-file", envOr("FASTMAIL_CA_FILE", ""), "PEM bundle of extra CA certificates to trust")
+	root.PersistentFlags().StringVar(&app.Flags.ClientCert, "client-cert", envOr("FASTMAIL_CLIENT_CERT", ""), "PEM client certificate for TLS (with --client-key)")
+	root.PersistentFlags().StringVar(&app.Flags.ClientKey, "client-key", envOr("FASTMAIL_CLIENT_KEY", ""), "PEM private key for --client-cert")
 	root.PersistentFlags().BoolVarP(&app.Flags.Yes, "yes", "y", false, "Skip confirmation prompts (non-interactive)")
 	root.PersistentFlags().BoolVar(&app.Flags.NoInput, "no-input", false, "Alias for --yes (non-interactive)")
 	root.PersistentFlags().BoolVar(&app.Flags.NonInteractive, "non-interactive", false, "Alias for --yes (non-interactive)")
@@ -138,9 +179,15 @@ func NewRootCmd(app *App) *cobra.Command {
 	root.AddCommand(newContactsCmd(app))
 	root.AddCommand(newCalendarCmd(app))
 	root.AddCommand(newQuotaCmd(app))
+	root.AddCommand(newCapabilitiesCmd(app))
 	root.AddCommand(newFilesCmd(app))
+	root.AddCommand(newAttachmentsCmd(app))
 	root.AddCommand(newSieveCmd(app))
 	root.AddCommand(newDraftCmd(app))
+	root.AddCommand(newJMAPCmd(app))
+	root.AddCommand(newCacheCmd(app))
+	root.AddCommand(newHistoryCmd(app))
+	root.AddCommand(newUndoCmd(app))
 
 	// Desire paths: top-level shortcuts for common email workflows.
 	root.AddCommand(newSearchShortcutCmd(app))
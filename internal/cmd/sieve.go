@@ -124,7 +124,18 @@ func (app *App) SieveClient() (*jmap.SieveClient, error) {
 		return nil, fmt.Errorf("sieve credentials not configured; run 'fastmail sieve auth' first")
 	}
 
-	return jmap.NewSieveClientFromCredentials(token, cookie), nil
+	endpoints, err := app.Endpoints(accountEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	client := jmap.NewSieveClientFromCredentials(token, cookie)
+	if endpoints.JMAP != "" {
+		client = jmap.NewSieveClient(token, cookie, jmap.SessionURLFor(endpoints.JMAP), "")
+	}
+	client.SetReadOnly(app.ReadOnly())
+	client.SetHTTPClient(app.auditedHTTPClient(accountEmail))
+	return client, nil
 }
 
 func newSieveGetCmd(app *App) *cobra.Command {
@@ -257,6 +268,14 @@ You can provide content directly via flags or from files:
 				return fmt.Errorf("at least one of --start, --middle, --end (or their -file variants) is required")
 			}
 
+			policy, err := app.Policy()
+			if err != nil {
+				return err
+			}
+			if err := policy.checkSieveChanges(); err != nil {
+				return err
+			}
+
 			if err := client.SetSieveBlocks(cmd.Context(), opts); err != nil {
 				return fmt.Errorf("failed to update sieve: %w", err)
 			}
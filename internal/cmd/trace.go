@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/outfmt"
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
+)
+
+// newTracer returns the tracer for --trace (logging to logger) and
+// --trace-file (recording a HAR), or nil with neither.
+func (a *App) newTracer(logger *slog.Logger) *transport.Tracer {
+	if a.Flags == nil || (!a.Flags.Trace && a.Flags.TraceFile == "") {
+		return nil
+	}
+	var traceLogger *slog.Logger
+	if a.Flags.Trace {
+		traceLogger = logger
+	}
+	a.tracer = transport.NewTracer(traceLogger, a.Flags.TraceFile != "")
+	return a.tracer
+}
+
+// writeTraceFile writes the HAR for --trace-file. It runs even when the
+// command failed, as that is when the file is most useful.
+func (a *App) writeTraceFile() {
+	if a.tracer == nil || a.Flags.TraceFile == "" {
+		return
+	}
+	// HAR files hold message contents, so keep them private.
+	f, err := os.OpenFile(a.Flags.TraceFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		outfmt.Errorf("warning: could not write trace file: %v", err)
+		return
+	}
+	err = a.tracer.WriteHAR(f, Version)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		outfmt.Errorf("warning: could not write trace file: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
+)
+
+func TestExecute_TraceFileWritesHAR(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { _ = transport.Configure(transport.Options{}) })
+
+	path := filepath.Join(t.TempDir(), "out.har")
+	captureStderr(t, func() {
+		captureStdout(t, func() {
+			if err := Execute([]string{"--trace-file", path, "email", "track", "status"}); err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+		})
+	})
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("trace file not written: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("trace file permissions = %o, want 600", perm)
+	}
+	data, _ := os.ReadFile(path)
+	var har struct {
+		Log struct {
+			Version string `json:"version"`
+			Entries []any  `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(data, &har); err != nil || har.Log.Version != "1.2" || har.Log.Entries == nil {
+		t.Errorf("trace file is not a HAR (%v): %s", err, data)
+	}
+}
+
+func TestExecute_InvalidLogFormat(t *testing.T) {
+	captureStderr(t, func() {
+		err := Execute([]string{"--log-format", "yaml", "email", "track", "status"})
+		if !errors.Is(err, ErrUsage) {
+			t.Errorf("err = %v, want usage error", err)
+		}
+	})
+}
+
+func TestNewTracer_DisabledByDefault(t *testing.T) {
+	app := &App{Flags: &rootFlags{}}
+	if app.newTracer(nil) != nil {
+		t.Error("tracer should be nil without --trace or --trace-file")
+	}
+}
@@ -15,11 +15,13 @@ import (
 
 // Token represents a stored API token with metadata
 type Token struct {
-	Email           string    `json:"email"`
-	CreatedAt       time.Time `json:"created_at,omitempty"`
-	IsPrimary       bool      `json:"is_primary,omitempty"`
-	DefaultIdentity string    `json:"default_identity,omitempty"` // Preferred sending identity for this account
-	APIToken        string    `json:"-"`                          // Never serialize the token
+	Email           string                 `json:"email"`
+	CreatedAt       time.Time              `json:"created_at,omitempty"`
+	IsPrimary       bool                   `json:"is_primary,omitempty"`
+	DefaultIdentity string                 `json:"default_identity,omitempty"` // Preferred sending identity for this account
+	TokenCommand    bool                   `json:"token_command,omitempty"`    // Token comes from a credential helper
+	Scoped          map[string]ScopedToken `json:"scoped_tokens,omitempty"`    // Scoped tokens, without their secrets
+	APIToken        string                 `json:"-"`                          // Never serialize the token
 }
 
 type storedToken struct {
@@ -27,6 +29,15 @@ type storedToken struct {
 	CreatedAt       time.Time `json:"created_at,omitempty"`
 	IsPrimary       bool      `json:"is_primary,omitempty"`
 	DefaultIdentity string    `json:"default_identity,omitempty"`
+
+	// OAuth logins also store what is needed to refresh APIToken.
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+	TokenURL     string    `json:"token_url,omitempty"`
+	ClientID     string    `json:"client_id,omitempty"`
+
+	// Extra tokens limited to a scope (read, write, send), keyed by scope.
+	Scoped map[string]ScopedToken `json:"scoped_tokens,omitempty"`
 }
 
 var openKeyring = func() (keyring.Keyring, error) {
@@ -102,7 +113,8 @@ func allowedBackendsFor(goos, dbusSessionBusAddress, backendSetting string) []ke
 	}
 }
 
-// SaveToken stores an API token in the OS keychain
+// SaveToken stores an API token in the OS keychain. It returns
+// ErrTokenCommand if the account's token comes from a token_command.
 // If this is the first account, it's automatically set as primary
 func SaveToken(email, token string) error {
 	email = normalize(email)
@@ -113,6 +125,10 @@ func SaveToken(email, token string) error {
 		return fmt.Errorf("missing token")
 	}
 
+	if err := CheckKeyringAccount(email); err != nil {
+		return err
+	}
+
 	ring, err := openKeyring()
 	if err != nil {
 		return err
@@ -122,10 +138,20 @@ func SaveToken(email, token string) error {
 	accounts, _ := ListAccounts() //nolint:errcheck // best-effort check for existing accounts
 	isPrimary := len(accounts) == 0
 
+	// Keep scoped tokens added with "auth add --scope".
+	var scoped map[string]ScopedToken
+	if item, err := ring.Get(tokenKey(email)); err == nil {
+		var old storedToken
+		if json.Unmarshal(item.Data, &old) == nil {
+			scoped = old.Scoped
+		}
+	}
+
 	payload, err := json.Marshal(storedToken{
 		APIToken:  token,
 		CreatedAt: time.Now().UTC(),
 		IsPrimary: isPrimary,
+		Scoped:    scoped,
 	})
 	if err != nil {
 		return err
@@ -284,13 +310,22 @@ func GetPrimaryAccount() (string, error) {
 	return "", nil
 }
 
-// GetToken retrieves an API token from the OS keychain
+// GetToken retrieves an API token from the account's token_command, or the
+// OS keychain if none is configured
 func GetToken(email string) (string, error) {
 	email = normalize(email)
 	if email == "" {
 		return "", fmt.Errorf("missing email")
 	}
 
+	command, err := TokenCommandFor(email)
+	if err != nil {
+		return "", err
+	}
+	if command != "" {
+		return helperGetToken(command, email)
+	}
+
 	ring, err := openKeyring()
 	if err != nil {
 		return "", err
@@ -305,17 +340,25 @@ func GetToken(email string) (string, error) {
 	if err := json.Unmarshal(item.Data, &st); err != nil {
 		return "", err
 	}
+	if st.APIToken == "" {
+		return strongestScopedToken(st.Scoped), nil
+	}
 
 	return st.APIToken, nil
 }
 
-// DeleteToken removes an API token from the OS keychain
+// DeleteToken removes an API token from the OS keychain. It returns
+// ErrTokenCommand if the account's token comes from a token_command
 func DeleteToken(email string) error {
 	email = normalize(email)
 	if email == "" {
 		return fmt.Errorf("missing email")
 	}
 
+	if err := CheckKeyringAccount(email); err != nil {
+		return err
+	}
+
 	ring, err := openKeyring()
 	if err != nil {
 		return err
@@ -337,12 +380,20 @@ func ListAccounts() ([]string, error) {
 	}
 
 	accounts := make([]string, 0)
+	seen := make(map[string]bool)
 	for _, k := range keys {
 		email, ok := parseTokenKey(k)
 		if !ok {
 			continue
 		}
 		accounts = append(accounts, email)
+		seen[email] = true
+	}
+
+	for _, email := range helperAccounts() {
+		if !seen[email] {
+			accounts = append(accounts, email)
+		}
 	}
 
 	return accounts, nil
@@ -387,10 +438,24 @@ func ListTokens() ([]Token, error) {
 			CreatedAt:       st.CreatedAt,
 			IsPrimary:       st.IsPrimary,
 			DefaultIdentity: st.DefaultIdentity,
+			Scoped:          redactScopedTokens(st.Scoped),
 			// APIToken intentionally omitted - use GetToken() when needed
 		})
 	}
 
+	for _, email := range helperAccounts() {
+		found := false
+		for i := range tokens {
+			if tokens[i].Email == email {
+				tokens[i].TokenCommand = true
+				found = true
+			}
+		}
+		if !found {
+			tokens = append(tokens, Token{Email: email, TokenCommand: true})
+		}
+	}
+
 	return tokens, nil
 }
 
@@ -49,6 +49,8 @@ func (m *mockKeyring) GetMetadata(_ string) (keyring.Metadata, error) {
 
 func setupMockKeyring(t *testing.T) *mockKeyring {
 	t.Helper()
+	t.Setenv(ConfigFileEnvVarName, filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv(TokenCommandEnvVarName, "")
 	mock := newMockKeyring()
 	originalOpenKeyring := openKeyring
 	openKeyring = func() (keyring.Keyring, error) {
@@ -0,0 +1,35 @@
+package config
+
+// Endpoints points an account at servers other than Fastmail's, such as a
+// Stalwart or Cyrus JMAP server or a local fake for integration tests. Empty
+// fields use the Fastmail default.
+type Endpoints struct {
+	// JMAP is the JMAP session URL. A URL without a path, such as
+	// "https://mail.example.com", is resolved via /.well-known/jmap.
+	JMAP string `json:"jmap,omitempty"`
+	// WebDAV is the base URL for file storage.
+	WebDAV string `json:"webdav,omitempty"`
+	// CalDAV is the base URL for calendars.
+	CalDAV string `json:"caldav,omitempty"`
+}
+
+// EndpointsFor returns the endpoints for email: each field from the account's
+// own endpoints if set, else from the config file's top-level ones.
+func (s *Settings) EndpointsFor(email string) Endpoints {
+	var ep Endpoints
+	if s.Endpoints != nil {
+		ep = *s.Endpoints
+	}
+	if own := s.Account(email).Endpoints; own != nil {
+		if own.JMAP != "" {
+			ep.JMAP = own.JMAP
+		}
+		if own.WebDAV != "" {
+			ep.WebDAV = own.WebDAV
+		}
+		if own.CalDAV != "" {
+			ep.CalDAV = own.CalDAV
+		}
+	}
+	return ep
+}
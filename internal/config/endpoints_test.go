@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestEndpointsFor(t *testing.T) {
+	s := &Settings{
+		Endpoints: &Endpoints{JMAP: "https://jmap.example.com", CalDAV: "https://cal.example.com"},
+		Accounts: map[string]AccountSettings{
+			"me@example.com": {Endpoints: &Endpoints{JMAP: "http://127.0.0.1:8080"}},
+		},
+	}
+
+	got := s.EndpointsFor("Me@Example.com")
+	want := Endpoints{JMAP: "http://127.0.0.1:8080", CalDAV: "https://cal.example.com"}
+	if got != want {
+		t.Errorf("EndpointsFor(me) = %+v, want %+v", got, want)
+	}
+	if got := s.EndpointsFor("other@example.com"); got != *s.Endpoints {
+		t.Errorf("EndpointsFor(other) = %+v, want the top-level endpoints", got)
+	}
+	if got := (&Settings{}).EndpointsFor("me@example.com"); got != (Endpoints{}) {
+		t.Errorf("EndpointsFor without config = %+v, want zero", got)
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/99designs/keyring"
+)
+
+// OAuthCredentials are the refresh details stored alongside an OAuth access
+// token.
+type OAuthCredentials struct {
+	RefreshToken string
+	ExpiresAt    time.Time
+	TokenURL     string
+	ClientID     string
+}
+
+// SaveOAuthToken stores an OAuth access token and its refresh details in the
+// OS keychain. The account's primary flag and default identity are kept; a
+// new account becomes primary if it is the first one.
+func SaveOAuthToken(email, accessToken string, creds OAuthCredentials) error {
+	email = normalize(email)
+	if email == "" {
+		return fmt.Errorf("missing email")
+	}
+	if accessToken == "" {
+		return fmt.Errorf("missing token")
+	}
+
+	ring, err := openKeyring()
+	if err != nil {
+		return err
+	}
+
+	var st storedToken
+	if item, err := ring.Get(tokenKey(email)); err == nil {
+		_ = json.Unmarshal(item.Data, &st) //nolint:errcheck // start over if the old entry is unreadable
+	} else {
+		accounts, _ := ListAccounts() //nolint:errcheck // best-effort check for existing accounts
+		st.IsPrimary = len(accounts) == 0
+	}
+
+	st.APIToken = accessToken
+	st.CreatedAt = time.Now().UTC()
+	st.RefreshToken = creds.RefreshToken
+	st.ExpiresAt = creds.ExpiresAt
+	st.TokenURL = creds.TokenURL
+	st.ClientID = creds.ClientID
+
+	payload, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	return ring.Set(keyring.Item{
+		Key:  tokenKey(email),
+		Data: payload,
+	})
+}
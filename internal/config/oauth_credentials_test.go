@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSaveOAuthToken_KeepsAccountSettings(t *testing.T) {
+	setupMockKeyring(t)
+
+	if err := SaveToken("user@example.com", "api-token"); err != nil {
+		t.Fatalf("SaveToken: %v", err)
+	}
+	if err := SetDefaultIdentity("user@example.com", "alias@example.com"); err != nil {
+		t.Fatalf("SetDefaultIdentity: %v", err)
+	}
+
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	creds := OAuthCredentials{
+		RefreshToken: "refresh",
+		ExpiresAt:    expires,
+		TokenURL:     "https://auth.example.com/token",
+		ClientID:     "client",
+	}
+	if err := SaveOAuthToken("User@Example.com", "access", creds); err != nil {
+		t.Fatalf("SaveOAuthToken: %v", err)
+	}
+
+	identity, _ := GetDefaultIdentity("user@example.com")
+	if identity != "alias@example.com" {
+		t.Errorf("default identity = %q, want it kept", identity)
+	}
+	primary, _ := GetPrimaryAccount()
+	if primary != "user@example.com" {
+		t.Errorf("primary = %q, want it kept", primary)
+	}
+
+	got, err := GetCredentials("user@example.com")
+	if err != nil {
+		t.Fatalf("GetCredentials: %v", err)
+	}
+	if got.Token != "access" {
+		t.Errorf("token = %q, want access", got.Token)
+	}
+	if got.OAuth == nil || *got.OAuth != creds {
+		t.Errorf("OAuth = %+v, want %+v", got.OAuth, creds)
+	}
+}
+
+func TestGetCredentials_APIToken(t *testing.T) {
+	setupMockKeyring(t)
+
+	if err := SaveToken("user@example.com", "api-token"); err != nil {
+		t.Fatalf("SaveToken: %v", err)
+	}
+	got, err := GetCredentials("user@example.com")
+	if err != nil || got.Token != "api-token" || got.OAuth != nil {
+		t.Errorf("GetCredentials = %+v, %v; want API token without OAuth details", got, err)
+	}
+}
@@ -1,5 +1,11 @@
 package config
 
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
 const (
 	AppName = "fastmail-cli"
 
@@ -18,4 +24,65 @@ const (
 	// KeyringBackendEnvVarName controls keyring backend selection. Supported
 	// values: auto|default|file|keychain|wincred|secret-service.
 	KeyringBackendEnvVarName = "FASTMAIL_KEYRING_BACKEND"
+
+	// StateDirEnvVarName overrides the directory used for local state files
+	// such as the snooze schedule.
+	StateDirEnvVarName = "FASTMAIL_STATE_DIR"
+
+	// CacheDirEnvVarName overrides the directory used for cached API data
+	// such as the JMAP session and mailbox list.
+	CacheDirEnvVarName = "FASTMAIL_CACHE_DIR"
+
+	// ConfigFileEnvVarName overrides the path of the optional config file.
+	ConfigFileEnvVarName = "FASTMAIL_CONFIG"
+
+	// TokenCommandEnvVarName sets a credential helper for every account that
+	// has no token_command of its own in the config file.
+	TokenCommandEnvVarName = "FASTMAIL_TOKEN_COMMAND" // #nosec G101 -- environment variable name
 )
+
+// StateDir returns the directory for fastmail-cli state files. It does not
+// create the directory.
+func StateDir() string {
+	if dir := strings.TrimSpace(os.Getenv(StateDirEnvVarName)); dir != "" {
+		return dir
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, AppName)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".config", AppName)
+	}
+	return "." + AppName
+}
+
+// CacheDir returns the directory for fastmail-cli cache files. It does not
+// create the directory.
+func CacheDir() string {
+	if dir := strings.TrimSpace(os.Getenv(CacheDirEnvVarName)); dir != "" {
+		return dir
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, AppName)
+	}
+	return filepath.Join(StateDir(), "cache")
+}
+
+// SafeName maps an account or key to a single path component, for the
+// per-account cache and state files.
+func SafeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '@', r == '.', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	if b.Len() == 0 || strings.Trim(b.String(), ".") == "" {
+		return "_"
+	}
+	return b.String()
+}
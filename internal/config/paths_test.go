@@ -0,0 +1,18 @@
+package config
+
+import "testing"
+
+func TestSafeName(t *testing.T) {
+	tests := map[string]string{
+		"me@example.com": "me@example.com",
+		"../etc/passwd":  ".._etc_passwd",
+		"..":             "_",
+		"":               "_",
+		"a b/c":          "a_b_c",
+	}
+	for in, want := range tests {
+		if got := SafeName(in); got != want {
+			t.Errorf("SafeName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+// Policy is a safety policy that mutating commands check before calling the
+// API. Zero values impose no restriction.
+type Policy struct {
+	// ProtectedMailboxes are mailbox names, IDs or roles (e.g. "inbox") that
+	// may not be deleted, and whose emails may not be bulk-deleted.
+	ProtectedMailboxes []string `json:"protected_mailboxes,omitempty"`
+	// MaxBulkItems caps the number of items one bulk operation may change.
+	MaxBulkItems int `json:"max_bulk_items,omitempty"`
+	// AllowedRecipientDomains limits who "email send" may send to. Entries
+	// match the domain and its subdomains.
+	AllowedRecipientDomains []string `json:"allowed_recipient_domains,omitempty"`
+	// AllowMaskedDelete permits deleting masked emails (default true).
+	AllowMaskedDelete *bool `json:"allow_masked_delete,omitempty"`
+	// ProtectedPaths are file storage paths that may not be deleted, along
+	// with everything under them.
+	ProtectedPaths []string `json:"protected_paths,omitempty"`
+	// AllowSieveChanges permits "sieve set" (default true).
+	AllowSieveChanges *bool `json:"allow_sieve_changes,omitempty"`
+}
+
+// PolicyFor returns the safety policy for email: the account's own policy,
+// else the config file's top-level one. It returns nil if neither is set.
+func (s *Settings) PolicyFor(email string) *Policy {
+	if policy := s.Account(email).Policy; policy != nil {
+		return policy
+	}
+	return s.Policy
+}
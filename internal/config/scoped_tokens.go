@@ -0,0 +1,222 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/99designs/keyring"
+)
+
+// Token scopes, from least to most privileged.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeSend  = "send"
+)
+
+var tokenScopes = []string{ScopeRead, ScopeWrite, ScopeSend}
+
+// ScopedToken is an extra API token limited to a scope, stored alongside an
+// account's main token. Capabilities and ReadOnly record what the JMAP
+// session reported for the token when it was added.
+type ScopedToken struct {
+	APIToken     string    `json:"api_token,omitempty"`
+	Capabilities []string  `json:"capabilities,omitempty"`
+	ReadOnly     bool      `json:"read_only,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+}
+
+// Credentials are everything stored for an account's API access.
+type Credentials struct {
+	// Token is the account's main token. It is empty when only scoped tokens
+	// are stored.
+	Token string
+	// OAuth holds refresh details for OAuth access tokens, nil otherwise.
+	OAuth *OAuthCredentials
+	// Scoped maps scope names to tokens limited to that scope.
+	Scoped map[string]string
+}
+
+// SaveScopedToken stores a token limited to scope for an account. The
+// account entry is created if needed, without a main token; a new account
+// becomes primary if it is the first one.
+func SaveScopedToken(email, scope string, token ScopedToken) error {
+	email = normalize(email)
+	if email == "" {
+		return fmt.Errorf("missing email")
+	}
+	if !validScope(scope) {
+		return fmt.Errorf("invalid token scope %q", scope)
+	}
+	if token.APIToken == "" {
+		return fmt.Errorf("missing token")
+	}
+
+	ring, err := openKeyring()
+	if err != nil {
+		return err
+	}
+
+	var st storedToken
+	if item, err := ring.Get(tokenKey(email)); err == nil {
+		if err := json.Unmarshal(item.Data, &st); err != nil {
+			return err
+		}
+	} else {
+		accounts, _ := ListAccounts() //nolint:errcheck // best-effort check for existing accounts
+		st.IsPrimary = len(accounts) == 0
+		st.CreatedAt = time.Now().UTC()
+	}
+
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now().UTC()
+	}
+	if st.Scoped == nil {
+		st.Scoped = make(map[string]ScopedToken)
+	}
+	st.Scoped[scope] = token
+
+	payload, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	return ring.Set(keyring.Item{
+		Key:  tokenKey(email),
+		Data: payload,
+	})
+}
+
+// DeleteScopedToken removes an account's token for scope. The account entry
+// is removed too if no main token or other scoped token is left.
+func DeleteScopedToken(email, scope string) error {
+	email = normalize(email)
+	if email == "" {
+		return fmt.Errorf("missing email")
+	}
+
+	ring, err := openKeyring()
+	if err != nil {
+		return err
+	}
+
+	item, err := ring.Get(tokenKey(email))
+	if err != nil {
+		return fmt.Errorf("account not found: %s", email)
+	}
+
+	var st storedToken
+	if err := json.Unmarshal(item.Data, &st); err != nil {
+		return err
+	}
+	if _, ok := st.Scoped[scope]; !ok {
+		return fmt.Errorf("no %s token stored for %s", scope, email)
+	}
+	delete(st.Scoped, scope)
+
+	if st.APIToken == "" && len(st.Scoped) == 0 {
+		return ring.Remove(tokenKey(email))
+	}
+
+	payload, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	return ring.Set(keyring.Item{
+		Key:  tokenKey(email),
+		Data: payload,
+	})
+}
+
+// GetCredentials retrieves an account's main token together with its OAuth
+// refresh details and scoped tokens. It reads the keychain once, unlike
+// GetToken followed by separate lookups. Accounts using a token_command only
+// have a main token.
+func GetCredentials(email string) (*Credentials, error) {
+	email = normalize(email)
+	if email == "" {
+		return nil, fmt.Errorf("missing email")
+	}
+
+	command, err := TokenCommandFor(email)
+	if err != nil {
+		return nil, err
+	}
+	if command != "" {
+		token, err := helperGetToken(command, email)
+		if err != nil {
+			return nil, err
+		}
+		return &Credentials{Token: token}, nil
+	}
+
+	ring, err := openKeyring()
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := ring.Get(tokenKey(email))
+	if err != nil {
+		return nil, err
+	}
+
+	var st storedToken
+	if err := json.Unmarshal(item.Data, &st); err != nil {
+		return nil, err
+	}
+
+	creds := &Credentials{Token: st.APIToken}
+	if st.RefreshToken != "" {
+		creds.OAuth = &OAuthCredentials{
+			RefreshToken: st.RefreshToken,
+			ExpiresAt:    st.ExpiresAt,
+			TokenURL:     st.TokenURL,
+			ClientID:     st.ClientID,
+		}
+	}
+	for scope, scoped := range st.Scoped {
+		if scoped.APIToken == "" {
+			continue
+		}
+		if creds.Scoped == nil {
+			creds.Scoped = make(map[string]string)
+		}
+		creds.Scoped[scope] = scoped.APIToken
+	}
+	return creds, nil
+}
+
+// strongestScopedToken returns the most privileged scoped token, for callers
+// such as WebDAV that cannot pick a token per request.
+func strongestScopedToken(scoped map[string]ScopedToken) string {
+	for i := len(tokenScopes) - 1; i >= 0; i-- {
+		if token := scoped[tokenScopes[i]].APIToken; token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// redactScopedTokens copies scoped token metadata without the tokens.
+func redactScopedTokens(scoped map[string]ScopedToken) map[string]ScopedToken {
+	if len(scoped) == 0 {
+		return nil
+	}
+	out := make(map[string]ScopedToken, len(scoped))
+	for scope, token := range scoped {
+		token.APIToken = ""
+		out[scope] = token
+	}
+	return out
+}
+
+func validScope(scope string) bool {
+	for _, s := range tokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package config
+
+import "testing"
+
+func TestScopedTokens(t *testing.T) {
+	setupMockKeyring(t)
+
+	if err := SaveScopedToken("User@Example.com", ScopeRead, ScopedToken{
+		APIToken:     "read-token",
+		Capabilities: []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail"},
+		ReadOnly:     true,
+	}); err != nil {
+		t.Fatalf("SaveScopedToken(read): %v", err)
+	}
+	if err := SaveScopedToken("user@example.com", "admin", ScopedToken{APIToken: "x"}); err == nil {
+		t.Error("SaveScopedToken should reject unknown scopes")
+	}
+
+	primary, _ := GetPrimaryAccount()
+	if primary != "user@example.com" {
+		t.Errorf("primary = %q, want first account to become primary", primary)
+	}
+
+	// With only a read token, GetToken falls back to it.
+	if token, err := GetToken("user@example.com"); err != nil || token != "read-token" {
+		t.Errorf("GetToken = %q, %v; want read-token", token, err)
+	}
+
+	// Saving a main token keeps scoped tokens.
+	if err := SaveToken("user@example.com", "main-token"); err != nil {
+		t.Fatalf("SaveToken: %v", err)
+	}
+	creds, err := GetCredentials("user@example.com")
+	if err != nil {
+		t.Fatalf("GetCredentials: %v", err)
+	}
+	if creds.Token != "main-token" || creds.Scoped[ScopeRead] != "read-token" {
+		t.Errorf("credentials = %+v", creds)
+	}
+
+	tokens, err := ListTokens()
+	if err != nil || len(tokens) != 1 {
+		t.Fatalf("ListTokens = %+v, %v", tokens, err)
+	}
+	read, ok := tokens[0].Scoped[ScopeRead]
+	if !ok || !read.ReadOnly || len(read.Capabilities) != 2 {
+		t.Errorf("listed read token = %+v", read)
+	}
+	if read.APIToken != "" {
+		t.Error("ListTokens must not return scoped token secrets")
+	}
+
+	if err := DeleteScopedToken("user@example.com", ScopeWrite); err == nil {
+		t.Error("DeleteScopedToken(write) should fail when none is stored")
+	}
+	if err := DeleteScopedToken("user@example.com", ScopeRead); err != nil {
+		t.Fatalf("DeleteScopedToken(read): %v", err)
+	}
+	creds, _ = GetCredentials("user@example.com")
+	if creds == nil || creds.Token != "main-token" || len(creds.Scoped) != 0 {
+		t.Errorf("after delete, credentials = %+v", creds)
+	}
+}
+
+func TestDeleteScopedToken_RemovesEmptyAccount(t *testing.T) {
+	setupMockKeyring(t)
+
+	if err := SaveScopedToken("user@example.com", ScopeWrite, ScopedToken{APIToken: "w"}); err != nil {
+		t.Fatalf("SaveScopedToken: %v", err)
+	}
+	if err := DeleteScopedToken("user@example.com", ScopeWrite); err != nil {
+		t.Fatalf("DeleteScopedToken: %v", err)
+	}
+	if accounts, _ := ListAccounts(); len(accounts) != 0 {
+		t.Errorf("accounts = %v, want the empty entry removed", accounts)
+	}
+}
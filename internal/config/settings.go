@@ -0,0 +1,89 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Settings is the optional config file for options that do not belong in the
+// keyring:
+//
+//	{
+//	  "token_command": "my-helper",
+//	  "policy": {"max_bulk_items": 500},
+//	  "accounts": {
+//	    "you@fastmail.com": {"token_command": "op read op://Private/Fastmail/token"},
+//	    "me@example.com": {"endpoints": {"jmap": "https://mail.example.com"}}
+//	  }
+//	}
+type Settings struct {
+	// TokenCommand is the credential helper for accounts without their own.
+	TokenCommand string `json:"token_command,omitempty"`
+	// Policy is the safety policy for accounts without their own.
+	Policy *Policy `json:"policy,omitempty"`
+	// Endpoints are the servers for accounts without their own.
+	Endpoints *Endpoints                 `json:"endpoints,omitempty"`
+	Accounts  map[string]AccountSettings `json:"accounts,omitempty"`
+}
+
+// AccountSettings holds per-account options.
+type AccountSettings struct {
+	TokenCommand string     `json:"token_command,omitempty"`
+	Policy       *Policy    `json:"policy,omitempty"`
+	Endpoints    *Endpoints `json:"endpoints,omitempty"`
+}
+
+// SettingsPath returns the config file path: $FASTMAIL_CONFIG, or
+// config.json in the state directory.
+func SettingsPath() string {
+	if path := strings.TrimSpace(os.Getenv(ConfigFileEnvVarName)); path != "" {
+		return path
+	}
+	return filepath.Join(StateDir(), "config.json")
+}
+
+// LoadSettings reads the config file. A missing file yields empty settings.
+func LoadSettings() (*Settings, error) {
+	path := SettingsPath()
+	data, err := os.ReadFile(path) //nolint:gosec // path is user-configured
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Settings{}, nil
+		}
+		return nil, fmt.Errorf("read config %s: %w", path, err)
+	}
+
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	// Account keys are matched like keyring entries: case-insensitively.
+	if len(s.Accounts) > 0 {
+		accounts := make(map[string]AccountSettings, len(s.Accounts))
+		for email, acc := range s.Accounts {
+			accounts[normalize(email)] = acc
+		}
+		s.Accounts = accounts
+	}
+	return &s, nil
+}
+
+// Account returns the settings for email (the zero value if none).
+func (s *Settings) Account(email string) AccountSettings {
+	return s.Accounts[normalize(email)]
+}
+
+// AccountEmails returns the accounts listed in the config file, sorted.
+func (s *Settings) AccountEmails() []string {
+	emails := make([]string, 0, len(s.Accounts))
+	for email := range s.Accounts {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+	return emails
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenActionGet is the credential helper action, as in git-credential.
+// Helpers are only ever asked for a token; storing and erasing it is left to
+// the secret manager behind them.
+const TokenActionGet = "get"
+
+// ErrTokenCommand is returned when asked to store or remove the token of an
+// account that gets it from a token_command.
+var ErrTokenCommand = errors.New("token is managed by token_command")
+
+// tokenCommandTimeout bounds a helper run; helpers such as 1Password may wait
+// for the user to approve access.
+const tokenCommandTimeout = 2 * time.Minute
+
+// helperTokens caches tokens returned by "get" for the process lifetime, keyed
+// by command and account.
+var helperTokens sync.Map
+
+// TokenCommandFor returns the credential helper configured for email: the
+// account's token_command, else $FASTMAIL_TOKEN_COMMAND, else the config
+// file's top-level token_command. It returns "" when tokens come from the
+// keyring.
+func TokenCommandFor(email string) (string, error) {
+	settings, err := LoadSettings()
+	if err != nil {
+		return "", err
+	}
+	if command := strings.TrimSpace(settings.Account(email).TokenCommand); command != "" {
+		return command, nil
+	}
+	if command := strings.TrimSpace(os.Getenv(TokenCommandEnvVarName)); command != "" {
+		return command, nil
+	}
+	return strings.TrimSpace(settings.TokenCommand), nil
+}
+
+// helperAccounts returns the config file's accounts that get their token from
+// a credential helper, so they are listed alongside keyring accounts.
+func helperAccounts() []string {
+	settings, err := LoadSettings()
+	if err != nil {
+		return nil
+	}
+	var emails []string
+	for _, email := range settings.AccountEmails() {
+		if command, _ := TokenCommandFor(email); command != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// helperGetToken returns the token from command, running it at most once per
+// process for each account.
+func helperGetToken(command, email string) (string, error) {
+	key := command + "\x00" + email
+	if token, ok := helperTokens.Load(key); ok {
+		return token.(string), nil
+	}
+
+	out, err := runTokenCommand(command, TokenActionGet, email)
+	if err != nil {
+		return "", err
+	}
+	token := parseHelperToken(out)
+	if token == "" {
+		return "", fmt.Errorf("token_command for %s printed no token", email)
+	}
+
+	helperTokens.Store(key, token)
+	return token, nil
+}
+
+// CheckKeyringAccount returns ErrTokenCommand if email's token comes from a
+// token_command, which is only ever run to read it, so the token cannot be
+// stored or removed here.
+func CheckKeyringAccount(email string) error {
+	command, err := TokenCommandFor(email)
+	if err != nil {
+		return err
+	}
+	if command != "" {
+		return fmt.Errorf("%w for %s; update it in your secret manager, or remove token_command to keep it in the keyring", ErrTokenCommand, email)
+	}
+	return nil
+}
+
+// runTokenCommand runs a credential helper through the shell. As with
+// git-credential, the request is written to stdin as key=value lines ending
+// with a blank line: account=<email>. The action ("get") is $1 in the command
+// string and is also set in $FASTMAIL_TOKEN_ACTION, so git-style helpers and
+// plain commands such as "op read ..." or "pass show ..." both work. Stderr
+// is passed through for prompts.
+func runTokenCommand(command, action, email string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command) // #nosec G204 -- user-configured credential helper
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", command, "fastmail-token-command", action) // #nosec G204 -- user-configured credential helper
+	}
+
+	var input strings.Builder
+	fmt.Fprintf(&input, "account=%s\n", email)
+	input.WriteString("\n")
+
+	var stdout bytes.Buffer
+	cmd.Stdin = strings.NewReader(input.String())
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "FASTMAIL_TOKEN_ACTION="+action, "FASTMAIL_TOKEN_ACCOUNT="+email)
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("token_command %s for %s timed out after %s", action, email, tokenCommandTimeout)
+		}
+		return nil, fmt.Errorf("token_command %s for %s failed: %w", action, email, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// parseHelperToken reads a token from helper output: a token= (or git's
+// password=) line if there is one, otherwise the first non-empty line, as
+// printed by "pass show".
+func parseHelperToken(out []byte) string {
+	var first string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		for _, prefix := range []string{"token=", "password="} {
+			if strings.HasPrefix(line, prefix) {
+				return strings.TrimSpace(strings.TrimPrefix(line, prefix))
+			}
+		}
+		if first == "" {
+			first = line
+		}
+	}
+	return first
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// setupTokenHelper configures a shell credential helper for user@example.com
+// that logs each action and its stdin and prints a fixed token for get. It
+// returns the log path.
+func setupTokenHelper(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper tests use /bin/sh")
+	}
+	setupMockKeyring(t)
+	helperTokens.Clear()
+	t.Cleanup(helperTokens.Clear)
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "log")
+	script := filepath.Join(dir, "helper.sh")
+	body := `#!/bin/sh
+input=$(cat)
+echo "$1 $FASTMAIL_TOKEN_ACTION $FASTMAIL_TOKEN_ACCOUNT $(echo "$input" | tr '\n' ' ')" >> "` + logPath + `"
+echo "token=secret"
+`
+	if err := os.WriteFile(script, []byte(body), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := filepath.Join(dir, "config.json")
+	config := `{"accounts": {"User@Example.com": {"token_command": "` + script + ` \"$1\""}}}`
+	if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConfigFileEnvVarName, configPath)
+	return logPath
+}
+
+func readHelperLog(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading helper log: %v", err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestTokenCommand_GetOnly(t *testing.T) {
+	logPath := setupTokenHelper(t)
+
+	for range 2 {
+		token, err := GetToken("user@example.com")
+		if err != nil || token != "secret" {
+			t.Fatalf("GetToken = %q, %v; want secret", token, err)
+		}
+	}
+
+	// The helper is never run to store or erase: a get-only command such as
+	// "op read" would otherwise exit 0 and the token would seem saved.
+	if err := SaveToken("user@example.com", "new"); !errors.Is(err, ErrTokenCommand) {
+		t.Errorf("SaveToken error = %v, want ErrTokenCommand", err)
+	}
+	if err := DeleteToken("user@example.com"); !errors.Is(err, ErrTokenCommand) {
+		t.Errorf("DeleteToken error = %v, want ErrTokenCommand", err)
+	}
+
+	log := readHelperLog(t, logPath)
+	want := "get get user@example.com account=user@example.com"
+	if len(log) != 1 || strings.TrimSpace(log[0]) != want {
+		t.Errorf("helper calls = %q, want only %q (cached after the first get)", log, want)
+	}
+}
+
+func TestTokenCommand_ListsHelperAccounts(t *testing.T) {
+	setupTokenHelper(t)
+
+	accounts, err := ListAccounts()
+	if err != nil || len(accounts) != 1 || accounts[0] != "user@example.com" {
+		t.Errorf("ListAccounts = %v, %v", accounts, err)
+	}
+	primary, err := GetPrimaryAccount()
+	if err != nil || primary != "user@example.com" {
+		t.Errorf("GetPrimaryAccount = %q, %v", primary, err)
+	}
+}
+
+func TestTokenCommand_EnvHelperAndFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper tests use /bin/sh")
+	}
+	setupMockKeyring(t)
+	helperTokens.Clear()
+	t.Cleanup(helperTokens.Clear)
+
+	t.Setenv(TokenCommandEnvVarName, `printf 'abc123\nuser: me\n'`)
+	creds, err := GetCredentials("other@example.com")
+	if err != nil || creds.Token != "abc123" || creds.OAuth != nil {
+		t.Errorf("GetCredentials = %+v, %v; want first output line", creds, err)
+	}
+
+	t.Setenv(TokenCommandEnvVarName, "exit 3")
+	if _, err := GetToken("failing@example.com"); err == nil || !strings.Contains(err.Error(), "token_command get") {
+		t.Errorf("GetToken error = %v, want helper failure", err)
+	}
+}
+
+func TestParseHelperToken(t *testing.T) {
+	tests := map[string]string{
+		"tok\n":                      "tok",
+		"\n  tok  \nmeta: x\n":       "tok",
+		"username=me\npassword=pw\n": "pw",
+		"token=t1\n":                 "t1",
+		"":                           "",
+	}
+	for in, want := range tests {
+		if got := parseHelperToken([]byte(in)); got != want {
+			t.Errorf("parseHelperToken(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -14,12 +14,27 @@ func ParseDateTimeNow(s string) (time.Time, error) {
 }
 
 // ParseDateTime parses RFC3339, YYYY-MM-DD, or relative expressions like yesterday, 2h ago, or monday.
+// A day expression may be followed by a time of day such as "9am", "5:30pm" or "14:00"
+// (e.g. "monday 9am", "tomorrow at 17:00"); a bare time of day means today.
 func ParseDateTime(s string, now time.Time) (time.Time, error) {
 	raw := strings.TrimSpace(s)
 	if raw == "" {
 		return time.Time{}, fmt.Errorf("empty date")
 	}
 
+	if day, hour, minute, ok := splitTimeOfDay(raw); ok {
+		base := startOfDay(now)
+		if day != "" {
+			parsed, err := ParseDateTime(day, now)
+			if err != nil {
+				return time.Time{}, err
+			}
+			base = parsed
+		}
+		year, month, date := base.Date()
+		return time.Date(year, month, date, hour, minute, 0, 0, base.Location()), nil
+	}
+
 	normalized := strings.ToLower(raw)
 	normalized = strings.TrimSpace(strings.Trim(normalized, ".,"))
 
@@ -53,6 +68,75 @@ func ParseDateTime(s string, now time.Time) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("invalid date %q", raw)
 }
 
+// ParseFutureDateTime is ParseDateTime for times that must lie ahead, such as
+// snooze deadlines. A bare time of day that has already passed means
+// tomorrow, and a weekday (with or without a time) that has already passed
+// means that day next week. "today" and "this <weekday>" are kept as given.
+func ParseFutureDateTime(s string, now time.Time) (time.Time, error) {
+	t, err := ParseDateTime(s, now)
+	if err != nil || t.After(now) {
+		return t, err
+	}
+
+	day := strings.ToLower(strings.TrimSpace(s))
+	hasTime := false
+	if d, _, _, ok := splitTimeOfDay(day); ok {
+		day, hasTime = d, true
+	}
+	day = strings.TrimSpace(strings.Trim(day, ".,"))
+
+	switch {
+	case day == "" && hasTime:
+		return t.AddDate(0, 0, 1), nil
+	case isWeekday(strings.TrimPrefix(day, "next ")):
+		return t.AddDate(0, 0, 7), nil
+	}
+	return t, nil
+}
+
+// isWeekday reports whether input names a day of the week on its own.
+func isWeekday(input string) bool {
+	_, ok := weekdayAliases[strings.TrimSpace(input)]
+	return ok
+}
+
+// splitTimeOfDay splits a trailing time of day ("9am", "9:30 pm", "14:00",
+// optionally preceded by "at") from the day expression before it.
+func splitTimeOfDay(input string) (string, int, int, bool) {
+	m := timeOfDayRE.FindStringSubmatch(strings.ToLower(input))
+	if m == nil || (m[3] == "" && m[4] == "") {
+		return "", 0, 0, false
+	}
+
+	hour, _ := strconv.Atoi(m[2])
+	minute := 0
+	if m[3] != "" {
+		minute, _ = strconv.Atoi(m[3])
+	}
+	if minute > 59 {
+		return "", 0, 0, false
+	}
+
+	switch m[4] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return "", 0, 0, false
+		}
+		if hour == 12 {
+			hour = 0
+		}
+		if m[4] == "pm" {
+			hour += 12
+		}
+	default:
+		if hour > 23 {
+			return "", 0, 0, false
+		}
+	}
+
+	return strings.TrimSpace(m[1]), hour, minute, true
+}
+
 func startOfDay(t time.Time) time.Time {
 	year, month, day := t.Date()
 	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
@@ -166,4 +250,6 @@ var weekdayAliases = map[string]time.Weekday{
 	"saturday":  time.Saturday,
 }
 
+var timeOfDayRE = regexp.MustCompile(`^(?:(.*?)\s+)?(?:at\s+)?(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+
 var durationTokenRE = regexp.MustCompile(`^(\d+)(mo|w|d|h|m)$`)
@@ -151,3 +151,127 @@ func TestParseDateTime_Invalid(t *testing.T) {
 		t.Fatalf("expected error for invalid relative date")
 	}
 }
+
+func TestParseDateTime_TimeOfDay(t *testing.T) {
+	loc := time.FixedZone("Test", -5*60*60)
+	now := time.Date(2025, 1, 15, 10, 30, 0, 0, loc) // Wednesday
+
+	tests := []struct {
+		name string
+		in   string
+		want time.Time
+	}{
+		{
+			name: "weekday with am",
+			in:   "monday 9am",
+			want: time.Date(2025, 1, 20, 9, 0, 0, 0, loc),
+		},
+		{
+			name: "tomorrow at 24h time",
+			in:   "tomorrow at 17:30",
+			want: time.Date(2025, 1, 16, 17, 30, 0, 0, loc),
+		},
+		{
+			name: "bare time means today",
+			in:   "5:15pm",
+			want: time.Date(2025, 1, 15, 17, 15, 0, 0, loc),
+		},
+		{
+			name: "noon",
+			in:   "friday 12pm",
+			want: time.Date(2025, 1, 17, 12, 0, 0, 0, loc),
+		},
+		{
+			name: "midnight",
+			in:   "today 12am",
+			want: time.Date(2025, 1, 15, 0, 0, 0, 0, loc),
+		},
+		{
+			name: "date with time",
+			in:   "2025-02-01 08:00",
+			want: time.Date(2025, 2, 1, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDateTime(tt.in, now)
+			if err != nil {
+				t.Fatalf("ParseDateTime(%q) error = %v", tt.in, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("ParseDateTime(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []string{"monday 13pm", "tomorrow 25:00", "monday 9:75"} {
+		if _, err := ParseDateTime(bad, now); err == nil {
+			t.Errorf("ParseDateTime(%q) expected error", bad)
+		}
+	}
+}
+
+func TestParseFutureDateTime_RollsPastTimesForward(t *testing.T) {
+	loc := time.FixedZone("Test", -5*60*60)
+	now := time.Date(2025, 1, 15, 18, 0, 0, 0, loc) // Wednesday evening
+
+	tests := []struct {
+		name string
+		in   string
+		want time.Time
+	}{
+		{
+			name: "weekday earlier today means next week",
+			in:   "wednesday 9am",
+			want: time.Date(2025, 1, 22, 9, 0, 0, 0, loc),
+		},
+		{
+			name: "weekday alone today means next week",
+			in:   "wed",
+			want: time.Date(2025, 1, 22, 0, 0, 0, 0, loc),
+		},
+		{
+			name: "bare time already past means tomorrow",
+			in:   "5pm",
+			want: time.Date(2025, 1, 16, 17, 0, 0, 0, loc),
+		},
+		{
+			name: "bare time still ahead stays today",
+			in:   "9pm",
+			want: time.Date(2025, 1, 15, 21, 0, 0, 0, loc),
+		},
+		{
+			name: "later weekday unchanged",
+			in:   "friday 9am",
+			want: time.Date(2025, 1, 17, 9, 0, 0, 0, loc),
+		},
+		{
+			name: "explicit today kept",
+			in:   "today 5pm",
+			want: time.Date(2025, 1, 15, 17, 0, 0, 0, loc),
+		},
+		{
+			name: "explicit this weekday kept",
+			in:   "this wednesday 9am",
+			want: time.Date(2025, 1, 15, 9, 0, 0, 0, loc),
+		},
+		{
+			name: "past duration kept",
+			in:   "2h ago",
+			want: time.Date(2025, 1, 15, 16, 0, 0, 0, loc),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFutureDateTime(tt.in, now)
+			if err != nil {
+				t.Fatalf("ParseFutureDateTime(%q) error = %v", tt.in, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("ParseFutureDateTime(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
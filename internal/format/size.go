@@ -3,6 +3,8 @@ package format
 import (
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 )
 
 // FormatBytes converts bytes to human-readable format (KB, MB, GB, TB).
@@ -26,3 +28,39 @@ func FormatBytes(bytes int64) string {
 
 	return fmt.Sprintf("%.1f %s", value, units[exp])
 }
+
+// ParseBytes parses a human-readable size such as "500K", "1M", "1.5GB" or
+// "1024" into bytes. Units are binary (1K = 1024 bytes) to match FormatBytes.
+func ParseBytes(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	if value == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+
+	value = strings.TrimSuffix(value, "IB")
+	value = strings.TrimSuffix(value, "B")
+
+	multiplier := int64(1)
+	if n := len(value); n > 0 {
+		switch value[n-1] {
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		case 'T':
+			multiplier = 1 << 40
+		}
+		if multiplier > 1 {
+			value = value[:n-1]
+		}
+	}
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || number < 0 || math.IsInf(number, 0) || math.IsNaN(number) {
+		return 0, fmt.Errorf("invalid size %q (use bytes or a unit like 500K, 1M, 2G)", s)
+	}
+
+	return int64(number * float64(multiplier)), nil
+}
@@ -25,3 +25,36 @@ func TestFormatBytes(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBytes(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"1024", 1024, false},
+		{"1K", 1024, false},
+		{"500k", 500 * 1024, false},
+		{"1M", 1024 * 1024, false},
+		{"1MB", 1024 * 1024, false},
+		{"1MiB", 1024 * 1024, false},
+		{"1.5G", 1536 * 1024 * 1024, false},
+		{"2 TB", 2 * 1024 * 1024 * 1024 * 1024, false},
+		{"10B", 10, false},
+		{"", 0, true},
+		{"lots", 0, true},
+		{"-1M", 0, true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseBytes(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBytes(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseBytes(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package jmap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// AuditedCall is a write method call as the server performed it, kept for
+// the audit log. Update and Prior only cover the properties Revert can
+// restore (see undoableTypes).
+type AuditedCall struct {
+	Method    string   `json:"method"`
+	AccountID string   `json:"accountId,omitempty"`
+	Created   []string `json:"created,omitempty"`
+	Updated   []string `json:"updated,omitempty"`
+	Destroyed []string `json:"destroyed,omitempty"`
+	// Update is the patch applied to each updated object.
+	Update map[string]map[string]any `json:"update,omitempty"`
+	// Prior holds each updated object's values of the patched properties
+	// before the call.
+	Prior map[string]map[string]any `json:"prior,omitempty"`
+}
+
+// Auditor receives the write calls of each request the client completes.
+type Auditor func(ctx context.Context, calls []AuditedCall)
+
+// undoableType lists the properties of a data type whose previous values are
+// recorded before an update, and the capabilities needed to restore them.
+type undoableType struct {
+	using      []string
+	properties []string
+}
+
+var undoableTypes = map[string]undoableType{
+	"Email": {
+		using:      []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail"},
+		properties: []string{"mailboxIds", "keywords"},
+	},
+	"MaskedEmail": {
+		using:      []string{"urn:ietf:params:jmap:core", maskedEmailNamespace},
+		properties: []string{"state", "description"},
+	},
+}
+
+// SetAuditor makes the client pass every successful write request to
+// auditor. Before sending an update to emails or masked emails, the client
+// fetches the values it is about to change so the audit log can undo it.
+func (c *Client) SetAuditor(auditor Auditor) {
+	c.auditor = auditor
+}
+
+// makeAuditedRequest sends a write request and reports what it changed.
+func (c *Client) makeAuditedRequest(ctx context.Context, req *Request) (*Response, error) {
+	calls, ok := genericCalls(req)
+	if !ok {
+		return c.makeRequest(ctx, req)
+	}
+
+	prior := c.fetchPriorState(ctx, req.Using, calls)
+	resp, err := c.makeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if audited := auditCalls(calls, resp, prior); len(audited) > 0 {
+		c.auditor(ctx, audited)
+	}
+	return resp, nil
+}
+
+// genericCalls returns req's method calls with their arguments decoded into
+// plain maps, whatever Go types built them.
+func genericCalls(req *Request) ([]MethodCall, bool) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, false
+	}
+	parsed, ok := ParseRequestBody(body)
+	if !ok {
+		return nil, false
+	}
+	return parsed.MethodCalls, true
+}
+
+// undoableUpdate returns the data type of a /set call with an update to
+// restorable properties, and the properties it touches.
+func undoableUpdate(call MethodCall) (typeName string, update map[string]any, props []string) {
+	method, _ := call[0].(string)
+	typeName, isSet := strings.CutSuffix(method, "/set")
+	kind, known := undoableTypes[typeName]
+	if !isSet || !known {
+		return "", nil, nil
+	}
+	args, _ := call[1].(map[string]any)
+	update, _ = args["update"].(map[string]any)
+
+	seen := make(map[string]bool)
+	for _, patch := range update {
+		patchMap, _ := patch.(map[string]any)
+		for key := range patchMap {
+			seen[patchProperty(key)] = true
+		}
+	}
+	for _, prop := range kind.properties {
+		if seen[prop] {
+			props = append(props, prop)
+		}
+	}
+	return typeName, update, props
+}
+
+// patchProperty returns the property a patch key changes: "keywords" for
+// both "keywords" and "keywords/$seen".
+func patchProperty(key string) string {
+	prop, _, _ := strings.Cut(key, "/")
+	return prop
+}
+
+// fetchPriorState gets the current values of the properties calls are about
+// to update, keyed by call index and object ID. It is best-effort: on error
+// the write goes ahead without prior state.
+func (c *Client) fetchPriorState(ctx context.Context, using []string, calls []MethodCall) map[int]map[string]map[string]any {
+	req := &Request{Using: using}
+	callIndex := make(map[string]int)
+	for i, call := range calls {
+		typeName, update, props := undoableUpdate(call)
+		if len(props) == 0 {
+			continue
+		}
+		ids := make([]string, 0, len(update))
+		for id := range update {
+			if !strings.HasPrefix(id, "#") {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		sort.Strings(ids)
+
+		args, _ := call[1].(map[string]any)
+		callID := fmt.Sprintf("prior%d", i)
+		callIndex[callID] = i
+		req.MethodCalls = append(req.MethodCalls, MethodCall{typeName + "/get", map[string]any{
+			"accountId":  args["accountId"],
+			"ids":        ids,
+			"properties": append([]string{"id"}, props...),
+		}, callID})
+	}
+	if len(req.MethodCalls) == 0 {
+		return nil
+	}
+
+	resp, err := c.makeRequest(ctx, req)
+	if err != nil {
+		return nil
+	}
+
+	prior := make(map[int]map[string]map[string]any)
+	for _, mr := range resp.MethodResponses {
+		callID, _ := mr[2].(string)
+		i, ok := callIndex[callID]
+		result, isMap := mr[1].(map[string]any)
+		if !ok || !isMap {
+			continue
+		}
+		list, _ := result["list"].([]any)
+		for _, item := range list {
+			obj, _ := item.(map[string]any)
+			id, _ := obj["id"].(string)
+			if id == "" {
+				continue
+			}
+			delete(obj, "id")
+			if prior[i] == nil {
+				prior[i] = make(map[string]map[string]any)
+			}
+			prior[i][id] = obj
+		}
+	}
+	return prior
+}
+
+// auditCalls pairs each write call with its response and records what the
+// server reports it created, updated and destroyed.
+func auditCalls(calls []MethodCall, resp *Response, prior map[int]map[string]map[string]any) []AuditedCall {
+	results := make(map[string]map[string]any, len(resp.MethodResponses))
+	for _, mr := range resp.MethodResponses {
+		name, _ := mr[0].(string)
+		callID, _ := mr[2].(string)
+		if result, ok := mr[1].(map[string]any); ok && name != "error" {
+			results[callID] = result
+		}
+	}
+
+	var audited []AuditedCall
+	for i, call := range calls {
+		method, _ := call[0].(string)
+		callID, _ := call[2].(string)
+		result, ok := results[callID]
+		if !ok || RequiredScope(&Request{MethodCalls: []MethodCall{call}}) == ScopeRead {
+			continue
+		}
+
+		args, _ := call[1].(map[string]any)
+		ac := AuditedCall{Method: method}
+		ac.AccountID, _ = args["accountId"].(string)
+		if created, ok := result["created"].(map[string]any); ok {
+			for cid, obj := range created {
+				id := cid
+				if objMap, ok := obj.(map[string]any); ok {
+					if serverID, ok := objMap["id"].(string); ok {
+						id = serverID
+					}
+				}
+				ac.Created = append(ac.Created, id)
+			}
+		}
+		if updated, ok := result["updated"].(map[string]any); ok {
+			for id := range updated {
+				ac.Updated = append(ac.Updated, id)
+			}
+		}
+		if destroyed, ok := result["destroyed"].([]any); ok {
+			for _, id := range destroyed {
+				if s, ok := id.(string); ok {
+					ac.Destroyed = append(ac.Destroyed, s)
+				}
+			}
+		}
+		if len(ac.Created)+len(ac.Updated)+len(ac.Destroyed) == 0 {
+			continue
+		}
+		sort.Strings(ac.Created)
+		sort.Strings(ac.Updated)
+		sort.Strings(ac.Destroyed)
+
+		if _, update, props := undoableUpdate(call); len(props) > 0 {
+			ac.Update = make(map[string]map[string]any)
+			for _, id := range ac.Updated {
+				patch, _ := update[id].(map[string]any)
+				kept := make(map[string]any)
+				for key, value := range patch {
+					if slices.Contains(props, patchProperty(key)) {
+						kept[key] = value
+					}
+				}
+				if len(kept) > 0 {
+					ac.Update[id] = kept
+				}
+			}
+			ac.Prior = prior[i]
+		}
+		audited = append(audited, ac)
+	}
+	return audited
+}
+
+// Undoable reports whether Revert can restore anything call changed.
+func (call AuditedCall) Undoable() bool {
+	return len(call.Revert()) > 0
+}
+
+// Revert returns the patches, by object ID, that put the properties call
+// updated back to their recorded prior values. Objects without recorded
+// prior state are left out; creations and destructions cannot be reverted.
+func (call AuditedCall) Revert() map[string]any {
+	revert := make(map[string]any)
+	for id, patch := range call.Update {
+		prior, ok := call.Prior[id]
+		if !ok {
+			continue
+		}
+		inverse := make(map[string]any, len(patch))
+		for key := range patch {
+			prop, sub, isPath := strings.Cut(key, "/")
+			if !isPath {
+				inverse[key] = prior[prop]
+				continue
+			}
+			// Restore a single entry such as "keywords/$seen": its old
+			// value, or null if it was not set.
+			values, _ := prior[prop].(map[string]any)
+			inverse[key] = values[unescapePointer(sub)]
+		}
+		revert[id] = inverse
+	}
+	return revert
+}
+
+// unescapePointer decodes a JSON Pointer reference token (RFC 6901).
+func unescapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
+
+// RevertCalls restores the prior state recorded in calls, latest call first.
+// Succeeded lists the object IDs restored; Failed maps the others to the
+// server's error.
+func (c *Client) RevertCalls(ctx context.Context, calls []AuditedCall) (*BulkResult, error) {
+	session, err := c.GetSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &Request{}
+	usingSeen := make(map[string]bool)
+	for i := len(calls) - 1; i >= 0; i-- {
+		call := calls[i]
+		revert := call.Revert()
+		if len(revert) == 0 {
+			continue
+		}
+		typeName := strings.TrimSuffix(call.Method, "/set")
+		kind := undoableTypes[typeName]
+		for _, capability := range kind.using {
+			if !usingSeen[capability] {
+				usingSeen[capability] = true
+				req.Using = append(req.Using, capability)
+			}
+		}
+		accountID := call.AccountID
+		if accountID == "" {
+			accountID = session.AccountFor(kind.using[len(kind.using)-1])
+		}
+		req.MethodCalls = append(req.MethodCalls, MethodCall{call.Method, map[string]any{
+			"accountId": accountID,
+			"update":    revert,
+		}, fmt.Sprintf("undo%d", i)})
+	}
+	if len(req.MethodCalls) == 0 {
+		return nil, fmt.Errorf("nothing to undo")
+	}
+
+	resp, err := c.MakeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkResult{Succeeded: []string{}, Failed: make(map[string]string)}
+	for i := range resp.MethodResponses {
+		setResult, err := decodeMethodResponse[map[string]any](resp, i)
+		if err != nil {
+			return nil, err
+		}
+		succeeded, failed := parseBulkUpdateResult(setResult)
+		result.Succeeded = append(result.Succeeded, succeeded...)
+		for id, msg := range failed {
+			result.Failed[id] = msg
+		}
+	}
+	sort.Strings(result.Succeeded)
+	return result, nil
+}
@@ -0,0 +1,174 @@
+package jmap
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_AuditRecordsPriorState(t *testing.T) {
+	var apiURL string
+	var bodies []Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"apiUrl": "` + apiURL + `", "accounts": {"acc1": {"name": "me"}}, "primaryAccounts": {"urn:ietf:params:jmap:mail": "acc1"}}`))
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		var req Request
+		_ = json.Unmarshal(data, &req)
+		bodies = append(bodies, req)
+
+		switch req.MethodCalls[0][0] {
+		case "Email/get":
+			_, _ = w.Write([]byte(`{"methodResponses": [["Email/get", {"list": [
+				{"id": "e1", "mailboxIds": {"inbox": true}, "keywords": {"$flagged": true}},
+				{"id": "e2", "mailboxIds": {"inbox": true, "work": true}, "keywords": {"$seen": true}}
+			]}, "prior0"]], "sessionState": ""}`))
+		default:
+			_, _ = w.Write([]byte(`{"methodResponses": [["Email/set", {"updated": {"e1": null, "e2": null}, "notUpdated": {"e3": {"type": "notFound"}}}, "0"]], "sessionState": ""}`))
+		}
+	}))
+	defer server.Close()
+	apiURL = server.URL + "/api"
+
+	var audited []AuditedCall
+	client := NewClientWithBaseURL("token", server.URL)
+	client.SetAuditor(func(_ context.Context, calls []AuditedCall) {
+		audited = calls
+	})
+
+	update := map[string]any{
+		"e1": map[string]any{"mailboxIds": map[string]bool{"archive": true}, "keywords/$seen": true},
+		"e2": map[string]any{"mailboxIds": map[string]bool{"archive": true}, "keywords/$seen": true},
+		"e3": map[string]any{"mailboxIds": map[string]bool{"archive": true}},
+	}
+	_, err := client.MakeRequest(context.Background(), &Request{
+		Using:       []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail"},
+		MethodCalls: []MethodCall{{"Email/set", map[string]any{"accountId": "acc1", "update": update}, "0"}},
+	})
+	if err != nil {
+		t.Fatalf("MakeRequest: %v", err)
+	}
+
+	if len(bodies) != 2 || bodies[0].MethodCalls[0][0] != "Email/get" {
+		t.Fatalf("requests = %+v, want Email/get before Email/set", bodies)
+	}
+	if len(audited) != 1 {
+		t.Fatalf("audited = %+v, want one call", audited)
+	}
+	call := audited[0]
+	if call.Method != "Email/set" || call.AccountID != "acc1" || !reflect.DeepEqual(call.Updated, []string{"e1", "e2"}) {
+		t.Errorf("audited call = %+v", call)
+	}
+
+	want := map[string]any{
+		"e1": map[string]any{"mailboxIds": map[string]any{"inbox": true}, "keywords/$seen": nil},
+		"e2": map[string]any{"mailboxIds": map[string]any{"inbox": true, "work": true}, "keywords/$seen": true},
+	}
+	if got := call.Revert(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Revert() = %#v\nwant %#v", got, want)
+	}
+}
+
+func TestClient_AuditSkipsReadsAndFailures(t *testing.T) {
+	var apiURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"apiUrl": "` + apiURL + `", "accounts": {"acc1": {"name": "me"}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"methodResponses": [["error", {"type": "invalidArguments"}, "0"]], "sessionState": ""}`))
+	}))
+	defer server.Close()
+	apiURL = server.URL + "/api"
+
+	calls := 0
+	client := NewClientWithBaseURL("token", server.URL)
+	client.SetAuditor(func(context.Context, []AuditedCall) { calls++ })
+
+	ctx := context.Background()
+	_, _ = client.MakeRequest(ctx, &Request{MethodCalls: []MethodCall{{"Email/get", map[string]any{}, "0"}}})
+	_, _ = client.MakeRequest(ctx, &Request{MethodCalls: []MethodCall{{"Mailbox/set", map[string]any{"destroy": []string{"m1"}}, "0"}}})
+	if calls != 0 {
+		t.Errorf("auditor called %d times, want none for reads and failed writes", calls)
+	}
+}
+
+func TestAuditedCall_RevertMaskedState(t *testing.T) {
+	call := AuditedCall{
+		Method:  "MaskedEmail/set",
+		Updated: []string{"m1", "m2"},
+		Update: map[string]map[string]any{
+			"m1": {"state": "disabled"},
+			"m2": {"state": "disabled"},
+		},
+		Prior: map[string]map[string]any{
+			"m1": {"state": "enabled"},
+		},
+	}
+	want := map[string]any{"m1": map[string]any{"state": "enabled"}}
+	if got := call.Revert(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Revert() = %#v, want %#v", got, want)
+	}
+
+	created := AuditedCall{Method: "Email/import", Created: []string{"e9"}}
+	if created.Undoable() {
+		t.Error("an import should not be undoable")
+	}
+}
+
+func TestClient_RevertCalls(t *testing.T) {
+	var apiURL string
+	var sent Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"apiUrl": "` + apiURL + `", "accounts": {"acc1": {"name": "me"}}}`))
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &sent)
+		_, _ = w.Write([]byte(`{"methodResponses": [
+			["MaskedEmail/set", {"updated": {"m1": null}}, "undo1"],
+			["Email/set", {"updated": {"e1": null}, "notUpdated": {"e2": {"type": "notFound"}}}, "undo0"]
+		], "sessionState": ""}`))
+	}))
+	defer server.Close()
+	apiURL = server.URL + "/api"
+
+	calls := []AuditedCall{
+		{
+			Method:    "Email/set",
+			AccountID: "acc1",
+			Updated:   []string{"e1", "e2"},
+			Update:    map[string]map[string]any{"e1": {"keywords/$seen": true}, "e2": {"keywords/$seen": true}},
+			Prior:     map[string]map[string]any{"e1": {"keywords": map[string]any{}}, "e2": {"keywords": map[string]any{}}},
+		},
+		{
+			Method:    "MaskedEmail/set",
+			AccountID: "acc1",
+			Updated:   []string{"m1"},
+			Update:    map[string]map[string]any{"m1": {"state": "disabled"}},
+			Prior:     map[string]map[string]any{"m1": {"state": "enabled"}},
+		},
+	}
+
+	client := NewClientWithBaseURL("token", server.URL)
+	result, err := client.RevertCalls(context.Background(), calls)
+	if err != nil {
+		t.Fatalf("RevertCalls: %v", err)
+	}
+	if len(sent.MethodCalls) != 2 || sent.MethodCalls[0][0] != "MaskedEmail/set" || sent.MethodCalls[1][0] != "Email/set" {
+		t.Fatalf("sent = %+v, want the later call reverted first", sent.MethodCalls)
+	}
+	if !reflect.DeepEqual(sent.Using, []string{"urn:ietf:params:jmap:core", maskedEmailNamespace, "urn:ietf:params:jmap:mail"}) {
+		t.Errorf("using = %v", sent.Using)
+	}
+	if !reflect.DeepEqual(result.Succeeded, []string{"e1", "m1"}) || result.Failed["e2"] == "" {
+		t.Errorf("result = %+v", result)
+	}
+}
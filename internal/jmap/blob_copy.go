@@ -0,0 +1,75 @@
+package jmap
+
+import (
+	"context"
+	"fmt"
+)
+
+// CopyBlobs copies blobs from the session's account into toAccountID with
+// Blob/copy (RFC 8620 section 6.3), without downloading them. Both accounts
+// must be accessible to the same session. It returns a map from each source
+// blob ID to its new ID, and the reason for each blob that was not copied.
+func (c *Client) CopyBlobs(ctx context.Context, toAccountID string, blobIDs []string) (map[string]string, map[string]string, error) {
+	if len(blobIDs) == 0 {
+		return map[string]string{}, map[string]string{}, nil
+	}
+
+	session, err := c.GetSession(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !session.HasAccount(toAccountID) {
+		return nil, nil, fmt.Errorf("account %s is not available in this session", toAccountID)
+	}
+
+	req := &Request{
+		Using: []string{"urn:ietf:params:jmap:core"},
+		MethodCalls: []MethodCall{
+			{"Blob/copy", map[string]any{
+				"fromAccountId": session.AccountID,
+				"accountId":     toAccountID,
+				"blobIds":       blobIDs,
+			}, "copy"},
+		},
+	}
+
+	resp, err := c.MakeRequest(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := decodeMethodResponse[map[string]any](resp, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	copied := make(map[string]string)
+	if m, ok := result["copied"].(map[string]any); ok {
+		for from, to := range m {
+			if id, ok := to.(string); ok {
+				copied[from] = id
+			}
+		}
+	}
+
+	notCopied := make(map[string]string)
+	if m, ok := result["notCopied"].(map[string]any); ok {
+		for id, errInfo := range m {
+			errMsg := "unknown error"
+			if errMap, ok := errInfo.(map[string]any); ok {
+				errType := getString(errMap, "type")
+				errDesc := getString(errMap, "description")
+				if errType != "" && errDesc != "" {
+					errMsg = errType + ": " + errDesc
+				} else if errType != "" {
+					errMsg = errType
+				} else if errDesc != "" {
+					errMsg = errDesc
+				}
+			}
+			notCopied[id] = errMsg
+		}
+	}
+
+	return copied, notCopied, nil
+}
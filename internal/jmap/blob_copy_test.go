@@ -0,0 +1,48 @@
+package jmap
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCopyBlobs(t *testing.T) {
+	var args map[string]any
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		args = req.MethodCalls[0][1].(map[string]any)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"methodResponses": [["Blob/copy", {
+			"copied": {"B1": "B1-copy"},
+			"notCopied": {"B2": {"type": "blobNotFound"}}
+		}, "copy"]]}`))
+	}))
+	defer apiServer.Close()
+
+	sessionServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"apiUrl": "` + apiServer.URL + `", "accounts": {"acc1": {"name": "me"}, "acc2": {"name": "shared"}}}`))
+	}))
+	defer sessionServer.Close()
+
+	client := NewClientWithBaseURL("test-token", sessionServer.URL)
+
+	copied, notCopied, err := client.CopyBlobs(context.Background(), "acc2", []string{"B1", "B2"})
+	if err != nil {
+		t.Fatalf("CopyBlobs() error: %v", err)
+	}
+	if args["fromAccountId"] != "acc1" || args["accountId"] != "acc2" {
+		t.Errorf("unexpected accounts in request: %v", args)
+	}
+	if copied["B1"] != "B1-copy" || notCopied["B2"] != "blobNotFound" {
+		t.Errorf("copied = %v, notCopied = %v", copied, notCopied)
+	}
+
+	if _, _, err := client.CopyBlobs(context.Background(), "other", []string{"B1"}); err == nil {
+		t.Error("expected error for an account outside the session")
+	}
+}
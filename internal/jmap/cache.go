@@ -0,0 +1,207 @@
+package jmap
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Cache persists data across Client instances, so short-lived processes can
+// skip the session fetch and revalidate mailboxes cheaply. *cache.Store
+// implements it.
+type Cache interface {
+	Load(key string, v any) bool
+	Save(key string, v any) error
+	Delete(key string)
+}
+
+const (
+	sessionCacheKey      = "session"
+	mailboxCacheKeyStart = "mailboxes-"
+)
+
+// cachedSession is the on-disk form of the session resource.
+type cachedSession struct {
+	TokenHash  string          `json:"tokenHash"`
+	SessionURL string          `json:"sessionUrl,omitempty"`
+	FetchedAt  time.Time       `json:"fetchedAt"`
+	Session    sessionResource `json:"session"`
+}
+
+// cachedMailboxes is the on-disk mailbox list of one account at a state.
+type cachedMailboxes struct {
+	TokenHash string    `json:"tokenHash"`
+	AccountID string    `json:"accountId"`
+	State     string    `json:"state"`
+	Mailboxes []Mailbox `json:"mailboxes"`
+}
+
+// SetCache enables persistent caching of the session and mailbox list.
+func (c *Client) SetCache(cache Cache) {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	c.cache = cache
+}
+
+// tokenHash identifies the token that produced a cache entry without storing
+// the token itself, so entries from a replaced token are ignored.
+func (c *Client) tokenHash() string {
+	token, _ := c.tokenFor(ScopeRead) //nolint:errcheck // an empty token hashes consistently
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// loadCachedSession returns the cached session if it is still within the
+// session TTL. The caller must hold sessionMu.
+func (c *Client) loadCachedSession() (*Session, time.Time, bool) {
+	if c.cache == nil {
+		return nil, time.Time{}, false
+	}
+
+	var entry cachedSession
+	if !c.cache.Load(sessionCacheKey, &entry) || entry.TokenHash != c.tokenHash() || entry.SessionURL != c.sessionURL() {
+		return nil, time.Time{}, false
+	}
+	if time.Since(entry.FetchedAt) >= c.sessionTTL || entry.Session.APIUrl == "" {
+		return nil, time.Time{}, false
+	}
+
+	session, err := c.buildSession(entry.Session)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	return session, entry.FetchedAt, true
+}
+
+// saveCachedSession stores the session resource. The caller must hold
+// sessionMu.
+func (c *Client) saveCachedSession(data sessionResource, fetched time.Time) {
+	if c.cache == nil {
+		return
+	}
+	_ = c.cache.Save(sessionCacheKey, cachedSession{
+		TokenHash:  c.tokenHash(),
+		SessionURL: c.sessionURL(),
+		FetchedAt:  fetched,
+		Session:    data,
+	})
+}
+
+// invalidateSession drops the in-memory and cached session, e.g. after a
+// response reports a new session state.
+func (c *Client) invalidateSession() {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	c.session = nil
+	if c.cache != nil {
+		c.cache.Delete(sessionCacheKey)
+	}
+}
+
+// cachedMailboxList revalidates the cached mailboxes of the session's account
+// with Mailbox/changes, fetching only created and updated mailboxes in the
+// same request. ok is false when there is no usable cache entry or the server
+// cannot calculate changes; the caller should then fetch everything.
+func (c *Client) cachedMailboxList(ctx context.Context, session *Session) ([]Mailbox, bool) {
+	if c.cache == nil {
+		return nil, false
+	}
+
+	key := mailboxCacheKeyStart + session.AccountID
+	var entry cachedMailboxes
+	if !c.cache.Load(key, &entry) || entry.TokenHash != c.tokenHash() || entry.AccountID != session.AccountID || entry.State == "" {
+		return nil, false
+	}
+
+	ref := func(path string) map[string]any {
+		return map[string]any{"resultOf": "changes", "name": "Mailbox/changes", "path": path}
+	}
+	req := &Request{
+		Using: []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail"},
+		MethodCalls: []MethodCall{
+			{"Mailbox/changes", map[string]any{
+				"accountId":  session.AccountID,
+				"sinceState": entry.State,
+			}, "changes"},
+			{"Mailbox/get", map[string]any{
+				"accountId": session.AccountID,
+				"#ids":      ref("/created"),
+			}, "created"},
+			{"Mailbox/get", map[string]any{
+				"accountId": session.AccountID,
+				"#ids":      ref("/updated"),
+			}, "updated"},
+		},
+	}
+
+	resp, err := c.MakeRequest(ctx, req)
+	if err != nil {
+		return nil, false
+	}
+
+	changes, err := decodeMethodResponse[struct {
+		NewState       string   `json:"newState"`
+		HasMoreChanges bool     `json:"hasMoreChanges"`
+		Destroyed      []string `json:"destroyed"`
+	}](resp, 0)
+	if err != nil || changes.HasMoreChanges || changes.NewState == "" {
+		c.cache.Delete(key)
+		return nil, false
+	}
+
+	var lists [2][]Mailbox
+	for i := range lists {
+		result, err := decodeMethodResponse[map[string]any](resp, i+1)
+		if err != nil {
+			c.cache.Delete(key)
+			return nil, false
+		}
+		list, _ := result["list"].([]any)
+		for _, item := range list {
+			if mb, ok := item.(map[string]any); ok {
+				lists[i] = append(lists[i], parseMailbox(mb))
+			}
+		}
+	}
+	created, updated := lists[0], lists[1]
+
+	drop := make(map[string]bool, len(changes.Destroyed))
+	for _, id := range changes.Destroyed {
+		drop[id] = true
+	}
+	replace := make(map[string]Mailbox, len(updated))
+	for _, mb := range updated {
+		replace[mb.ID] = mb
+	}
+
+	mailboxes := make([]Mailbox, 0, len(entry.Mailboxes)+len(created))
+	for _, mb := range entry.Mailboxes {
+		if drop[mb.ID] {
+			continue
+		}
+		if u, ok := replace[mb.ID]; ok {
+			mb = u
+		}
+		mailboxes = append(mailboxes, mb)
+	}
+	mailboxes = append(mailboxes, created...)
+
+	if changes.NewState != entry.State {
+		c.saveMailboxes(session, changes.NewState, mailboxes)
+	}
+	return mailboxes, true
+}
+
+// saveMailboxes stores the mailbox list of the session's account at state.
+func (c *Client) saveMailboxes(session *Session, state string, mailboxes []Mailbox) {
+	if c.cache == nil || state == "" {
+		return
+	}
+	_ = c.cache.Save(mailboxCacheKeyStart+session.AccountID, cachedMailboxes{
+		TokenHash: c.tokenHash(),
+		AccountID: session.AccountID,
+		State:     state,
+		Mailboxes: mailboxes,
+	})
+}
@@ -0,0 +1,250 @@
+package jmap
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// memCache is an in-memory Cache for tests.
+type memCache struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+func newMemCache() *memCache {
+	return &memCache{entries: map[string][]byte{}}
+}
+
+func (m *memCache) Load(key string, v any) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.entries[key]
+	return ok && json.Unmarshal(data, v) == nil
+}
+
+func (m *memCache) Save(key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = data
+	return nil
+}
+
+func (m *memCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
+
+// cacheTestServer serves a session and records the JMAP method names of each
+// API request. respond builds the methodResponses for a request.
+type cacheTestServer struct {
+	sessionURL    string
+	sessionHits   atomic.Int32
+	mu            sync.Mutex
+	requests      [][]string
+	responseState string
+}
+
+func newCacheTestServer(t *testing.T, respond func(calls [][3]json.RawMessage) []any) *cacheTestServer {
+	t.Helper()
+	s := &cacheTestServer{}
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			MethodCalls [][3]json.RawMessage `json:"methodCalls"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var names []string
+		for _, call := range req.MethodCalls {
+			var name string
+			_ = json.Unmarshal(call[0], &name)
+			names = append(names, name)
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, names)
+		state := s.responseState
+		s.mu.Unlock()
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"methodResponses": respond(req.MethodCalls),
+			"sessionState":    state,
+		})
+	}))
+	t.Cleanup(api.Close)
+
+	session := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.sessionHits.Add(1)
+		_, _ = w.Write([]byte(`{"apiUrl": "` + api.URL + `", "state": "sess1", "accounts": {"acc1": {"name": "me"}}}`))
+	}))
+	t.Cleanup(session.Close)
+
+	s.sessionURL = session.URL
+	s.responseState = "sess1"
+	return s
+}
+
+func (s *cacheTestServer) methods() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.requests...)
+}
+
+func TestSessionCache_ReusedAcrossClients(t *testing.T) {
+	server := newCacheTestServer(t, func([][3]json.RawMessage) []any { return []any{} })
+	cache := newMemCache()
+
+	first := NewClientWithBaseURL("test-token", server.sessionURL)
+	first.SetCache(cache)
+	if _, err := first.GetSession(context.Background()); err != nil {
+		t.Fatalf("GetSession() error: %v", err)
+	}
+
+	second := NewClientWithBaseURL("test-token", server.sessionURL)
+	second.SetCache(cache)
+	session, err := second.GetSession(context.Background())
+	if err != nil {
+		t.Fatalf("GetSession() error: %v", err)
+	}
+	if session.AccountID != "acc1" || session.State != "sess1" {
+		t.Errorf("cached session = %+v", session)
+	}
+	if hits := server.sessionHits.Load(); hits != 1 {
+		t.Errorf("session fetched %d times, want 1", hits)
+	}
+
+	other := NewClientWithBaseURL("other-token", server.sessionURL)
+	other.SetCache(cache)
+	if _, err := other.GetSession(context.Background()); err != nil {
+		t.Fatalf("GetSession() error: %v", err)
+	}
+	if hits := server.sessionHits.Load(); hits != 2 {
+		t.Errorf("a different token must not reuse the cached session (fetches = %d)", hits)
+	}
+}
+
+func TestSessionCache_InvalidatedByNewSessionState(t *testing.T) {
+	server := newCacheTestServer(t, func([][3]json.RawMessage) []any {
+		return []any{[]any{"Core/echo", map[string]any{}, "c0"}}
+	})
+	cache := newMemCache()
+
+	client := NewClientWithBaseURL("test-token", server.sessionURL)
+	client.SetCache(cache)
+
+	server.mu.Lock()
+	server.responseState = "sess2"
+	server.mu.Unlock()
+
+	if _, err := client.MakeRequest(context.Background(), &Request{MethodCalls: []MethodCall{{"Core/echo", map[string]any{}, "c0"}}}); err != nil {
+		t.Fatalf("MakeRequest() error: %v", err)
+	}
+
+	var entry cachedSession
+	if cache.Load(sessionCacheKey, &entry) {
+		t.Error("cached session should be dropped when sessionState changes")
+	}
+	if _, err := client.GetSession(context.Background()); err != nil {
+		t.Fatalf("GetSession() error: %v", err)
+	}
+	if hits := server.sessionHits.Load(); hits != 2 {
+		t.Errorf("session fetched %d times, want refetch after state change", hits)
+	}
+}
+
+func TestMailboxCache_RevalidatesWithChanges(t *testing.T) {
+	var changesResponse []any
+	server := newCacheTestServer(t, func(calls [][3]json.RawMessage) []any {
+		var name string
+		_ = json.Unmarshal(calls[0][0], &name)
+		if name == "Mailbox/get" {
+			return []any{[]any{"Mailbox/get", map[string]any{
+				"state": "m1",
+				"list": []any{
+					map[string]any{"id": "inbox", "name": "Inbox", "role": "inbox", "unreadEmails": 1},
+					map[string]any{"id": "old", "name": "Old"},
+				},
+			}, "mailboxes"}}
+		}
+		return changesResponse
+	})
+	cache := newMemCache()
+
+	first := NewClientWithBaseURL("test-token", server.sessionURL)
+	first.SetCache(cache)
+	if _, err := first.GetMailboxes(context.Background()); err != nil {
+		t.Fatalf("GetMailboxes() error: %v", err)
+	}
+
+	changesResponse = []any{
+		[]any{"Mailbox/changes", map[string]any{"oldState": "m1", "newState": "m2", "destroyed": []any{"old"}}, "changes"},
+		[]any{"Mailbox/get", map[string]any{"list": []any{map[string]any{"id": "new", "name": "New"}}}, "created"},
+		[]any{"Mailbox/get", map[string]any{"list": []any{map[string]any{"id": "inbox", "name": "Inbox", "role": "inbox", "unreadEmails": 5}}}, "updated"},
+	}
+
+	second := NewClientWithBaseURL("test-token", server.sessionURL)
+	second.SetCache(cache)
+	mailboxes, err := second.GetMailboxes(context.Background())
+	if err != nil {
+		t.Fatalf("GetMailboxes() error: %v", err)
+	}
+
+	if len(mailboxes) != 2 || mailboxes[0].ID != "inbox" || mailboxes[0].UnreadEmails != 5 || mailboxes[1].ID != "new" {
+		t.Errorf("mailboxes = %+v", mailboxes)
+	}
+
+	requests := server.methods()
+	last := requests[len(requests)-1]
+	if len(last) != 3 || last[0] != "Mailbox/changes" {
+		t.Errorf("cached lookup should send Mailbox/changes, sent %v", last)
+	}
+
+	var entry cachedMailboxes
+	if !cache.Load(mailboxCacheKeyStart+"acc1", &entry) || entry.State != "m2" || len(entry.Mailboxes) != 2 {
+		t.Errorf("cache entry not updated: %+v", entry)
+	}
+}
+
+func TestMailboxCache_FallsBackWhenChangesUnavailable(t *testing.T) {
+	server := newCacheTestServer(t, func(calls [][3]json.RawMessage) []any {
+		var name string
+		_ = json.Unmarshal(calls[0][0], &name)
+		if name == "Mailbox/changes" {
+			return []any{[]any{"error", map[string]any{"type": "cannotCalculateChanges"}, "changes"}}
+		}
+		return []any{[]any{"Mailbox/get", map[string]any{
+			"state": "m9",
+			"list":  []any{map[string]any{"id": "inbox", "name": "Inbox"}},
+		}, "mailboxes"}}
+	})
+	cache := newMemCache()
+	_ = cache.Save(mailboxCacheKeyStart+"acc1", cachedMailboxes{
+		TokenHash: NewClientWithBaseURL("test-token", "").tokenHash(),
+		AccountID: "acc1",
+		State:     "ancient",
+		Mailboxes: []Mailbox{{ID: "gone", Name: "Gone"}},
+	})
+
+	client := NewClientWithBaseURL("test-token", server.sessionURL)
+	client.SetCache(cache)
+	mailboxes, err := client.GetMailboxes(context.Background())
+	if err != nil {
+		t.Fatalf("GetMailboxes() error: %v", err)
+	}
+	if len(mailboxes) != 1 || mailboxes[0].ID != "inbox" {
+		t.Errorf("mailboxes = %+v, want full refetch", mailboxes)
+	}
+
+	var entry cachedMailboxes
+	if !cache.Load(mailboxCacheKeyStart+"acc1", &entry) || entry.State != "m9" {
+		t.Errorf("cache should hold the refetched state, got %+v", entry)
+	}
+}
@@ -92,7 +92,7 @@ func (c *Client) GetCalendars(ctx context.Context) ([]Calendar, error) {
 		Using: []string{"urn:ietf:params:jmap:core", calendarsCapability},
 		MethodCalls: []MethodCall{
 			{"Calendar/get", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(calendarsCapability),
 			}, "0"},
 		},
 	}
@@ -148,12 +148,12 @@ func (c *Client) GetEvents(ctx context.Context, calendarID string, from, to time
 		Using: []string{"urn:ietf:params:jmap:core", calendarsCapability},
 		MethodCalls: []MethodCall{
 			{"CalendarEvent/query", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(calendarsCapability),
 				"filter":    filter,
 				"limit":     limit,
 			}, "0"},
 			{"CalendarEvent/get", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(calendarsCapability),
 				"#ids": map[string]any{
 					"resultOf": "0",
 					"name":     "CalendarEvent/query",
@@ -198,7 +198,7 @@ func (c *Client) GetEventByID(ctx context.Context, id string) (*CalendarEvent, e
 		Using: []string{"urn:ietf:params:jmap:core", calendarsCapability},
 		MethodCalls: []MethodCall{
 			{"CalendarEvent/get", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(calendarsCapability),
 				"ids":       []string{id},
 			}, "0"},
 		},
@@ -240,7 +240,7 @@ func (c *Client) CreateEvent(ctx context.Context, event *CalendarEvent) (*Calend
 		Using: []string{"urn:ietf:params:jmap:core", calendarsCapability},
 		MethodCalls: []MethodCall{
 			{"CalendarEvent/set", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(calendarsCapability),
 				"create": map[string]any{
 					"new-event": event,
 				},
@@ -285,7 +285,7 @@ func (c *Client) UpdateEvent(ctx context.Context, id string, updates map[string]
 		Using: []string{"urn:ietf:params:jmap:core", calendarsCapability},
 		MethodCalls: []MethodCall{
 			{"CalendarEvent/set", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(calendarsCapability),
 				"update": map[string]any{
 					id: updates,
 				},
@@ -330,7 +330,7 @@ func (c *Client) DeleteEvent(ctx context.Context, id string) error {
 		Using: []string{"urn:ietf:params:jmap:core", calendarsCapability},
 		MethodCalls: []MethodCall{
 			{"CalendarEvent/set", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(calendarsCapability),
 				"destroy":   []string{id},
 			}, "0"},
 		},
@@ -4,11 +4,11 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"sort"
 	"strings"
 	"sync"
@@ -25,12 +25,17 @@ const (
 	// SessionPath is the path to the JMAP session endpoint
 	SessionPath = "/jmap/session"
 
+	// WellKnownPath is the standard path servers redirect to their session
+	// resource (RFC 8620, section 2.2).
+	WellKnownPath = "/.well-known/jmap"
+
 	// Default retry configuration values (shared with transport)
 	DefaultMaxRetries   = transport.DefaultMaxRetries
 	DefaultInitialDelay = transport.DefaultInitialDelay
 	DefaultMaxDelay     = transport.DefaultMaxDelay
 
-	// MaxUploadSize is the maximum size for blob uploads (50MB)
+	// MaxUploadSize is the blob upload limit (50MB) used when the server does
+	// not advertise maxSizeUpload
 	MaxUploadSize = 50 * 1024 * 1024
 
 	// Default circuit breaker configuration values
@@ -38,6 +43,19 @@ const (
 	DefaultCircuitBreakerResetAfter = 30 * time.Second
 )
 
+// Capability URIs for the data types the CLI uses.
+const (
+	CapabilityCore             = "urn:ietf:params:jmap:core"
+	CapabilityMail             = "urn:ietf:params:jmap:mail"
+	CapabilitySubmission       = "urn:ietf:params:jmap:submission"
+	CapabilityVacationResponse = "urn:ietf:params:jmap:vacationresponse"
+	CapabilityQuota            = "urn:ietf:params:jmap:quota"
+	CapabilityBlob             = "urn:ietf:params:jmap:blob"
+	CapabilityContacts         = contactsCapability
+	CapabilityCalendars        = calendarsCapability
+	CapabilityMaskedEmail      = maskedEmailNamespace
+)
+
 // RetryConfig configures retry behavior for JMAP requests.
 type RetryConfig = transport.RetryConfig
 
@@ -96,11 +114,97 @@ func (cb *circuitBreaker) recordFailure() {
 
 // Session represents a JMAP session with API endpoints and account information
 type Session struct {
-	APIUrl       string         `json:"apiUrl"`
-	AccountID    string         `json:"accountId"`
-	Capabilities map[string]any `json:"capabilities"`
-	DownloadURL  string         `json:"downloadUrl"`
-	UploadURL    string         `json:"uploadUrl"`
+	APIUrl          string                    `json:"apiUrl"`
+	AccountID       string                    `json:"accountId"`
+	Accounts        map[string]SessionAccount `json:"accounts"`
+	PrimaryAccounts map[string]string         `json:"primaryAccounts"`
+	Capabilities    map[string]any            `json:"capabilities"`
+	DownloadURL     string                    `json:"downloadUrl"`
+	UploadURL       string                    `json:"uploadUrl"`
+	Username        string                    `json:"username"`
+
+	// State changes whenever the session object changes; responses carry it
+	// as sessionState.
+	State string `json:"state"`
+
+	// Limits are the server limits from the core capability.
+	Limits CoreLimits `json:"limits"`
+
+	// explicit is set when AccountID was chosen with Client.SetAccount, in
+	// which case it is used for every capability.
+	explicit bool
+}
+
+// SessionAccount describes an account the session's token can access.
+type SessionAccount struct {
+	ID                  string         `json:"id"`
+	Name                string         `json:"name"`
+	IsPersonal          bool           `json:"isPersonal"`
+	IsReadOnly          bool           `json:"isReadOnly"`
+	AccountCapabilities map[string]any `json:"accountCapabilities"`
+}
+
+// HasAccount reports whether the session can access accountID.
+func (s *Session) HasAccount(accountID string) bool {
+	_, ok := s.Accounts[accountID]
+	return ok
+}
+
+// AccountFor returns the account to use for methods of capability: the
+// explicitly selected account if there is one, otherwise the session's primary
+// account for that capability, falling back to AccountID.
+func (s *Session) AccountFor(capability string) string {
+	if !s.explicit {
+		if id := s.PrimaryAccounts[capability]; id != "" && s.HasAccount(id) {
+			return id
+		}
+	}
+	return s.AccountID
+}
+
+// selectAccount picks the session's default account. A non-empty selector
+// matches an account ID, or else an account name case-insensitively. Without
+// one, the primary mail account is used, then the first account by ID.
+func selectAccount(accounts map[string]SessionAccount, primary map[string]string, selector string) (string, error) {
+	if selector != "" {
+		if _, ok := accounts[selector]; ok {
+			return selector, nil
+		}
+		var matches []string
+		for id, acc := range accounts {
+			if strings.EqualFold(acc.Name, selector) {
+				matches = append(matches, id)
+			}
+		}
+		switch len(matches) {
+		case 1:
+			return matches[0], nil
+		case 0:
+			return "", fmt.Errorf("%w: %s", ErrAccountNotFound, selector)
+		default:
+			sort.Strings(matches)
+			return "", fmt.Errorf("account name %q is ambiguous, use one of the IDs: %s", selector, strings.Join(matches, ", "))
+		}
+	}
+
+	for _, capability := range []string{"urn:ietf:params:jmap:mail", "urn:ietf:params:jmap:core"} {
+		if id := primary[capability]; id != "" {
+			if _, ok := accounts[id]; ok {
+				return id, nil
+			}
+		}
+	}
+
+	// Fall back to the first account ID deterministically
+	accountIDs := make([]string, 0, len(accounts))
+	for id := range accounts {
+		accountIDs = append(accountIDs, id)
+	}
+	if len(accountIDs) == 0 {
+		return "", ErrNoAccounts
+	}
+	sort.Strings(accountIDs)
+	return accountIDs[0], nil
 }
 
 // Request represents a JMAP request
@@ -123,15 +227,31 @@ type MethodResponse [3]any
 
 // Client is a JMAP client for interacting with the Fastmail API
 type Client struct {
-	token          string
-	baseURL        string
-	session        *Session
-	sessionFetch   time.Time
-	sessionTTL     time.Duration
-	sessionMu      sync.RWMutex
-	http           *http.Client
-	retry          RetryConfig
-	circuitBreaker *circuitBreaker
+	token   string
+	tokenMu sync.RWMutex
+	baseURL string
+	// sessionEndpoint overrides baseURL+SessionPath (see SetSessionURL).
+	sessionEndpoint string
+	session         *Session
+	sessionFetch    time.Time
+	sessionTTL      time.Duration
+	sessionMu       sync.RWMutex
+	http            *http.Client
+	retry           RetryConfig
+	circuitBreaker  *circuitBreaker
+	// accountSelector is the JMAP account ID or name chosen with SetAccount.
+	accountSelector string
+	// cache persists the session and mailboxes across invocations (optional).
+	cache Cache
+	// scoped holds tokens limited to a scope (optional, see SetScopedTokens).
+	scoped map[TokenScope]string
+	// refresher obtains a new access token after a 401 (optional).
+	refresher TokenRefresher
+	refreshMu sync.Mutex
+	// readOnly rejects requests that would change data (see SetReadOnly).
+	readOnly bool
+	// auditor receives completed writes (optional, see SetAuditor).
+	auditor Auditor
 }
 
 // Compile-time interface compliance checks
@@ -142,25 +262,13 @@ var (
 	_ QuotaService       = (*Client)(nil)
 )
 
-// newSecureHTTPClient creates an HTTP client with secure TLS configuration.
-func newSecureHTTPClient() *http.Client {
-	return &http.Client{
-		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				MinVersion: tls.VersionTLS12,
-			},
-		},
-	}
-}
-
 // NewClient creates a new JMAP client with the provided API token
 func NewClient(token string) *Client {
 	return &Client{
 		token:          token,
 		baseURL:        DefaultBaseURL,
 		sessionTTL:     1 * time.Hour,
-		http:           newSecureHTTPClient(),
+		http:           transport.NewHTTPClient(30 * time.Second),
 		retry:          DefaultRetryConfig(),
 		circuitBreaker: newCircuitBreaker(),
 	}
@@ -172,12 +280,51 @@ func NewClientWithBaseURL(token, baseURL string) *Client {
 		token:          token,
 		baseURL:        baseURL,
 		sessionTTL:     1 * time.Hour,
-		http:           newSecureHTTPClient(),
+		http:           transport.NewHTTPClient(30 * time.Second),
 		retry:          DefaultRetryConfig(),
 		circuitBreaker: newCircuitBreaker(),
 	}
 }
 
+// SetAccount selects the JMAP account (by ID or name) used for all requests,
+// instead of the session's primary accounts. Use it to operate on shared or
+// delegated accounts the token can access.
+func (c *Client) SetAccount(selector string) {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	c.accountSelector = selector
+	c.session = nil
+}
+
+// SetSessionURL points the client at another JMAP server's session resource.
+// A URL without a path is resolved via /.well-known/jmap (see SessionURLFor).
+func (c *Client) SetSessionURL(sessionURL string) {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	c.sessionEndpoint = SessionURLFor(sessionURL)
+	c.session = nil
+}
+
+// sessionURL returns the URL the session is fetched from.
+func (c *Client) sessionURL() string {
+	if c.sessionEndpoint != "" {
+		return c.sessionEndpoint
+	}
+	return c.baseURL + SessionPath
+}
+
+// SessionURLFor returns the session URL for a configured JMAP endpoint: the
+// endpoint itself, or <endpoint>/.well-known/jmap if it has no path. The
+// HTTP client follows the server's redirect from there to the session.
+func SessionURLFor(endpoint string) string {
+	endpoint = strings.TrimSpace(endpoint)
+	u, err := url.Parse(endpoint)
+	if err != nil || strings.Trim(u.Path, "/") != "" {
+		return endpoint
+	}
+	return strings.TrimSuffix(endpoint, "/") + WellKnownPath
+}
+
 // SetRetryConfig sets a custom retry configuration (zero values use defaults).
 func (c *Client) SetRetryConfig(cfg RetryConfig) {
 	c.retry = cfg
@@ -223,37 +370,49 @@ func (c *Client) GetSession(ctx context.Context) (*Session, error) {
 		return c.session, nil
 	}
 
-	// Build session URL
-	sessionURL := c.baseURL + SessionPath
+	// Reuse a session saved by an earlier invocation
+	if session, fetched, ok := c.loadCachedSession(); ok {
+		c.session = session
+		c.sessionFetch = fetched
+		return session, nil
+	}
+
+	sessionURL := c.sessionURL()
 	reqFn := func(ctx context.Context) (*http.Request, error) {
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, sessionURL, nil)
 		if err != nil {
 			return nil, fmt.Errorf("creating session request: %w", err)
 		}
-		req.Header.Set("Authorization", "Bearer "+c.token)
+		token, err := c.tokenFor(ScopeRead)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("X-Request-ID", uuid.New().String())
 		return req, nil
 	}
 
-	resp, err := transport.DoWithRetry(ctx, c.http, c.retry, reqFn, func(attempt int, resp *http.Response) (bool, error) {
-		if resp.StatusCode == http.StatusOK {
-			return false, nil
-		}
-		if resp.StatusCode >= 500 && resp.StatusCode < 600 {
-			c.circuitBreaker.recordFailure()
-		}
-		if resp.StatusCode == http.StatusTooManyRequests {
-			if attempt < c.retry.MaxRetries {
+	resp, err := c.sendAuthorized(ctx, func() (*http.Response, error) {
+		return transport.DoWithRetry(ctx, c.http, c.retry, reqFn, func(attempt int, resp *http.Response) (bool, error) {
+			if resp.StatusCode == http.StatusOK {
+				return false, nil
+			}
+			if resp.StatusCode >= 500 && resp.StatusCode < 600 {
+				c.circuitBreaker.recordFailure()
+			}
+			if resp.StatusCode == http.StatusTooManyRequests {
+				if attempt < c.retry.MaxRetries {
+					return true, nil
+				}
+				retryAfter := transport.RetryDelay(c.retry, attempt, resp)
+				return false, &RateLimitError{RetryAfter: retryAfter}
+			}
+			if transport.IsRetriableStatus(resp.StatusCode) {
 				return true, nil
 			}
-			retryAfter := transport.RetryDelay(c.retry, attempt, resp)
-			return false, &RateLimitError{RetryAfter: retryAfter}
-		}
-		if transport.IsRetriableStatus(resp.StatusCode) {
-			return true, nil
-		}
-		return false, nil
+			return false, nil
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("fetching session: %w", err)
@@ -265,40 +424,22 @@ func (c *Client) GetSession(ctx context.Context) (*Session, error) {
 		return nil, transport.NewHTTPError("session request", resp, body)
 	}
 
-	var sessionData struct {
-		APIUrl       string                    `json:"apiUrl"`
-		Accounts     map[string]map[string]any `json:"accounts"`
-		Capabilities map[string]any            `json:"capabilities"`
-		DownloadURL  string                    `json:"downloadUrl"`
-		UploadURL    string                    `json:"uploadUrl"`
-	}
-
+	var sessionData sessionResource
 	if err := json.NewDecoder(resp.Body).Decode(&sessionData); err != nil {
 		return nil, fmt.Errorf("decoding session response: %w", err)
 	}
 
-	// Extract the first account ID deterministically (Fastmail typically has one account)
-	accountIDs := make([]string, 0, len(sessionData.Accounts))
-	for id := range sessionData.Accounts {
-		accountIDs = append(accountIDs, id)
-	}
-	if len(accountIDs) == 0 {
-		return nil, ErrNoAccounts
+	session, err := c.buildSession(sessionData)
+	if err != nil {
+		return nil, err
 	}
-	sort.Strings(accountIDs)
-	accountID := accountIDs[0]
 
 	// Build and cache session
-	c.session = &Session{
-		APIUrl:       sessionData.APIUrl,
-		AccountID:    accountID,
-		Capabilities: sessionData.Capabilities,
-		DownloadURL:  sessionData.DownloadURL,
-		UploadURL:    sessionData.UploadURL,
-	}
+	c.session = session
 
 	// Record the time of successful session fetch
 	c.sessionFetch = time.Now()
+	c.saveCachedSession(sessionData, c.sessionFetch)
 
 	// Record success in circuit breaker
 	c.circuitBreaker.recordSuccess()
@@ -306,8 +447,68 @@ func (c *Client) GetSession(ctx context.Context) (*Session, error) {
 	return c.session, nil
 }
 
+// sessionResource is the JSON session object returned by the server.
+type sessionResource struct {
+	APIUrl          string                    `json:"apiUrl"`
+	Accounts        map[string]map[string]any `json:"accounts"`
+	PrimaryAccounts map[string]string         `json:"primaryAccounts"`
+	Capabilities    map[string]any            `json:"capabilities"`
+	DownloadURL     string                    `json:"downloadUrl"`
+	UploadURL       string                    `json:"uploadUrl"`
+	Username        string                    `json:"username"`
+	State           string                    `json:"state"`
+}
+
+// buildSession converts a session resource into a Session, selecting the
+// account to operate on.
+func (c *Client) buildSession(sessionData sessionResource) (*Session, error) {
+	accounts := make(map[string]SessionAccount, len(sessionData.Accounts))
+	for id, acc := range sessionData.Accounts {
+		account := SessionAccount{
+			ID:         id,
+			Name:       getString(acc, "name"),
+			IsPersonal: getBool(acc, "isPersonal"),
+			IsReadOnly: getBool(acc, "isReadOnly"),
+		}
+		if caps, ok := acc["accountCapabilities"].(map[string]any); ok {
+			account.AccountCapabilities = caps
+		}
+		accounts[id] = account
+	}
+
+	accountID, err := selectAccount(accounts, sessionData.PrimaryAccounts, c.accountSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		APIUrl:          sessionData.APIUrl,
+		AccountID:       accountID,
+		Accounts:        accounts,
+		PrimaryAccounts: sessionData.PrimaryAccounts,
+		Capabilities:    sessionData.Capabilities,
+		DownloadURL:     sessionData.DownloadURL,
+		UploadURL:       sessionData.UploadURL,
+		Username:        sessionData.Username,
+		Limits:          parseCoreLimits(sessionData.Capabilities),
+		State:           sessionData.State,
+		explicit:        c.accountSelector != "",
+	}, nil
+}
+
 // MakeRequest executes a JMAP request and returns the response
 func (c *Client) MakeRequest(ctx context.Context, req *Request) (*Response, error) {
+	if err := c.checkReadOnly(req); err != nil {
+		return nil, err
+	}
+	if c.auditor != nil && IsWriteRequest(req) {
+		return c.makeAuditedRequest(ctx, req)
+	}
+	return c.makeRequest(ctx, req)
+}
+
+// makeRequest executes a JMAP request without auditing it.
+func (c *Client) makeRequest(ctx context.Context, req *Request) (*Response, error) {
 	// Check circuit breaker
 	if c.circuitBreaker.isOpen() {
 		return nil, &CircuitBreakerError{}
@@ -319,11 +520,26 @@ func (c *Client) MakeRequest(ctx context.Context, req *Request) (*Response, erro
 		return nil, fmt.Errorf("getting session: %w", err)
 	}
 
+	// Split calls that exceed the server's maxObjectsInGet/maxObjectsInSet
+	if parts := splitRequest(req, session.Limits); len(parts) > 1 {
+		return c.makeSplitRequest(ctx, session, parts)
+	}
+
+	return c.doRequest(ctx, session, req)
+}
+
+// doRequest sends a single JMAP request to the session's API URL.
+func (c *Client) doRequest(ctx context.Context, session *Session, req *Request) (*Response, error) {
+	scope := RequiredScope(req)
+
 	// Marshal request body once (reuse for retries)
 	body, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("marshaling request: %w", err)
 	}
+	if err := checkRequestLimits(req, body, session.Limits); err != nil {
+		return nil, err
+	}
 
 	// Generate idempotency key for write operations
 	var idempotencyKey string
@@ -343,7 +559,11 @@ func (c *Client) MakeRequest(ctx context.Context, req *Request) (*Response, erro
 		if reqErr != nil {
 			return nil, fmt.Errorf("creating request: %w", reqErr)
 		}
-		httpReq.Header.Set("Authorization", "Bearer "+c.token)
+		token, err := c.tokenFor(scope)
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
 		httpReq.Header.Set("Content-Type", "application/json")
 		httpReq.Header.Set("X-Request-ID", uuid.New().String())
 		if idempotencyKey != "" {
@@ -352,24 +572,26 @@ func (c *Client) MakeRequest(ctx context.Context, req *Request) (*Response, erro
 		return httpReq, nil
 	}
 
-	httpResp, err := transport.DoWithRetry(ctx, c.http, c.retry, reqFn, func(attempt int, resp *http.Response) (bool, error) {
-		if resp.StatusCode == http.StatusOK {
-			return false, nil
-		}
-		if resp.StatusCode >= 500 && resp.StatusCode < 600 {
-			c.circuitBreaker.recordFailure()
-		}
-		if resp.StatusCode == http.StatusTooManyRequests {
-			if attempt < c.retry.MaxRetries {
+	httpResp, err := c.sendAuthorized(ctx, func() (*http.Response, error) {
+		return transport.DoWithRetry(ctx, c.http, c.retry, reqFn, func(attempt int, resp *http.Response) (bool, error) {
+			if resp.StatusCode == http.StatusOK {
+				return false, nil
+			}
+			if resp.StatusCode >= 500 && resp.StatusCode < 600 {
+				c.circuitBreaker.recordFailure()
+			}
+			if resp.StatusCode == http.StatusTooManyRequests {
+				if attempt < c.retry.MaxRetries {
+					return true, nil
+				}
+				retryAfter := transport.RetryDelay(c.retry, attempt, resp)
+				return false, &RateLimitError{RetryAfter: retryAfter}
+			}
+			if transport.IsRetriableStatus(resp.StatusCode) {
 				return true, nil
 			}
-			retryAfter := transport.RetryDelay(c.retry, attempt, resp)
-			return false, &RateLimitError{RetryAfter: retryAfter}
-		}
-		if transport.IsRetriableStatus(resp.StatusCode) {
-			return true, nil
-		}
-		return false, nil
+			return false, nil
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("executing request: %w", err)
@@ -386,6 +608,11 @@ func (c *Client) MakeRequest(ctx context.Context, req *Request) (*Response, erro
 		return nil, fmt.Errorf("decoding response: %w", err)
 	}
 
+	// A new session state means the cached session is out of date
+	if response.SessionState != "" && session.State != "" && response.SessionState != session.State {
+		c.invalidateSession()
+	}
+
 	// Record success in circuit breaker
 	c.circuitBreaker.recordSuccess()
 
@@ -432,19 +659,25 @@ func (c *Client) DownloadBlob(ctx context.Context, blobID string) (io.ReadCloser
 		if reqErr != nil {
 			return nil, fmt.Errorf("creating download request: %w", reqErr)
 		}
-		req.Header.Set("Authorization", "Bearer "+c.token)
+		token, err := c.tokenFor(ScopeRead)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
 		req.Header.Set("X-Request-ID", uuid.New().String())
 		return req, nil
 	}
 
-	resp, err := transport.DoWithRetry(ctx, c.http, c.retry, reqFn, func(_ int, resp *http.Response) (bool, error) {
-		if resp.StatusCode == http.StatusOK {
+	resp, err := c.sendAuthorized(ctx, func() (*http.Response, error) {
+		return transport.DoWithRetry(ctx, c.http, c.retry, reqFn, func(_ int, resp *http.Response) (bool, error) {
+			if resp.StatusCode == http.StatusOK {
+				return false, nil
+			}
+			if transport.IsRetriableStatus(resp.StatusCode) {
+				return true, nil
+			}
 			return false, nil
-		}
-		if transport.IsRetriableStatus(resp.StatusCode) {
-			return true, nil
-		}
-		return false, nil
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("downloading blob: %w", err)
@@ -475,6 +708,9 @@ func (c *Client) UploadBlob(ctx context.Context, reader io.Reader, contentType s
 	if contentType == "" {
 		return nil, fmt.Errorf("contentType is required")
 	}
+	if c.readOnly {
+		return nil, &transport.ReadOnlyError{Operation: "upload a blob"}
+	}
 
 	session, err := c.GetSession(ctx)
 	if err != nil {
@@ -485,15 +721,16 @@ func (c *Client) UploadBlob(ctx context.Context, reader io.Reader, contentType s
 	uploadURL := strings.Replace(session.UploadURL, "{accountId}", session.AccountID, 1)
 
 	// Read content into buffer for potential retries, with size limit
-	limitedReader := io.LimitReader(reader, MaxUploadSize+1)
+	limit := session.UploadLimit()
+	limitedReader := io.LimitReader(reader, limit+1)
 	content, err := io.ReadAll(limitedReader)
 	if err != nil {
 		return nil, fmt.Errorf("reading upload content: %w", err)
 	}
 
 	// Check if content exceeds size limit
-	if len(content) > MaxUploadSize {
-		return nil, fmt.Errorf("upload content size exceeds maximum allowed size of %d bytes (50MB)", MaxUploadSize)
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("%w: maximum is %d bytes", ErrUploadTooLarge, limit)
 	}
 
 	reqFn := func(ctx context.Context) (*http.Request, error) {
@@ -502,20 +739,26 @@ func (c *Client) UploadBlob(ctx context.Context, reader io.Reader, contentType s
 			return nil, fmt.Errorf("creating upload request: %w", reqErr)
 		}
 
-		req.Header.Set("Authorization", "Bearer "+c.token)
+		token, err := c.tokenFor(ScopeWrite)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
 		req.Header.Set("Content-Type", contentType)
 		req.Header.Set("X-Request-ID", uuid.New().String())
 		return req, nil
 	}
 
-	resp, err := transport.DoWithRetry(ctx, c.http, c.retry, reqFn, func(_ int, resp *http.Response) (bool, error) {
-		if resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusOK {
+	resp, err := c.sendAuthorized(ctx, func() (*http.Response, error) {
+		return transport.DoWithRetry(ctx, c.http, c.retry, reqFn, func(_ int, resp *http.Response) (bool, error) {
+			if resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusOK {
+				return false, nil
+			}
+			if transport.IsRetriableStatus(resp.StatusCode) {
+				return true, nil
+			}
 			return false, nil
-		}
-		if transport.IsRetriableStatus(resp.StatusCode) {
-			return true, nil
-		}
-		return false, nil
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("uploading blob: %w", err)
@@ -83,7 +83,7 @@ func (c *Client) GetAddressBooks(ctx context.Context) ([]AddressBook, error) {
 		Using: []string{"urn:ietf:params:jmap:core", contactsCapability},
 		MethodCalls: []MethodCall{
 			{"AddressBook/get", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(contactsCapability),
 			}, "0"},
 		},
 	}
@@ -129,12 +129,12 @@ func (c *Client) GetContacts(ctx context.Context, addressBookID string, limit in
 		Using: []string{"urn:ietf:params:jmap:core", contactsCapability},
 		MethodCalls: []MethodCall{
 			{"ContactCard/query", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(contactsCapability),
 				"filter":    filter,
 				"limit":     limit,
 			}, "0"},
 			{"ContactCard/get", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(contactsCapability),
 				"#ids": map[string]any{
 					"resultOf": "0",
 					"name":     "ContactCard/query",
@@ -179,7 +179,7 @@ func (c *Client) GetContactByID(ctx context.Context, id string) (*Contact, error
 		Using: []string{"urn:ietf:params:jmap:core", contactsCapability},
 		MethodCalls: []MethodCall{
 			{"ContactCard/get", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(contactsCapability),
 				"ids":       []string{id},
 			}, "0"},
 		},
@@ -221,7 +221,7 @@ func (c *Client) CreateContact(ctx context.Context, contact *Contact) (*Contact,
 		Using: []string{"urn:ietf:params:jmap:core", contactsCapability},
 		MethodCalls: []MethodCall{
 			{"ContactCard/set", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(contactsCapability),
 				"create": map[string]any{
 					"new-contact": contact,
 				},
@@ -266,7 +266,7 @@ func (c *Client) UpdateContact(ctx context.Context, id string, updates map[strin
 		Using: []string{"urn:ietf:params:jmap:core", contactsCapability},
 		MethodCalls: []MethodCall{
 			{"ContactCard/set", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(contactsCapability),
 				"update": map[string]any{
 					id: updates,
 				},
@@ -311,7 +311,7 @@ func (c *Client) DeleteContact(ctx context.Context, id string) error {
 		Using: []string{"urn:ietf:params:jmap:core", contactsCapability},
 		MethodCalls: []MethodCall{
 			{"ContactCard/set", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(contactsCapability),
 				"destroy":   []string{id},
 			}, "0"},
 		},
@@ -349,14 +349,14 @@ func (c *Client) SearchContacts(ctx context.Context, query string, limit int) ([
 		Using: []string{"urn:ietf:params:jmap:core", contactsCapability},
 		MethodCalls: []MethodCall{
 			{"ContactCard/query", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(contactsCapability),
 				"filter": map[string]any{
 					"text": query,
 				},
 				"limit": limit,
 			}, "0"},
 			{"ContactCard/get", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor(contactsCapability),
 				"#ids": map[string]any{
 					"resultOf": "0",
 					"name":     "ContactCard/query",
@@ -0,0 +1,105 @@
+package jmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// dryRunState is the state string reported in synthetic dry-run responses.
+const dryRunState = "dry-run"
+
+// ParseRequestBody decodes a JMAP API request body. ok is false if body is
+// not a JMAP request, such as a blob upload.
+func ParseRequestBody(body []byte) (req *Request, ok bool) {
+	var r Request
+	if err := json.Unmarshal(body, &r); err != nil || len(r.MethodCalls) == 0 {
+		return nil, false
+	}
+	return &r, true
+}
+
+// IsWriteRequest reports whether req changes data: any /set, /import, /copy
+// or EmailSubmission call, as classified by RequiredScope.
+func IsWriteRequest(req *Request) bool {
+	return RequiredScope(req) != ScopeRead
+}
+
+// DryRunResponse builds the response a server would plausibly give to req
+// if every write succeeded, so a dry run can carry on without sending it.
+// Created objects get IDs of the form "dry-run-<creation id>"; read calls in
+// the same request return no data.
+func DryRunResponse(req *Request) ([]byte, error) {
+	// SessionState stays empty so the cached session is kept.
+	var resp Response
+	for _, call := range req.MethodCalls {
+		method, _ := call[0].(string)
+		args, _ := call[1].(map[string]any)
+		callID, _ := call[2].(string)
+		resp.MethodResponses = append(resp.MethodResponses, MethodResponse{method, dryRunResult(method, args), callID})
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("building dry-run response: %w", err)
+	}
+	return data, nil
+}
+
+func dryRunResult(method string, args map[string]any) map[string]any {
+	result := map[string]any{"accountId": args["accountId"]}
+
+	switch {
+	case strings.HasSuffix(method, "/set"):
+		result["oldState"] = dryRunState
+		result["newState"] = dryRunState
+		if create, ok := args["create"].(map[string]any); ok {
+			result["created"] = dryRunCreated(create)
+		}
+		if update, ok := args["update"].(map[string]any); ok {
+			updated := make(map[string]any, len(update))
+			for id := range update {
+				updated[id] = nil
+			}
+			result["updated"] = updated
+		}
+		if destroy, ok := args["destroy"].([]any); ok {
+			result["destroyed"] = destroy
+		}
+	case strings.HasSuffix(method, "/import"):
+		result["newState"] = dryRunState
+		if emails, ok := args["emails"].(map[string]any); ok {
+			result["created"] = dryRunCreated(emails)
+		}
+	case method == "Blob/copy":
+		result["fromAccountId"] = args["fromAccountId"]
+		if blobIDs, ok := args["blobIds"].([]any); ok {
+			copied := make(map[string]any, len(blobIDs))
+			for _, id := range blobIDs {
+				if id, ok := id.(string); ok {
+					copied[id] = "dry-run-" + id
+				}
+			}
+			result["copied"] = copied
+		}
+	case strings.HasSuffix(method, "/copy"):
+		result["newState"] = dryRunState
+		if create, ok := args["create"].(map[string]any); ok {
+			result["created"] = dryRunCreated(create)
+		}
+	default:
+		result["state"] = dryRunState
+		result["list"] = []any{}
+		result["notFound"] = []any{}
+		result["ids"] = []any{}
+	}
+	return result
+}
+
+func dryRunCreated(create map[string]any) map[string]any {
+	created := make(map[string]any, len(create))
+	for cid := range create {
+		id := "dry-run-" + cid
+		created[cid] = map[string]any{"id": id, "blobId": id, "threadId": id}
+	}
+	return created
+}
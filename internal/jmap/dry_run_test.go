@@ -0,0 +1,60 @@
+package jmap
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDryRunResponse(t *testing.T) {
+	req, ok := ParseRequestBody([]byte(`{
+  "using": ["urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail"],
+  "methodCalls": [
+    ["Email/set", {"accountId": "u1", "create": {"draft": {}}, "update": {"e1": {}}, "destroy": ["e2"]}, "0"],
+    ["Email/import", {"accountId": "u1", "emails": {"imp": {}}}, "1"],
+    ["Email/get", {"accountId": "u1", "ids": ["e1"]}, "2"]
+  ]
+}`))
+	if !ok || !IsWriteRequest(req) {
+		t.Fatalf("ParseRequestBody = %v, %v; want a write request", req, ok)
+	}
+	if _, ok := ParseRequestBody([]byte("raw blob bytes")); ok {
+		t.Error("ParseRequestBody accepted a non-JMAP body")
+	}
+
+	data, err := DryRunResponse(req)
+	if err != nil {
+		t.Fatalf("DryRunResponse: %v", err)
+	}
+	var resp Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	if len(resp.MethodResponses) != 3 {
+		t.Fatalf("got %d method responses, want 3", len(resp.MethodResponses))
+	}
+
+	set := resp.MethodResponses[0][1].(map[string]any)
+	created := set["created"].(map[string]any)["draft"].(map[string]any)
+	if created["id"] != "dry-run-draft" {
+		t.Errorf("created = %v", created)
+	}
+	if _, ok := set["updated"].(map[string]any)["e1"]; !ok {
+		t.Errorf("updated = %v, want e1", set["updated"])
+	}
+	if destroyed := set["destroyed"].([]any); len(destroyed) != 1 || destroyed[0] != "e2" {
+		t.Errorf("destroyed = %v", destroyed)
+	}
+
+	imported := resp.MethodResponses[1][1].(map[string]any)["created"].(map[string]any)
+	if _, ok := imported["imp"]; !ok {
+		t.Errorf("import created = %v", imported)
+	}
+	if list := resp.MethodResponses[2][1].(map[string]any)["list"].([]any); len(list) != 0 {
+		t.Errorf("read call list = %v, want empty", list)
+	}
+
+	succeeded, failed := parseBulkUpdateResult(set)
+	if len(succeeded) != 1 || len(failed) != 0 {
+		t.Errorf("parseBulkUpdateResult = %v, %v; want e1 to succeed", succeeded, failed)
+	}
+}
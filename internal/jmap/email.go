@@ -32,6 +32,7 @@ type EmailAddress struct {
 type Email struct {
 	ID            string               `json:"id"`
 	ThreadID      string               `json:"threadId,omitempty"`
+	BlobID        string               `json:"blobId,omitempty"`
 	Subject       string               `json:"subject"`
 	From          []EmailAddress       `json:"from"`
 	To            []EmailAddress       `json:"to"`
@@ -39,10 +40,13 @@ type Email struct {
 	BCC           []EmailAddress       `json:"bcc"`
 	ReplyTo       []EmailAddress       `json:"replyTo"`
 	ReceivedAt    string               `json:"receivedAt"`
+	SentAt        string               `json:"sentAt,omitempty"`
+	Size          int64                `json:"size,omitempty"`
 	Preview       string               `json:"preview,omitempty"`
 	HasAttachment bool                 `json:"hasAttachment"`
 	Keywords      map[string]bool      `json:"keywords,omitempty"`
 	MailboxIDs    map[string]bool      `json:"mailboxIds,omitempty"`
+	Snoozed       *EmailSnooze         `json:"snoozed,omitempty"`
 	BodyValues    map[string]BodyValue `json:"bodyValues,omitempty"`
 	TextBody      []BodyPart           `json:"textBody"`
 	HTMLBody      []BodyPart           `json:"htmlBody"`
@@ -113,6 +117,10 @@ func (c *Client) GetMailboxes(ctx context.Context) ([]Mailbox, error) {
 		return nil, err
 	}
 
+	if mailboxes, ok := c.cachedMailboxList(ctx, session); ok {
+		return mailboxes, nil
+	}
+
 	req := &Request{
 		Using: []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail"},
 		MethodCalls: []MethodCall{
@@ -143,21 +151,27 @@ func (c *Client) GetMailboxes(ctx context.Context) ([]Mailbox, error) {
 			continue
 		}
 
-		mailbox := Mailbox{
-			ID:            getString(mb, "id"),
-			Name:          getString(mb, "name"),
-			Role:          getString(mb, "role"),
-			TotalEmails:   getInt(mb, "totalEmails"),
-			UnreadEmails:  getInt(mb, "unreadEmails"),
-			TotalThreads:  getInt(mb, "totalThreads"),
-			UnreadThreads: getInt(mb, "unreadThreads"),
-		}
-		mailboxes = append(mailboxes, mailbox)
+		mailboxes = append(mailboxes, parseMailbox(mb))
 	}
 
+	c.saveMailboxes(session, getString(result, "state"), mailboxes)
+
 	return mailboxes, nil
 }
 
+// parseMailbox converts a Mailbox object from a JMAP response.
+func parseMailbox(mb map[string]any) Mailbox {
+	return Mailbox{
+		ID:            getString(mb, "id"),
+		Name:          getString(mb, "name"),
+		Role:          getString(mb, "role"),
+		TotalEmails:   getInt(mb, "totalEmails"),
+		UnreadEmails:  getInt(mb, "unreadEmails"),
+		TotalThreads:  getInt(mb, "totalThreads"),
+		UnreadThreads: getInt(mb, "unreadThreads"),
+	}
+}
+
 // GetMailboxByName finds a mailbox by name (case-insensitive).
 // Returns ErrMailboxNotFound if no mailbox matches the given name or role.
 func (c *Client) GetMailboxByName(ctx context.Context, name string) (*Mailbox, error) {
@@ -1839,8 +1853,11 @@ func parseEmail(data map[string]any) *Email {
 	email := &Email{
 		ID:            getString(data, "id"),
 		ThreadID:      getString(data, "threadId"),
+		BlobID:        getString(data, "blobId"),
 		Subject:       getString(data, "subject"),
 		ReceivedAt:    getString(data, "receivedAt"),
+		SentAt:        getString(data, "sentAt"),
+		Size:          getInt64(data, "size"),
 		Preview:       getString(data, "preview"),
 		HasAttachment: getBool(data, "hasAttachment"),
 		// Initialize all slice fields to empty (not nil) so JSON
@@ -1918,6 +1935,10 @@ func parseEmail(data map[string]any) *Email {
 		}
 	}
 
+	if snoozed, ok := data["snoozed"].(map[string]any); ok {
+		email.Snoozed = &EmailSnooze{Until: getString(snoozed, "until")}
+	}
+
 	// Parse body parts
 	if textBody, ok := data["textBody"].([]any); ok {
 		email.TextBody = parseBodyParts(textBody)
@@ -2314,6 +2335,103 @@ func (c *Client) ForwardEmail(ctx context.Context, original *Email, opts Forward
 	return c.SendEmail(ctx, sendOpts)
 }
 
+// ForwardEmailsAsAttachments forwards one or more emails as message/rfc822
+// attachments, so recipients receive each original message intact. The
+// originals must include BlobID (GetEmailSummaries fetches it).
+func (c *Client) ForwardEmailsAsAttachments(ctx context.Context, originals []Email, opts ForwardEmailOpts) (string, error) {
+	if len(opts.To) == 0 {
+		return "", fmt.Errorf("at least one recipient is required")
+	}
+	if len(originals) == 0 {
+		return "", fmt.Errorf("at least one email to forward is required")
+	}
+
+	fromAddress := opts.From
+	if fromAddress == "" {
+		fromAddress = c.findMaskedEmailRecipient(ctx, &originals[0])
+	}
+
+	subject := originals[0].Subject
+	if !strings.HasPrefix(strings.ToLower(subject), "fwd:") {
+		subject = "Fwd: " + subject
+	}
+	if len(originals) > 1 {
+		subject = fmt.Sprintf("%s (+%d more)", subject, len(originals)-1)
+	}
+
+	used := make(map[string]int, len(originals))
+	attachments := make([]AttachmentOpts, 0, len(originals))
+	for _, e := range originals {
+		if e.BlobID == "" {
+			return "", fmt.Errorf("email %s has no blob ID", e.ID)
+		}
+		attachments = append(attachments, AttachmentOpts{
+			BlobID: e.BlobID,
+			Name:   forwardAttachmentName(e.Subject, used),
+			Type:   "message/rfc822",
+		})
+	}
+
+	sendOpts := SendEmailOpts{
+		To:          opts.To,
+		Subject:     subject,
+		TextBody:    buildAttachedForwardBody(originals, opts.Body),
+		From:        fromAddress,
+		Attachments: attachments,
+	}
+
+	return c.SendEmail(ctx, sendOpts)
+}
+
+// buildAttachedForwardBody lists the attached messages below prependBody.
+func buildAttachedForwardBody(originals []Email, prependBody string) string {
+	var sb strings.Builder
+	if prependBody != "" {
+		sb.WriteString(prependBody)
+		sb.WriteString("\n\n")
+	}
+	if len(originals) == 1 {
+		sb.WriteString("---------- Forwarded message attached ----------\n")
+	} else {
+		fmt.Fprintf(&sb, "---------- %d forwarded messages attached ----------\n", len(originals))
+	}
+	for _, e := range originals {
+		subject := e.Subject
+		if subject == "" {
+			subject = "(no subject)"
+		}
+		fmt.Fprintf(&sb, "- %s", subject)
+		if from := formatAddressList(e.From); from != "" {
+			fmt.Fprintf(&sb, " (from %s)", from)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// forwardAttachmentName builds a filesystem-safe .eml name from subject,
+// adding a counter when the same name was already used.
+func forwardAttachmentName(subject string, used map[string]int) string {
+	name := strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(subject))
+	if runes := []rune(name); len(runes) > 80 {
+		name = strings.TrimSpace(string(runes[:80]))
+	}
+	if name == "" {
+		name = "message"
+	}
+
+	used[name]++
+	if n := used[name]; n > 1 {
+		name = fmt.Sprintf("%s (%d)", name, n)
+	}
+	return name + ".eml"
+}
+
 // buildForwardBody creates the forward message body with headers.
 func buildForwardBody(original *Email, prependBody string) (textBody, htmlBody string) {
 	// Format the date in human-readable RFC1123Z format
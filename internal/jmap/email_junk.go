@@ -0,0 +1,100 @@
+package jmap
+
+import (
+	"context"
+)
+
+// ReportSpamOpts controls how emails are reported as spam or not spam.
+type ReportSpamOpts struct {
+	// Spam reports the emails as spam when true, or as not spam when false.
+	Spam bool
+	// TargetMailboxID overrides where the emails are moved. Defaults to the
+	// junk-role mailbox for spam and the inbox-role mailbox for not spam.
+	TargetMailboxID string
+}
+
+// ReportSpam moves emails into (or out of) the junk mailbox and sets the
+// $junk / $notjunk keywords that Fastmail's spam filter learns from.
+// Handles partial failures gracefully - some emails may succeed while others fail.
+func (c *Client) ReportSpam(ctx context.Context, ids []string, opts ReportSpamOpts) (*BulkResult, error) {
+	if len(ids) == 0 {
+		return &BulkResult{
+			Succeeded: []string{},
+			Failed:    map[string]string{},
+		}, nil
+	}
+
+	session, err := c.GetSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	targetID := opts.TargetMailboxID
+	if targetID == "" {
+		mailboxes, err := c.GetMailboxes(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		role := "inbox"
+		if opts.Spam {
+			role = "junk"
+		}
+		for _, mb := range mailboxes {
+			if mb.Role == role {
+				targetID = mb.ID
+				break
+			}
+		}
+		if targetID == "" {
+			if opts.Spam {
+				return nil, ErrNoJunkMailbox
+			}
+			return nil, ErrNoInboxMailbox
+		}
+	}
+
+	// $junk and $notjunk are mutually exclusive; null removes a keyword.
+	var junk, notJunk any
+	if opts.Spam {
+		junk = true
+	} else {
+		notJunk = true
+	}
+
+	updates := make(map[string]any)
+	for _, id := range ids {
+		updates[id] = map[string]any{
+			"mailboxIds":        map[string]bool{targetID: true},
+			"keywords/$junk":    junk,
+			"keywords/$notjunk": notJunk,
+		}
+	}
+
+	req := &Request{
+		Using: []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail"},
+		MethodCalls: []MethodCall{
+			{"Email/set", map[string]any{
+				"accountId": session.AccountID,
+				"update":    updates,
+			}, "reportSpam"},
+		},
+	}
+
+	resp, err := c.MakeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := decodeMethodResponse[map[string]any](resp, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded, failed := parseBulkUpdateResult(result)
+
+	return &BulkResult{
+		Succeeded: succeeded,
+		Failed:    failed,
+	}, nil
+}
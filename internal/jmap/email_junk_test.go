@@ -0,0 +1,79 @@
+package jmap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newJunkTestClient(t *testing.T, mailboxes string, onSet func(args map[string]any)) *Client {
+	t.Helper()
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		call := req.MethodCalls[0]
+		if call[0] == "Mailbox/get" {
+			_, _ = w.Write([]byte(`{"methodResponses": [["Mailbox/get", {"list": ` + mailboxes + `}, "0"]]}`))
+			return
+		}
+		onSet(call[1].(map[string]any))
+		_, _ = w.Write([]byte(`{"methodResponses": [["Email/set", {"updated": {"e1": null}}, "reportSpam"]]}`))
+	}))
+	t.Cleanup(apiServer.Close)
+
+	sessionServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"apiUrl": "` + apiServer.URL + `", "accounts": {"acc123": {}}}`))
+	}))
+	t.Cleanup(sessionServer.Close)
+
+	return NewClientWithBaseURL("test-token", sessionServer.URL)
+}
+
+func TestReportSpam(t *testing.T) {
+	var patch map[string]any
+	client := newJunkTestClient(t, `[{"id": "in", "role": "inbox"}, {"id": "junk", "role": "junk"}]`, func(args map[string]any) {
+		patch = args["update"].(map[string]any)["e1"].(map[string]any)
+	})
+
+	result, err := client.ReportSpam(context.Background(), []string{"e1"}, ReportSpamOpts{Spam: true})
+	if err != nil {
+		t.Fatalf("ReportSpam() error: %v", err)
+	}
+	if len(result.Succeeded) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if patch["keywords/$junk"] != true || patch["keywords/$notjunk"] != nil {
+		t.Errorf("unexpected keyword patch: %v", patch)
+	}
+	if patch["mailboxIds"].(map[string]any)["junk"] != true {
+		t.Errorf("expected move to junk, got %v", patch["mailboxIds"])
+	}
+
+	if _, err := client.ReportSpam(context.Background(), []string{"e1"}, ReportSpamOpts{Spam: false}); err != nil {
+		t.Fatalf("ReportSpam(not spam) error: %v", err)
+	}
+	if patch["keywords/$notjunk"] != true || patch["keywords/$junk"] != nil {
+		t.Errorf("unexpected keyword patch: %v", patch)
+	}
+	if patch["mailboxIds"].(map[string]any)["in"] != true {
+		t.Errorf("expected move to inbox, got %v", patch["mailboxIds"])
+	}
+}
+
+func TestReportSpam_NoJunkMailbox(t *testing.T) {
+	client := newJunkTestClient(t, `[{"id": "in", "role": "inbox"}]`, func(map[string]any) {
+		t.Fatal("Email/set should not be called")
+	})
+
+	_, err := client.ReportSpam(context.Background(), []string{"e1"}, ReportSpamOpts{Spam: true})
+	if !errors.Is(err, ErrNoJunkMailbox) {
+		t.Fatalf("expected ErrNoJunkMailbox, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package jmap
+
+import (
+	"context"
+	"fmt"
+)
+
+// parseEmailProperties are the Email properties requested from Email/parse.
+// Parsed messages have no id, mailboxIds, keywords or receivedAt.
+var parseEmailProperties = []string{
+	"blobId", "subject", "from", "to", "cc", "bcc", "replyTo", "sentAt", "size",
+	"preview", "hasAttachment", "textBody", "htmlBody", "attachments", "bodyValues",
+	"messageId", "inReplyTo", "references",
+}
+
+// ParseEmail parses an uploaded RFC 5322 message blob with Email/parse and
+// returns it as an Email without creating anything in the account.
+func (c *Client) ParseEmail(ctx context.Context, blobID string) (*Email, error) {
+	session, err := c.GetSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &Request{
+		Using: []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail"},
+		MethodCalls: []MethodCall{
+			{"Email/parse", map[string]any{
+				"accountId":           session.AccountID,
+				"blobIds":             []string{blobID},
+				"properties":          parseEmailProperties,
+				"bodyProperties":      []string{"partId", "blobId", "name", "type", "size"},
+				"fetchTextBodyValues": true,
+				"fetchHTMLBodyValues": true,
+			}, "parse"},
+		},
+	}
+
+	resp, err := c.MakeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := decodeMethodResponse[map[string]any](resp, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed, ok := result["parsed"].(map[string]any); ok {
+		if data, ok := parsed[blobID].(map[string]any); ok {
+			email := parseEmail(data)
+			if email.BlobID == "" {
+				email.BlobID = blobID
+			}
+			return email, nil
+		}
+	}
+	for _, key := range []string{"notParsable", "notFound"} {
+		if ids, ok := result[key].([]any); ok && len(ids) > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrEmailNotParsable, blobID)
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected response format")
+}
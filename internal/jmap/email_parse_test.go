@@ -0,0 +1,63 @@
+package jmap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newParseTestClient(t *testing.T, response string) *Client {
+	t.Helper()
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.MethodCalls[0][0] != "Email/parse" {
+			t.Fatalf("unexpected method %v", req.MethodCalls[0][0])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"methodResponses": [["Email/parse", ` + response + `, "parse"]]}`))
+	}))
+	t.Cleanup(apiServer.Close)
+
+	sessionServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"apiUrl": "` + apiServer.URL + `", "accounts": {"acc123": {}}}`))
+	}))
+	t.Cleanup(sessionServer.Close)
+
+	return NewClientWithBaseURL("test-token", sessionServer.URL)
+}
+
+func TestClientParseEmail(t *testing.T) {
+	client := newParseTestClient(t, `{"parsed": {"B1": {
+		"subject": "Invoice",
+		"from": [{"name": "Billing", "email": "billing@example.com"}],
+		"messageId": ["abc@example.com"],
+		"attachments": [{"partId": "2", "blobId": "B1-2", "name": "invoice.pdf", "type": "application/pdf", "size": 1024}]
+	}}}`)
+
+	email, err := client.ParseEmail(context.Background(), "B1")
+	if err != nil {
+		t.Fatalf("ParseEmail() error: %v", err)
+	}
+	if email.Subject != "Invoice" || email.BlobID != "B1" || email.ID != "" {
+		t.Errorf("unexpected email: %+v", email)
+	}
+	if len(email.MessageID) != 1 || len(email.Attachments) != 1 || email.Attachments[0].Name != "invoice.pdf" {
+		t.Errorf("headers or attachments not parsed: %+v", email)
+	}
+}
+
+func TestClientParseEmail_NotParsable(t *testing.T) {
+	client := newParseTestClient(t, `{"parsed": {}, "notParsable": ["B1"]}`)
+
+	_, err := client.ParseEmail(context.Background(), "B1")
+	if !errors.Is(err, ErrEmailNotParsable) {
+		t.Fatalf("expected ErrEmailNotParsable, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package jmap
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultScanPageSize is the number of emails fetched per Email/query page during scans.
+const DefaultScanPageSize = 500
+
+// ScanEmailsOpts controls a paged scan over Email/query results.
+type ScanEmailsOpts struct {
+	MailboxID         string   // Optional: restrict the scan to one mailbox
+	ExcludeMailboxIDs []string // Optional: skip emails that are in any of these mailboxes
+	Sort              string   // Sort property: "receivedAt" (default) or "size"
+	Ascending         bool     // Sort ascending instead of descending
+	Limit             int      // Maximum number of emails to return (0 = all)
+	PageSize          int      // Emails per request (0 = DefaultScanPageSize)
+
+	Filter             *EmailSearchFilter // Optional: text and date conditions
+	HasAttachment      bool               // Only emails with attachments
+	MinSize            int64              // Only emails at least this many bytes
+	IncludeAttachments bool               // Also fetch attachment metadata for each email
+}
+
+// scanEmailProperties are the summary properties fetched for each scanned email.
+// Bodies are never fetched so scans stay cheap even for large mailboxes.
+var scanEmailProperties = []string{
+	"id", "threadId", "blobId", "subject", "from", "to", "receivedAt", "sentAt", "size",
+	"hasAttachment", "keywords", "mailboxIds", "messageId",
+}
+
+// ScanEmails pages through every email matching opts using Email/query with
+// position-based paging, and returns summary fields (including size, sentAt,
+// messageId, mailboxIds and blobId) for each one.
+//
+// Servers may return fewer ids than the requested limit, so a short page does
+// not end the scan: it stops at the query total, or on an empty page.
+func (c *Client) ScanEmails(ctx context.Context, opts ScanEmailsOpts) ([]Email, error) {
+	session, err := c.GetSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultScanPageSize
+	}
+	// The Email/get back-references every id of the page, and requests with
+	// several calls are never split, so a page must fit in one Email/get.
+	if maxGet := session.Limits.MaxObjectsInGet; maxGet > 0 && pageSize > maxGet {
+		pageSize = maxGet
+	}
+	if opts.Limit > 0 && opts.Limit < pageSize {
+		pageSize = opts.Limit
+	}
+
+	sortProperty := opts.Sort
+	if sortProperty == "" {
+		sortProperty = "receivedAt"
+	}
+
+	filter := map[string]any{}
+	if opts.Filter != nil {
+		filter = opts.Filter.ToJMAPFilter()
+	}
+	if opts.HasAttachment {
+		filter["hasAttachment"] = true
+	}
+	if opts.MinSize > 0 {
+		filter["minSize"] = opts.MinSize
+	}
+	if opts.MailboxID != "" {
+		filter["inMailbox"] = opts.MailboxID
+	}
+	if len(opts.ExcludeMailboxIDs) > 0 {
+		filter["inMailboxOtherThan"] = opts.ExcludeMailboxIDs
+	}
+
+	properties := scanEmailProperties
+	if opts.IncludeAttachments {
+		properties = append(append([]string(nil), scanEmailProperties...), "attachments")
+	}
+
+	emails := make([]Email, 0)
+	for position := 0; ; {
+		req := &Request{
+			Using: []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail"},
+			MethodCalls: []MethodCall{
+				{"Email/query", map[string]any{
+					"accountId":      session.AccountID,
+					"filter":         filter,
+					"sort":           []map[string]any{{"property": sortProperty, "isAscending": opts.Ascending}},
+					"position":       position,
+					"limit":          pageSize,
+					"calculateTotal": true,
+				}, "query"},
+				{"Email/get", map[string]any{
+					"accountId":  session.AccountID,
+					"#ids":       map[string]any{"resultOf": "query", "name": "Email/query", "path": "/ids"},
+					"properties": properties,
+				}, "emails"},
+			},
+		}
+
+		resp, err := c.MakeRequest(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		query, err := decodeMethodResponse[struct {
+			IDs   []string `json:"ids"`
+			Total *int     `json:"total"`
+		}](resp, 0)
+		if err != nil {
+			return nil, fmt.Errorf("querying emails at position %d: %w", position, err)
+		}
+		if len(resp.MethodResponses) < 2 {
+			return nil, fmt.Errorf("empty response from server")
+		}
+
+		page, err := parseEmailList(resp.MethodResponses[1])
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, page...)
+
+		if opts.Limit > 0 && len(emails) >= opts.Limit {
+			return emails[:opts.Limit], nil
+		}
+		position += len(query.IDs)
+		if len(query.IDs) == 0 || (query.Total != nil && position >= *query.Total) {
+			return emails, nil
+		}
+	}
+}
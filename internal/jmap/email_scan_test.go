@@ -0,0 +1,187 @@
+package jmap
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScanEmails_PagesUntilTotal(t *testing.T) {
+	var positions, limits []float64
+	var filters []map[string]any
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		args := req.MethodCalls[0][1].(map[string]any)
+		position := args["position"].(float64)
+		positions = append(positions, position)
+		limits = append(limits, args["limit"].(float64))
+		filters = append(filters, args["filter"].(map[string]any))
+
+		var ids []string
+		var list []map[string]any
+		// The server caps pages at two ids, below the requested limit.
+		switch position {
+		case 0:
+			ids = []string{"e1", "e2"}
+		case 2:
+			ids = []string{"e3"}
+		}
+		for _, id := range ids {
+			list = append(list, map[string]any{"id": id, "size": 100, "messageId": []string{"<" + id + "@x>"}})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"methodResponses": []any{
+				[]any{"Email/query", map[string]any{"ids": ids, "total": 3}, "query"},
+				[]any{"Email/get", map[string]any{"list": list}, "emails"},
+			},
+		})
+	}))
+	defer apiServer.Close()
+
+	sessionServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"apiUrl": "` + apiServer.URL + `", "accounts": {"acc123": {}}}`))
+	}))
+	defer sessionServer.Close()
+
+	client := NewClientWithBaseURL("test-token", sessionServer.URL)
+	emails, err := client.ScanEmails(context.Background(), ScanEmailsOpts{
+		ExcludeMailboxIDs: []string{"trash"},
+		PageSize:          5,
+	})
+	if err != nil {
+		t.Fatalf("ScanEmails() error: %v", err)
+	}
+
+	if len(emails) != 3 {
+		t.Fatalf("ScanEmails() returned %d emails, want 3", len(emails))
+	}
+	if emails[2].ID != "e3" || emails[2].Size != 100 || len(emails[2].MessageID) != 1 {
+		t.Errorf("unexpected parsed email: %+v", emails[2])
+	}
+	if len(positions) != 2 || positions[1] != 2 || limits[0] != 5 {
+		t.Errorf("expected two pages of limit 5 at positions [0 2], got %v %v", positions, limits)
+	}
+	if _, ok := filters[0]["inMailboxOtherThan"]; !ok {
+		t.Errorf("expected inMailboxOtherThan filter, got %v", filters[0])
+	}
+}
+
+func TestScanEmails_ClampsPageSizeAndStopsOnEmptyPage(t *testing.T) {
+	var limits []float64
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		args := req.MethodCalls[0][1].(map[string]any)
+		limits = append(limits, args["limit"].(float64))
+
+		// No total: the scan must page until it sees an empty page.
+		body := `{"methodResponses": [["Email/query", {"ids": []}, "query"], ["Email/get", {"list": []}, "emails"]]}`
+		if args["position"].(float64) == 0 {
+			body = `{"methodResponses": [["Email/query", {"ids": ["e1", "e2"]}, "query"], ["Email/get", {"list": [{"id": "e1"}, {"id": "e2"}]}, "emails"]]}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer apiServer.Close()
+
+	sessionServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"apiUrl": "` + apiServer.URL + `", "accounts": {"acc123": {}},
+			"capabilities": {"urn:ietf:params:jmap:core": {"maxObjectsInGet": 2}}}`))
+	}))
+	defer sessionServer.Close()
+
+	client := NewClientWithBaseURL("test-token", sessionServer.URL)
+	emails, err := client.ScanEmails(context.Background(), ScanEmailsOpts{})
+	if err != nil {
+		t.Fatalf("ScanEmails() error: %v", err)
+	}
+	if len(emails) != 2 {
+		t.Fatalf("ScanEmails() returned %d emails, want 2", len(emails))
+	}
+	if len(limits) != 2 || limits[0] != 2 {
+		t.Errorf("expected two requests with limit clamped to maxObjectsInGet, got %v", limits)
+	}
+}
+
+func TestScanEmails_RespectsLimit(t *testing.T) {
+	var requests int
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"methodResponses": [
+			["Email/query", {"ids": ["e1", "e2"]}, "query"],
+			["Email/get", {"list": [{"id": "e1"}, {"id": "e2"}]}, "emails"]
+		]}`))
+	}))
+	defer apiServer.Close()
+
+	sessionServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"apiUrl": "` + apiServer.URL + `", "accounts": {"acc123": {}}}`))
+	}))
+	defer sessionServer.Close()
+
+	client := NewClientWithBaseURL("test-token", sessionServer.URL)
+	emails, err := client.ScanEmails(context.Background(), ScanEmailsOpts{Limit: 2})
+	if err != nil {
+		t.Fatalf("ScanEmails() error: %v", err)
+	}
+	if len(emails) != 2 || requests != 1 {
+		t.Fatalf("expected 2 emails in 1 request, got %d emails in %d requests", len(emails), requests)
+	}
+}
+
+func TestScanEmails_FilterAndAttachments(t *testing.T) {
+	var args map[string]any
+	var properties []any
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		args = req.MethodCalls[0][1].(map[string]any)
+		properties = req.MethodCalls[1][1].(map[string]any)["properties"].([]any)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"methodResponses": [
+			["Email/query", {"ids": ["e1"], "total": 1}, "query"],
+			["Email/get", {"list": [{"id": "e1", "attachments": [{"blobId": "b1", "name": "invoice.pdf", "type": "application/pdf", "size": 2048}]}]}, "emails"]
+		]}`))
+	}))
+	defer apiServer.Close()
+
+	sessionServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"apiUrl": "` + apiServer.URL + `", "accounts": {"acc123": {}}}`))
+	}))
+	defer sessionServer.Close()
+
+	client := NewClientWithBaseURL("test-token", sessionServer.URL)
+	emails, err := client.ScanEmails(context.Background(), ScanEmailsOpts{
+		Filter:             &EmailSearchFilter{Text: "invoice"},
+		HasAttachment:      true,
+		MinSize:            1024,
+		IncludeAttachments: true,
+	})
+	if err != nil {
+		t.Fatalf("ScanEmails() error: %v", err)
+	}
+
+	filter := args["filter"].(map[string]any)
+	if filter["text"] != "invoice" || filter["hasAttachment"] != true || filter["minSize"] != float64(1024) {
+		t.Errorf("unexpected filter: %v", filter)
+	}
+	if properties[len(properties)-1] != "attachments" {
+		t.Errorf("expected attachments property, got %v", properties)
+	}
+	if len(emails) != 1 || len(emails[0].Attachments) != 1 || emails[0].Attachments[0].BlobID != "b1" {
+		t.Fatalf("unexpected emails: %+v", emails)
+	}
+}
@@ -0,0 +1,189 @@
+package jmap
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SnoozeCapability is the Fastmail mail extension capability. Sessions that
+// advertise it support the Email "snoozed" property, and the server moves
+// snoozed emails back to their mailbox when the snooze expires.
+const SnoozeCapability = "https://www.fastmail.com/dev/mail"
+
+// EmailSnooze is the Fastmail Email "snoozed" property.
+type EmailSnooze struct {
+	Until string `json:"until"`
+}
+
+// SupportsSnooze reports whether the session advertises server-side snooze.
+func (c *Client) SupportsSnooze(ctx context.Context) (bool, error) {
+	session, err := c.GetSession(ctx)
+	if err != nil {
+		return false, err
+	}
+	_, ok := session.Capabilities[SnoozeCapability]
+	return ok, nil
+}
+
+// SnoozeEmails sets the "snoozed" property on each email so the server wakes
+// it at until. If snoozedMailboxID is set, the emails are also moved into that
+// mailbox. Requires SnoozeCapability.
+func (c *Client) SnoozeEmails(ctx context.Context, ids []string, until time.Time, snoozedMailboxID string) (*BulkResult, error) {
+	if len(ids) == 0 {
+		return &BulkResult{Succeeded: []string{}, Failed: map[string]string{}}, nil
+	}
+
+	session, err := c.GetSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]any, len(ids))
+	for _, id := range ids {
+		patch := map[string]any{
+			"snoozed": map[string]any{"until": until.UTC().Format(time.RFC3339)},
+		}
+		if snoozedMailboxID != "" {
+			patch["mailboxIds"] = map[string]bool{snoozedMailboxID: true}
+		}
+		updates[id] = patch
+	}
+
+	req := &Request{
+		Using: []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail", SnoozeCapability},
+		MethodCalls: []MethodCall{
+			{"Email/set", map[string]any{
+				"accountId": session.AccountID,
+				"update":    updates,
+			}, "snooze"},
+		},
+	}
+
+	resp, err := c.MakeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := decodeMethodResponse[map[string]any](resp, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded, failed := parseBulkUpdateResult(result)
+	return &BulkResult{Succeeded: succeeded, Failed: failed}, nil
+}
+
+// GetSnoozedEmails returns up to limit emails in the snoozed mailbox, including
+// their "snoozed" property. Requires SnoozeCapability.
+func (c *Client) GetSnoozedEmails(ctx context.Context, snoozedMailboxID string, limit int) ([]Email, error) {
+	session, err := c.GetSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &Request{
+		Using: []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail", SnoozeCapability},
+		MethodCalls: []MethodCall{
+			{"Email/query", map[string]any{
+				"accountId": session.AccountID,
+				"filter":    map[string]any{"inMailbox": snoozedMailboxID},
+				"sort":      []map[string]any{{"property": "receivedAt", "isAscending": false}},
+				"limit":     limit,
+			}, "query"},
+			{"Email/get", map[string]any{
+				"accountId":  session.AccountID,
+				"#ids":       map[string]any{"resultOf": "query", "name": "Email/query", "path": "/ids"},
+				"properties": append(append([]string(nil), scanEmailProperties...), "snoozed"),
+			}, "emails"},
+		},
+	}
+
+	resp, err := c.MakeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.MethodResponses) < 2 {
+		return nil, fmt.Errorf("empty response from server")
+	}
+
+	return parseEmailList(resp.MethodResponses[1])
+}
+
+// GetEmailSummaries fetches summary fields (including mailboxIds and keywords)
+// for specific emails. IDs that do not exist are left out of the result.
+func (c *Client) GetEmailSummaries(ctx context.Context, ids []string) ([]Email, error) {
+	if len(ids) == 0 {
+		return []Email{}, nil
+	}
+
+	session, err := c.GetSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &Request{
+		Using: []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail"},
+		MethodCalls: []MethodCall{
+			{"Email/get", map[string]any{
+				"accountId":  session.AccountID,
+				"ids":        ids,
+				"properties": scanEmailProperties,
+			}, "emails"},
+		},
+	}
+
+	resp, err := c.MakeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.MethodResponses) == 0 {
+		return nil, fmt.Errorf("empty response from server")
+	}
+
+	return parseEmailList(resp.MethodResponses[0])
+}
+
+// RestoreEmails moves each email back into the given set of mailboxes and
+// marks it unread. It is used to wake emails snoozed without server support.
+func (c *Client) RestoreEmails(ctx context.Context, mailboxes map[string]map[string]bool) (*BulkResult, error) {
+	if len(mailboxes) == 0 {
+		return &BulkResult{Succeeded: []string{}, Failed: map[string]string{}}, nil
+	}
+
+	session, err := c.GetSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]any, len(mailboxes))
+	for id, mailboxIDs := range mailboxes {
+		updates[id] = map[string]any{
+			"mailboxIds":     mailboxIDs,
+			"keywords/$seen": nil,
+		}
+	}
+
+	req := &Request{
+		Using: []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail"},
+		MethodCalls: []MethodCall{
+			{"Email/set", map[string]any{
+				"accountId": session.AccountID,
+				"update":    updates,
+			}, "restore"},
+		},
+	}
+
+	resp, err := c.MakeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := decodeMethodResponse[map[string]any](resp, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded, failed := parseBulkUpdateResult(result)
+	return &BulkResult{Succeeded: succeeded, Failed: failed}, nil
+}
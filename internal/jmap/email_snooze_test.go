@@ -0,0 +1,84 @@
+package jmap
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSnoozeEmails(t *testing.T) {
+	var req Request
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"methodResponses": [["Email/set", {"updated": {"e1": null}}, "snooze"]]}`))
+	}))
+	defer apiServer.Close()
+
+	sessionServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"apiUrl": "` + apiServer.URL + `", "accounts": {"acc123": {}}, "capabilities": {"` + SnoozeCapability + `": {}}}`))
+	}))
+	defer sessionServer.Close()
+
+	client := NewClientWithBaseURL("test-token", sessionServer.URL)
+
+	ok, err := client.SupportsSnooze(context.Background())
+	if err != nil || !ok {
+		t.Fatalf("SupportsSnooze() = %v, %v; want true", ok, err)
+	}
+
+	until := time.Date(2025, 1, 20, 9, 0, 0, 0, time.FixedZone("X", 3600))
+	result, err := client.SnoozeEmails(context.Background(), []string{"e1"}, until, "snoozed-mb")
+	if err != nil {
+		t.Fatalf("SnoozeEmails() error: %v", err)
+	}
+	if len(result.Succeeded) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	if req.Using[len(req.Using)-1] != SnoozeCapability {
+		t.Errorf("expected snooze capability in using, got %v", req.Using)
+	}
+	patch := req.MethodCalls[0][1].(map[string]any)["update"].(map[string]any)["e1"].(map[string]any)
+	if patch["snoozed"].(map[string]any)["until"] != "2025-01-20T08:00:00Z" {
+		t.Errorf("unexpected snoozed patch: %v", patch["snoozed"])
+	}
+	if _, ok := patch["mailboxIds"].(map[string]any)["snoozed-mb"]; !ok {
+		t.Errorf("expected move to snoozed mailbox, got %v", patch["mailboxIds"])
+	}
+}
+
+func TestRestoreEmails(t *testing.T) {
+	var req Request
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"methodResponses": [["Email/set", {"updated": {"e1": null}}, "restore"]]}`))
+	}))
+	defer apiServer.Close()
+
+	sessionServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"apiUrl": "` + apiServer.URL + `", "accounts": {"acc123": {}}}`))
+	}))
+	defer sessionServer.Close()
+
+	client := NewClientWithBaseURL("test-token", sessionServer.URL)
+	if _, err := client.RestoreEmails(context.Background(), map[string]map[string]bool{"e1": {"inbox": true}}); err != nil {
+		t.Fatalf("RestoreEmails() error: %v", err)
+	}
+
+	patch := req.MethodCalls[0][1].(map[string]any)["update"].(map[string]any)["e1"].(map[string]any)
+	if seen, ok := patch["keywords/$seen"]; !ok || seen != nil {
+		t.Errorf("expected $seen to be cleared, got %v", patch)
+	}
+	if patch["mailboxIds"].(map[string]any)["inbox"] != true {
+		t.Errorf("expected original mailbox to be restored, got %v", patch["mailboxIds"])
+	}
+}
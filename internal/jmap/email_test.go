@@ -2040,3 +2040,40 @@ func TestSendDraft(t *testing.T) {
 		})
 	}
 }
+
+func Test_buildAttachedForwardBody(t *testing.T) {
+	originals := []Email{
+		{Subject: "Invoice", From: []EmailAddress{{Name: "Billing", Email: "billing@example.com"}}},
+		{Subject: ""},
+	}
+
+	got := buildAttachedForwardBody(originals, "See attached")
+	for _, want := range []string{
+		"See attached\n\n",
+		"---------- 2 forwarded messages attached ----------",
+		"- Invoice (from Billing <billing@example.com>)",
+		"- (no subject)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("body missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func Test_forwardAttachmentName(t *testing.T) {
+	used := map[string]int{}
+	tests := []struct {
+		subject string
+		want    string
+	}{
+		{"Re: Q3/Q4 plan?", "Re_ Q3_Q4 plan_.eml"},
+		{"", "message.eml"},
+		{"  ", "message (2).eml"},
+		{"Re: Q3/Q4 plan?", "Re_ Q3_Q4 plan_ (2).eml"},
+	}
+	for _, tt := range tests {
+		if got := forwardAttachmentName(tt.subject, used); got != tt.want {
+			t.Errorf("forwardAttachmentName(%q) = %q, want %q", tt.subject, got, tt.want)
+		}
+	}
+}
@@ -11,6 +11,9 @@ var (
 	// ErrNoAccounts indicates no accounts were found in session
 	ErrNoAccounts = errors.New("no accounts found in session")
 
+	// ErrAccountNotFound indicates the selected account is not in the session
+	ErrAccountNotFound = errors.New("account not found in session")
+
 	// ErrEmailNotFound indicates the requested email was not found
 	ErrEmailNotFound = errors.New("email not found")
 
@@ -47,11 +50,30 @@ var (
 	// ErrNoTrashMailbox indicates trash mailbox was not found
 	ErrNoTrashMailbox = errors.New("trash mailbox not found")
 
+	// ErrNoJunkMailbox indicates junk (spam) mailbox was not found
+	ErrNoJunkMailbox = errors.New("junk mailbox not found")
+
+	// ErrNoInboxMailbox indicates inbox mailbox was not found
+	ErrNoInboxMailbox = errors.New("inbox mailbox not found")
+
+	// ErrEmailNotParsable indicates a blob could not be parsed as an email
+	ErrEmailNotParsable = errors.New("blob is not a parsable email")
+
+	// ErrUploadTooLarge indicates an upload exceeds the server's maxSizeUpload
+	ErrUploadTooLarge = errors.New("upload content size exceeds maximum allowed size")
+
+	// ErrRequestTooLarge indicates a request exceeds the server's
+	// maxCallsInRequest or maxSizeRequest
+	ErrRequestTooLarge = errors.New("request exceeds server limits")
+
 	// ErrNoBody indicates neither text nor HTML body was provided
 	ErrNoBody = errors.New("either text or HTML body must be provided")
 
 	// ErrQuotaNotEnabled indicates quota API is not available
 	ErrQuotaNotEnabled = errors.New("quota API not enabled for this account")
+
+	// ErrTokenScope indicates no stored token may perform the operation
+	ErrTokenScope = errors.New("no token with the required scope")
 )
 
 // Typed errors for specific error conditions
@@ -0,0 +1,288 @@
+package jmap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CoreLimits are the server limits advertised by the urn:ietf:params:jmap:core
+// capability. A zero value means the server did not advertise the limit.
+type CoreLimits struct {
+	MaxSizeUpload         int64 `json:"maxSizeUpload"`
+	MaxConcurrentUpload   int   `json:"maxConcurrentUpload"`
+	MaxSizeRequest        int64 `json:"maxSizeRequest"`
+	MaxConcurrentRequests int   `json:"maxConcurrentRequests"`
+	MaxCallsInRequest     int   `json:"maxCallsInRequest"`
+	MaxObjectsInGet       int   `json:"maxObjectsInGet"`
+	MaxObjectsInSet       int   `json:"maxObjectsInSet"`
+}
+
+// parseCoreLimits reads the core limits from a session's capabilities.
+func parseCoreLimits(capabilities map[string]any) CoreLimits {
+	var limits CoreLimits
+	data, err := json.Marshal(capabilities[CapabilityCore])
+	if err != nil {
+		return limits
+	}
+	_ = json.Unmarshal(data, &limits)
+	return limits
+}
+
+// UploadLimit returns the largest blob the server accepts: maxSizeUpload if
+// advertised, otherwise MaxUploadSize.
+func (s *Session) UploadLimit() int64 {
+	if s.Limits.MaxSizeUpload > 0 {
+		return s.Limits.MaxSizeUpload
+	}
+	return MaxUploadSize
+}
+
+// UploadLimit returns the session's upload size limit.
+func (c *Client) UploadLimit(ctx context.Context) (int64, error) {
+	session, err := c.GetSession(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return session.UploadLimit(), nil
+}
+
+// checkRequestLimits rejects requests the server would refuse for having too
+// many method calls or too large a body.
+func checkRequestLimits(req *Request, body []byte, limits CoreLimits) error {
+	if limits.MaxCallsInRequest > 0 && len(req.MethodCalls) > limits.MaxCallsInRequest {
+		return fmt.Errorf("%w: %d method calls exceeds maxCallsInRequest (%d)", ErrRequestTooLarge, len(req.MethodCalls), limits.MaxCallsInRequest)
+	}
+	if limits.MaxSizeRequest > 0 && int64(len(body)) > limits.MaxSizeRequest {
+		return fmt.Errorf("%w: %d bytes exceeds maxSizeRequest (%d bytes)", ErrRequestTooLarge, len(body), limits.MaxSizeRequest)
+	}
+	return nil
+}
+
+// splitRequest splits a request with a single /get or /set call whose ids or
+// create/update/destroy entries exceed maxObjectsInGet or maxObjectsInSet into
+// requests that fit. Any other request is returned unchanged. Requests with
+// several calls are never split, since later calls may back-reference
+// earlier ones.
+func splitRequest(req *Request, limits CoreLimits) []*Request {
+	if len(req.MethodCalls) != 1 {
+		return []*Request{req}
+	}
+	call := req.MethodCalls[0]
+	method, _ := call[0].(string)
+	args, ok := call[1].(map[string]any)
+	if !ok {
+		return []*Request{req}
+	}
+
+	var chunks []map[string]any
+	switch {
+	case strings.HasSuffix(method, "/get") && limits.MaxObjectsInGet > 0:
+		chunks = splitGetArgs(args, limits.MaxObjectsInGet)
+	case strings.HasSuffix(method, "/set") && limits.MaxObjectsInSet > 0:
+		chunks = splitSetArgs(args, limits.MaxObjectsInSet)
+	}
+	if len(chunks) <= 1 {
+		return []*Request{req}
+	}
+
+	parts := make([]*Request, len(chunks))
+	for i, chunk := range chunks {
+		parts[i] = &Request{
+			Using:       req.Using,
+			MethodCalls: []MethodCall{{method, chunk, call[2]}},
+		}
+	}
+	return parts
+}
+
+// splitGetArgs splits a /get call's ids into chunks of at most size.
+func splitGetArgs(args map[string]any, size int) []map[string]any {
+	ids, ok := rawList(args["ids"])
+	if !ok || len(ids) <= size {
+		return nil
+	}
+
+	var chunks []map[string]any
+	for start := 0; start < len(ids); start += size {
+		chunk := copyArgs(args)
+		chunk["ids"] = ids[start:min(start+size, len(ids))]
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// setEntry is one create, update or destroy operation of a /set call.
+type setEntry struct {
+	kind  string
+	id    string
+	value json.RawMessage
+}
+
+// splitSetArgs splits a /set call's create, update and destroy operations
+// into chunks of at most size. Calls guarded by ifInState or with onSuccess*
+// side effects are left alone, since splitting would change their meaning.
+func splitSetArgs(args map[string]any, size int) []map[string]any {
+	for key := range args {
+		if key == "ifInState" || strings.HasPrefix(key, "onSuccess") {
+			return nil
+		}
+	}
+
+	var entries []setEntry
+	for _, kind := range []string{"create", "update"} {
+		m, ok := rawMap(args[kind])
+		if !ok {
+			continue
+		}
+		ids := make([]string, 0, len(m))
+		for id := range m {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			entries = append(entries, setEntry{kind: kind, id: id, value: m[id]})
+		}
+	}
+	if ids, ok := rawList(args["destroy"]); ok {
+		for _, id := range ids {
+			entries = append(entries, setEntry{kind: "destroy", value: id})
+		}
+	}
+	if len(entries) <= size {
+		return nil
+	}
+
+	var chunks []map[string]any
+	for start := 0; start < len(entries); start += size {
+		chunk := copyArgs(args)
+		delete(chunk, "create")
+		delete(chunk, "update")
+		delete(chunk, "destroy")
+		for _, e := range entries[start:min(start+size, len(entries))] {
+			switch e.kind {
+			case "destroy":
+				list, _ := chunk["destroy"].([]json.RawMessage)
+				chunk["destroy"] = append(list, e.value)
+			default:
+				m, ok := chunk[e.kind].(map[string]json.RawMessage)
+				if !ok {
+					m = map[string]json.RawMessage{}
+					chunk[e.kind] = m
+				}
+				m[e.id] = e.value
+			}
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// mergeSplitResponses combines the responses to the parts of a split call.
+// Lists (list, notFound, destroyed) are concatenated, maps (created,
+// updated, not*) are merged, oldState comes from the first part and other
+// values from the last.
+func mergeSplitResponses(parts []*Response) *Response {
+	merged := map[string]any{}
+	var name string
+	var callID any
+	for i, part := range parts {
+		mr := part.MethodResponses[0]
+		name, _ = mr[0].(string)
+		callID = mr[2]
+		args, _ := mr[1].(map[string]any)
+		for key, value := range args {
+			switch v := value.(type) {
+			case []any:
+				existing, _ := merged[key].([]any)
+				merged[key] = append(existing, v...)
+			case map[string]any:
+				existing, ok := merged[key].(map[string]any)
+				if !ok {
+					existing = map[string]any{}
+					merged[key] = existing
+				}
+				for k, item := range v {
+					existing[k] = item
+				}
+			default:
+				if key == "oldState" && i > 0 {
+					continue
+				}
+				if _, seen := merged[key]; seen && v == nil {
+					continue
+				}
+				merged[key] = v
+			}
+		}
+	}
+
+	last := parts[len(parts)-1]
+	return &Response{
+		MethodResponses: []MethodResponse{{name, merged, callID}},
+		SessionState:    last.SessionState,
+	}
+}
+
+// makeSplitRequest sends each part in turn and merges the responses. It stops
+// at the first part that returns a method error and returns that response,
+// so earlier parts may already have been applied.
+func (c *Client) makeSplitRequest(ctx context.Context, session *Session, parts []*Request) (*Response, error) {
+	responses := make([]*Response, 0, len(parts))
+	for _, part := range parts {
+		resp, err := c.doRequest(ctx, session, part)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.MethodResponses) != 1 {
+			return nil, fmt.Errorf("unexpected response format: %d method responses", len(resp.MethodResponses))
+		}
+		if name, _ := resp.MethodResponses[0][0].(string); name == "error" {
+			return resp, nil
+		}
+		responses = append(responses, resp)
+	}
+	return mergeSplitResponses(responses), nil
+}
+
+func copyArgs(args map[string]any) map[string]any {
+	out := make(map[string]any, len(args))
+	for k, v := range args {
+		out[k] = v
+	}
+	return out
+}
+
+// rawList converts a JSON array value of any Go type to raw elements.
+func rawList(v any) ([]json.RawMessage, bool) {
+	if v == nil {
+		return nil, false
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, false
+	}
+	return list, true
+}
+
+// rawMap converts a JSON object value of any Go type to raw entries.
+func rawMap(v any) (map[string]json.RawMessage, bool) {
+	if v == nil {
+		return nil, false
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil || m == nil {
+		return nil, false
+	}
+	return m, true
+}
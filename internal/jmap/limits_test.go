@@ -0,0 +1,252 @@
+package jmap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+// newLimitsTestClient serves a session advertising coreCaps and handles API
+// requests with api.
+func newLimitsTestClient(t *testing.T, coreCaps string, api http.HandlerFunc) *Client {
+	t.Helper()
+	apiServer := httptest.NewServer(api)
+	t.Cleanup(apiServer.Close)
+
+	sessionServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"apiUrl": "` + apiServer.URL + `",
+			"uploadUrl": "` + apiServer.URL + `/upload/{accountId}/",
+			"accounts": {"acc1": {}},
+			"capabilities": {"urn:ietf:params:jmap:core": ` + coreCaps + `}
+		}`))
+	}))
+	t.Cleanup(sessionServer.Close)
+
+	return NewClientWithBaseURL("test-token", sessionServer.URL)
+}
+
+func TestGetSession_CoreLimits(t *testing.T) {
+	client := newLimitsTestClient(t, `{"maxSizeUpload": 1000, "maxCallsInRequest": 16, "maxObjectsInGet": 500, "maxObjectsInSet": 100, "maxSizeRequest": 10000000, "maxConcurrentUpload": 4, "maxConcurrentRequests": 8}`, nil)
+
+	session, err := client.GetSession(context.Background())
+	if err != nil {
+		t.Fatalf("GetSession() error: %v", err)
+	}
+
+	want := CoreLimits{
+		MaxSizeUpload: 1000, MaxConcurrentUpload: 4, MaxSizeRequest: 10000000,
+		MaxConcurrentRequests: 8, MaxCallsInRequest: 16, MaxObjectsInGet: 500, MaxObjectsInSet: 100,
+	}
+	if session.Limits != want {
+		t.Errorf("Limits = %+v, want %+v", session.Limits, want)
+	}
+	if session.UploadLimit() != 1000 {
+		t.Errorf("UploadLimit() = %d, want 1000", session.UploadLimit())
+	}
+	if (&Session{}).UploadLimit() != MaxUploadSize {
+		t.Error("UploadLimit() should fall back to MaxUploadSize")
+	}
+}
+
+func TestSplitRequest_Get(t *testing.T) {
+	req := &Request{
+		Using: []string{CapabilityCore, CapabilityMail},
+		MethodCalls: []MethodCall{{"Thread/get", map[string]any{
+			"accountId": "acc1",
+			"ids":       []string{"t1", "t2", "t3", "t4", "t5"},
+		}, "threads"}},
+	}
+
+	parts := splitRequest(req, CoreLimits{MaxObjectsInGet: 2})
+	if len(parts) != 3 {
+		t.Fatalf("got %d parts, want 3", len(parts))
+	}
+
+	var ids []string
+	for _, part := range parts {
+		args := part.MethodCalls[0][1].(map[string]any)
+		if args["accountId"] != "acc1" || part.MethodCalls[0][2] != "threads" {
+			t.Errorf("part lost args or call ID: %v", part.MethodCalls[0])
+		}
+		var chunk []string
+		data, _ := json.Marshal(args["ids"])
+		_ = json.Unmarshal(data, &chunk)
+		if len(chunk) > 2 {
+			t.Errorf("chunk too large: %v", chunk)
+		}
+		ids = append(ids, chunk...)
+	}
+	if !reflect.DeepEqual(ids, []string{"t1", "t2", "t3", "t4", "t5"}) {
+		t.Errorf("ids = %v", ids)
+	}
+
+	if got := splitRequest(req, CoreLimits{MaxObjectsInGet: 5}); len(got) != 1 || got[0] != req {
+		t.Error("request within limits should not be split")
+	}
+}
+
+func TestSplitRequest_Set(t *testing.T) {
+	update := map[string]any{}
+	for _, id := range []string{"e1", "e2", "e3"} {
+		update[id] = map[string]any{"keywords/$seen": true}
+	}
+	req := &Request{MethodCalls: []MethodCall{{"Email/set", map[string]any{
+		"accountId": "acc1",
+		"update":    update,
+		"destroy":   []string{"e4", "e5"},
+	}, "set"}}}
+
+	parts := splitRequest(req, CoreLimits{MaxObjectsInSet: 2})
+	if len(parts) != 3 {
+		t.Fatalf("got %d parts, want 3", len(parts))
+	}
+
+	total := 0
+	for _, part := range parts {
+		data, _ := json.Marshal(part.MethodCalls[0][1])
+		var args struct {
+			Update  map[string]any `json:"update"`
+			Destroy []string       `json:"destroy"`
+		}
+		_ = json.Unmarshal(data, &args)
+		n := len(args.Update) + len(args.Destroy)
+		if n > 2 {
+			t.Errorf("part has %d operations, want at most 2", n)
+		}
+		total += n
+	}
+	if total != 5 {
+		t.Errorf("parts cover %d operations, want 5", total)
+	}
+
+	guarded := &Request{MethodCalls: []MethodCall{{"Email/set", map[string]any{
+		"ifInState": "s1",
+		"destroy":   []string{"e1", "e2", "e3"},
+	}, "set"}}}
+	if len(splitRequest(guarded, CoreLimits{MaxObjectsInSet: 2})) != 1 {
+		t.Error("ifInState calls should not be split")
+	}
+
+	multi := &Request{MethodCalls: []MethodCall{
+		{"Email/query", map[string]any{}, "q"},
+		{"Email/get", map[string]any{"ids": []string{"a", "b", "c"}}, "g"},
+	}}
+	if len(splitRequest(multi, CoreLimits{MaxObjectsInGet: 1})) != 1 {
+		t.Error("multi-call requests should not be split")
+	}
+}
+
+func TestMakeRequest_SplitsAndMerges(t *testing.T) {
+	var calls atomic.Int32
+	client := newLimitsTestClient(t, `{"maxObjectsInGet": 2}`, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var raw struct {
+			MethodCalls [][3]json.RawMessage `json:"methodCalls"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&raw)
+		var args struct {
+			IDs []string `json:"ids"`
+		}
+		_ = json.Unmarshal(raw.MethodCalls[0][1], &args)
+
+		list := []map[string]any{}
+		notFound := []string{}
+		for _, id := range args.IDs {
+			if id == "missing" {
+				notFound = append(notFound, id)
+				continue
+			}
+			list = append(list, map[string]any{"id": id, "emailIds": []string{"e-" + id}})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"methodResponses": []any{[]any{"Thread/get", map[string]any{"list": list, "notFound": notFound, "state": "s"}, "threads"}},
+			"sessionState":    "state1",
+		})
+	})
+
+	counts, err := client.GetThreadMessageCounts(context.Background(), []string{"t1", "t2", "t3", "missing", "t5"})
+	if err != nil {
+		t.Fatalf("GetThreadMessageCounts() error: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("API calls = %d, want 3", calls.Load())
+	}
+	if len(counts) != 4 || counts["t5"] != 1 {
+		t.Errorf("counts = %v", counts)
+	}
+}
+
+func TestMergeSplitResponses(t *testing.T) {
+	parts := []*Response{
+		{MethodResponses: []MethodResponse{{"Email/set", map[string]any{
+			"oldState": "s1", "newState": "s2",
+			"updated":    map[string]any{"e1": nil},
+			"notUpdated": nil,
+			"destroyed":  []any{"e3"},
+		}, "set"}}, SessionState: "a"},
+		{MethodResponses: []MethodResponse{{"Email/set", map[string]any{
+			"oldState": "s2", "newState": "s3",
+			"updated":    map[string]any{"e2": nil},
+			"notUpdated": map[string]any{"e4": map[string]any{"type": "notFound"}},
+			"destroyed":  nil,
+		}, "set"}}, SessionState: "b"},
+	}
+
+	merged := mergeSplitResponses(parts)
+	args := merged.MethodResponses[0][1].(map[string]any)
+
+	if args["oldState"] != "s1" || args["newState"] != "s3" || merged.SessionState != "b" {
+		t.Errorf("states not merged correctly: %v (session %s)", args, merged.SessionState)
+	}
+	if len(args["updated"].(map[string]any)) != 2 || len(args["notUpdated"].(map[string]any)) != 1 {
+		t.Errorf("maps not merged: %v", args)
+	}
+	if !reflect.DeepEqual(args["destroyed"], []any{"e3"}) {
+		t.Errorf("destroyed = %v", args["destroyed"])
+	}
+}
+
+func TestMakeRequest_RejectsTooManyCalls(t *testing.T) {
+	var calls atomic.Int32
+	client := newLimitsTestClient(t, `{"maxCallsInRequest": 1}`, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	})
+
+	_, err := client.MakeRequest(context.Background(), &Request{MethodCalls: []MethodCall{
+		{"Mailbox/get", map[string]any{}, "a"},
+		{"Mailbox/get", map[string]any{}, "b"},
+	}})
+	if !errors.Is(err, ErrRequestTooLarge) {
+		t.Fatalf("error = %v, want ErrRequestTooLarge", err)
+	}
+	if calls.Load() != 0 {
+		t.Error("request should not be sent")
+	}
+}
+
+func TestUploadBlob_RespectsMaxSizeUpload(t *testing.T) {
+	var uploads atomic.Int32
+	client := newLimitsTestClient(t, `{"maxSizeUpload": 10}`, func(w http.ResponseWriter, r *http.Request) {
+		uploads.Add(1)
+		_, _ = w.Write([]byte(`{"blobId": "b1", "type": "text/plain", "size": 5}`))
+	})
+
+	_, err := client.UploadBlob(context.Background(), bytes.NewReader(make([]byte, 11)), "text/plain")
+	if !errors.Is(err, ErrUploadTooLarge) {
+		t.Fatalf("error = %v, want ErrUploadTooLarge", err)
+	}
+	if uploads.Load() != 0 {
+		t.Error("oversized upload should not be sent")
+	}
+
+	if _, err := client.UploadBlob(context.Background(), bytes.NewReader(make([]byte, 10)), "text/plain"); err != nil {
+		t.Fatalf("upload within limit failed: %v", err)
+	}
+}
@@ -57,7 +57,7 @@ func (c *Client) GetMaskedEmails(ctx context.Context) ([]MaskedEmail, error) {
 			{
 				"MaskedEmail/get",
 				map[string]any{
-					"accountId":  session.AccountID,
+					"accountId":  session.AccountFor(maskedEmailNamespace),
 					"properties": []string{"id", "email", "forDomain", "state", "description", "createdAt", "lastMessageAt"},
 				},
 				"0",
@@ -158,7 +158,7 @@ func (c *Client) CreateMaskedEmail(ctx context.Context, domain, description stri
 			{
 				"MaskedEmail/set",
 				map[string]any{
-					"accountId": session.AccountID,
+					"accountId": session.AccountFor(maskedEmailNamespace),
 					"create": map[string]maskedEmailCreate{
 						"new": {
 							ForDomain:   normalizedDomain,
@@ -223,7 +223,7 @@ func (c *Client) UpdateMaskedEmailState(ctx context.Context, id string, state Ma
 			{
 				"MaskedEmail/set",
 				map[string]any{
-					"accountId": session.AccountID,
+					"accountId": session.AccountFor(maskedEmailNamespace),
 					"update": map[string]maskedEmailUpdate{
 						id: {
 							State: &state,
@@ -286,7 +286,7 @@ func (c *Client) UpdateMaskedEmailDescription(ctx context.Context, id, descripti
 			{
 				"MaskedEmail/set",
 				map[string]any{
-					"accountId": session.AccountID,
+					"accountId": session.AccountFor(maskedEmailNamespace),
 					"update": map[string]maskedEmailUpdate{
 						id: {
 							Description: &description,
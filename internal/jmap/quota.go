@@ -33,7 +33,7 @@ func (c *Client) GetQuotas(ctx context.Context) ([]Quota, error) {
 		Using: []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:quota"},
 		MethodCalls: []MethodCall{
 			{"Quota/get", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor("urn:ietf:params:jmap:quota"),
 			}, "q0"},
 		},
 	}
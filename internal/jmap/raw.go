@@ -0,0 +1,104 @@
+package jmap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// methodCapabilities maps JMAP data types to the capability that defines them.
+var methodCapabilities = map[string]string{
+	"Mailbox":          CapabilityMail,
+	"Email":            CapabilityMail,
+	"Thread":           CapabilityMail,
+	"SearchSnippet":    CapabilityMail,
+	"Identity":         CapabilitySubmission,
+	"EmailSubmission":  CapabilitySubmission,
+	"VacationResponse": CapabilityVacationResponse,
+	"Quota":            CapabilityQuota,
+	"Blob":             CapabilityBlob,
+	"AddressBook":      CapabilityContacts,
+	"ContactCard":      CapabilityContacts,
+	"Calendar":         CapabilityCalendars,
+	"CalendarEvent":    CapabilityCalendars,
+	"MaskedEmail":      CapabilityMaskedEmail,
+}
+
+// CapabilityForMethod returns the capability URI that defines method (e.g.
+// "urn:ietf:params:jmap:mail" for "Email/get"), or "" if it is unknown.
+func CapabilityForMethod(method string) string {
+	dataType, _, _ := strings.Cut(method, "/")
+	return methodCapabilities[dataType]
+}
+
+// CallRaw sends a caller-built request. Missing capabilities are added to
+// Using, and calls without an accountId get the session's account for their
+// capability. Call IDs default to "c0", "c1", ... The request is modified in
+// place so callers can show what was sent.
+func (c *Client) CallRaw(ctx context.Context, req *Request) (*Response, error) {
+	session, err := c.GetSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := PrepareRawRequest(session, req); err != nil {
+		return nil, err
+	}
+	return c.MakeRequest(ctx, req)
+}
+
+// PrepareRawRequest fills in the defaults CallRaw applies before sending.
+// Inferred capabilities are only added when the session advertises them, so
+// the server reports an unknown method rather than an unknown capability.
+func PrepareRawRequest(session *Session, req *Request) error {
+	using := map[string]bool{}
+	for _, u := range req.Using {
+		using[u] = true
+	}
+	addUsing := func(capability string) {
+		if capability != "" && !using[capability] {
+			using[capability] = true
+			req.Using = append(req.Using, capability)
+		}
+	}
+	addUsing(CapabilityCore)
+
+	for i, call := range req.MethodCalls {
+		method, ok := call[0].(string)
+		if !ok || method == "" {
+			return fmt.Errorf("method call %d: method name must be a non-empty string", i)
+		}
+
+		var args map[string]any
+		switch a := call[1].(type) {
+		case nil:
+			args = map[string]any{}
+		case map[string]any:
+			args = a
+		default:
+			return fmt.Errorf("method call %d (%s): arguments must be an object", i, method)
+		}
+
+		capability := CapabilityForMethod(method)
+		if _, advertised := session.Capabilities[capability]; advertised || len(session.Capabilities) == 0 {
+			addUsing(capability)
+		}
+
+		_, hasID := args["accountId"]
+		_, hasRef := args["#accountId"]
+		if !hasID && !hasRef && method != "Core/echo" {
+			if capability == "" {
+				args["accountId"] = session.AccountID
+			} else {
+				args["accountId"] = session.AccountFor(capability)
+			}
+		}
+
+		callID := call[2]
+		if s, ok := callID.(string); !ok || s == "" {
+			callID = fmt.Sprintf("c%d", i)
+		}
+
+		req.MethodCalls[i] = MethodCall{method, args, callID}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package jmap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCapabilityForMethod(t *testing.T) {
+	tests := map[string]string{
+		"Mailbox/get":         "urn:ietf:params:jmap:mail",
+		"EmailSubmission/set": "urn:ietf:params:jmap:submission",
+		"ContactCard/query":   contactsCapability,
+		"MaskedEmail/get":     maskedEmailNamespace,
+		"Core/echo":           "",
+		"Unknown/get":         "",
+	}
+	for method, want := range tests {
+		if got := CapabilityForMethod(method); got != want {
+			t.Errorf("CapabilityForMethod(%q) = %q, want %q", method, got, want)
+		}
+	}
+}
+
+func TestPrepareRawRequest(t *testing.T) {
+	session := &Session{
+		AccountID:       "mail-acc",
+		Accounts:        map[string]SessionAccount{"mail-acc": {}, "contacts-acc": {}},
+		PrimaryAccounts: map[string]string{contactsCapability: "contacts-acc"},
+		Capabilities: map[string]any{
+			"urn:ietf:params:jmap:core": map[string]any{},
+			"urn:ietf:params:jmap:mail": map[string]any{},
+			contactsCapability:          map[string]any{},
+		},
+	}
+	req := &Request{
+		MethodCalls: []MethodCall{
+			{"Email/query", map[string]any{"limit": 5}, ""},
+			{"Email/get", map[string]any{"#ids": map[string]any{"resultOf": "c0"}, "accountId": "other"}, "get"},
+			{"ContactCard/get", nil, nil},
+			{"Quota/get", map[string]any{}, "q"},
+		},
+	}
+
+	if err := PrepareRawRequest(session, req); err != nil {
+		t.Fatalf("PrepareRawRequest() error: %v", err)
+	}
+
+	wantUsing := []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail", contactsCapability}
+	if !reflect.DeepEqual(req.Using, wantUsing) {
+		t.Errorf("Using = %v, want %v", req.Using, wantUsing)
+	}
+
+	wantAccounts := []string{"mail-acc", "other", "contacts-acc", "mail-acc"}
+	wantIDs := []string{"c0", "get", "c2", "q"}
+	for i, call := range req.MethodCalls {
+		args := call[1].(map[string]any)
+		if args["accountId"] != wantAccounts[i] {
+			t.Errorf("call %d accountId = %v, want %s", i, args["accountId"], wantAccounts[i])
+		}
+		if call[2] != wantIDs[i] {
+			t.Errorf("call %d id = %v, want %s", i, call[2], wantIDs[i])
+		}
+	}
+}
+
+func TestPrepareRawRequest_InvalidCall(t *testing.T) {
+	session := &Session{AccountID: "a1"}
+
+	for name, call := range map[string]MethodCall{
+		"missing method": {"", map[string]any{}, "c0"},
+		"array args":     {"Email/get", []any{}, "c0"},
+	} {
+		req := &Request{MethodCalls: []MethodCall{call}}
+		if err := PrepareRawRequest(session, req); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
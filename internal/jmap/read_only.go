@@ -0,0 +1,35 @@
+package jmap
+
+import (
+	"fmt"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
+)
+
+// SetReadOnly makes the client reject requests that change data: any /set,
+// /import, /copy or EmailSubmission call, and blob uploads. Rejected requests
+// fail with a *transport.ReadOnlyError before anything is sent.
+func (c *Client) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
+
+// checkReadOnly returns an error if the client is read-only and req would
+// change data.
+func (c *Client) checkReadOnly(req *Request) error {
+	if !c.readOnly || RequiredScope(req) == ScopeRead {
+		return nil
+	}
+	for _, call := range req.MethodCalls {
+		method, _ := call[0].(string)
+		if RequiredScope(&Request{MethodCalls: []MethodCall{call}}) != ScopeRead {
+			return &transport.ReadOnlyError{Operation: fmt.Sprintf("call %s", method)}
+		}
+	}
+	return &transport.ReadOnlyError{Operation: "send a write request"}
+}
+
+// SetReadOnly makes the client reject SetSieveBlocks with a
+// *transport.ReadOnlyError.
+func (c *SieveClient) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
@@ -0,0 +1,75 @@
+package jmap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
+)
+
+func TestClient_ReadOnly(t *testing.T) {
+	var requests atomic.Int32
+	var apiURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"apiUrl": "` + apiURL + `", "uploadUrl": "` + apiURL + `", "accounts": {"acc1": {"name": "me"}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"methodResponses": [], "sessionState": ""}`))
+	}))
+	defer server.Close()
+	apiURL = server.URL + "/api"
+
+	client := NewClientWithBaseURL("token", server.URL)
+	client.SetReadOnly(true)
+
+	ctx := context.Background()
+	call := func(methods ...string) error {
+		req := &Request{}
+		for _, m := range methods {
+			req.MethodCalls = append(req.MethodCalls, MethodCall{m, map[string]any{}, "c"})
+		}
+		_, err := client.MakeRequest(ctx, req)
+		return err
+	}
+
+	for _, methods := range [][]string{
+		{"Email/set"},
+		{"Email/get", "Email/import"},
+		{"Mailbox/copy"},
+		{"EmailSubmission/set"},
+	} {
+		err := call(methods...)
+		if !transport.IsReadOnly(err) {
+			t.Errorf("%v: error = %v, want read-only error", methods, err)
+		}
+	}
+	if err := call("Email/get", "Email/import"); err == nil || !strings.Contains(err.Error(), "Email/import") {
+		t.Errorf("error = %v, want it to name the write method", err)
+	}
+	if _, err := client.UploadBlob(ctx, strings.NewReader("x"), "text/plain"); !transport.IsReadOnly(err) {
+		t.Errorf("UploadBlob error = %v, want read-only error", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Fatalf("%d requests sent, want none for rejected writes", n)
+	}
+
+	if err := call("Email/query", "Email/get"); err != nil {
+		t.Fatalf("read request: %v", err)
+	}
+}
+
+func TestSieveClient_ReadOnly(t *testing.T) {
+	client := NewSieveClient("t", "c", "http://127.0.0.1:0/session", "http://127.0.0.1:0/api")
+	client.SetReadOnly(true)
+
+	script := "stop;"
+	if err := client.SetSieveBlocks(context.Background(), SetSieveBlocksOpts{SieveAtEnd: &script}); !transport.IsReadOnly(err) {
+		t.Errorf("SetSieveBlocks error = %v, want read-only error", err)
+	}
+}
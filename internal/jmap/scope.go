@@ -0,0 +1,113 @@
+package jmap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TokenScope is the access a request needs, from least to most privileged.
+type TokenScope int
+
+const (
+	ScopeRead TokenScope = iota
+	ScopeWrite
+	ScopeSend
+)
+
+// scopeNames are the token names used in config and on the command line.
+var scopeNames = []string{"read", "write", "send"}
+
+func (s TokenScope) String() string {
+	if int(s) < len(scopeNames) {
+		return scopeNames[s]
+	}
+	return fmt.Sprintf("scope(%d)", int(s))
+}
+
+// ParseTokenScope parses a scope name: read, write or send.
+func ParseTokenScope(name string) (TokenScope, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i, n := range scopeNames {
+		if n == name {
+			return TokenScope(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid token scope %q: must be one of %s", name, strings.Join(scopeNames, ", "))
+}
+
+// RequiredScope returns the scope a request needs: send for
+// EmailSubmission/set, write for /set, /import, /copy and other methods that
+// change data, and read otherwise.
+func RequiredScope(req *Request) TokenScope {
+	scope := ScopeRead
+	for _, call := range req.MethodCalls {
+		method, _ := call[0].(string)
+		switch {
+		case method == "EmailSubmission/set":
+			return ScopeSend
+		case isWriteOperation(method), strings.HasSuffix(method, "/import"), strings.HasSuffix(method, "/copy"):
+			scope = ScopeWrite
+		}
+	}
+	return scope
+}
+
+// SessionScope describes what the session reports a token can do: send if
+// it has the submission capability, write if the selected account is not
+// read-only, and read otherwise.
+func SessionScope(session *Session) TokenScope {
+	acc, ok := session.Accounts[session.AccountID]
+	if ok && acc.IsReadOnly {
+		return ScopeRead
+	}
+	if _, ok := session.Capabilities[CapabilitySubmission]; ok {
+		return ScopeSend
+	}
+	return ScopeWrite
+}
+
+// SetScopedTokens adds tokens limited to a scope. Each request then uses the
+// least-privileged token that covers it, falling back to the client's main
+// token. If the main token is empty, requests no scoped token covers fail
+// with ErrTokenScope.
+func (c *Client) SetScopedTokens(tokens map[TokenScope]string) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	c.scoped = make(map[TokenScope]string, len(tokens))
+	for scope, token := range tokens {
+		if token != "" {
+			c.scoped[scope] = token
+		}
+	}
+}
+
+// tokenFor returns the token to use for a request needing scope.
+func (c *Client) tokenFor(scope TokenScope) (string, error) {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	return SelectToken(c.token, c.scoped, scope)
+}
+
+// SelectToken picks the least-privileged scoped token that covers scope,
+// falling back to main, which is assumed to have full access.
+func SelectToken(main string, scoped map[TokenScope]string, scope TokenScope) (string, error) {
+	for s := scope; s <= ScopeSend; s++ {
+		if token := scoped[s]; token != "" {
+			return token, nil
+		}
+	}
+	if main != "" {
+		return main, nil
+	}
+
+	have := make([]string, 0, len(scoped))
+	for s := ScopeRead; s <= ScopeSend; s++ {
+		if scoped[s] != "" {
+			have = append(have, s.String())
+		}
+	}
+	if len(have) == 0 {
+		have = append(have, "none")
+	}
+	return "", fmt.Errorf("%w: this operation needs a %s token (stored: %s)", ErrTokenScope, scope, strings.Join(have, ", "))
+}
@@ -0,0 +1,109 @@
+package jmap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestRequiredScope(t *testing.T) {
+	tests := []struct {
+		methods []string
+		want    TokenScope
+	}{
+		{[]string{"Email/query", "Email/get"}, ScopeRead},
+		{[]string{"EmailSubmission/get"}, ScopeRead},
+		{[]string{"Email/set"}, ScopeWrite},
+		{[]string{"Email/get", "Email/import"}, ScopeWrite},
+		{[]string{"Mailbox/copy"}, ScopeWrite},
+		{[]string{"Email/set", "EmailSubmission/set"}, ScopeSend},
+	}
+	for _, tt := range tests {
+		req := &Request{}
+		for _, m := range tt.methods {
+			req.MethodCalls = append(req.MethodCalls, MethodCall{m, map[string]any{}, "c"})
+		}
+		if got := RequiredScope(req); got != tt.want {
+			t.Errorf("RequiredScope(%v) = %s, want %s", tt.methods, got, tt.want)
+		}
+	}
+}
+
+func TestParseTokenScope(t *testing.T) {
+	if s, err := ParseTokenScope(" Write "); err != nil || s != ScopeWrite {
+		t.Errorf("ParseTokenScope(Write) = %v, %v", s, err)
+	}
+	if _, err := ParseTokenScope("admin"); err == nil {
+		t.Error("ParseTokenScope(admin) should fail")
+	}
+}
+
+func TestSessionScope(t *testing.T) {
+	session := &Session{
+		AccountID:    "a",
+		Accounts:     map[string]SessionAccount{"a": {ID: "a"}},
+		Capabilities: map[string]any{CapabilityMail: map[string]any{}},
+	}
+	if got := SessionScope(session); got != ScopeWrite {
+		t.Errorf("mail-only scope = %s, want write", got)
+	}
+	session.Capabilities[CapabilitySubmission] = map[string]any{}
+	if got := SessionScope(session); got != ScopeSend {
+		t.Errorf("with submission = %s, want send", got)
+	}
+	session.Accounts["a"] = SessionAccount{ID: "a", IsReadOnly: true}
+	if got := SessionScope(session); got != ScopeRead {
+		t.Errorf("read-only account = %s, want read", got)
+	}
+}
+
+func TestClient_UsesLeastPrivilegedToken(t *testing.T) {
+	var mu sync.Mutex
+	var used []string
+	var apiURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		used = append(used, r.Header.Get("Authorization"))
+		mu.Unlock()
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"apiUrl": "` + apiURL + `", "accounts": {"acc1": {"name": "me"}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"methodResponses": [], "sessionState": ""}`))
+	}))
+	defer server.Close()
+	apiURL = server.URL + "/api"
+
+	client := NewClientWithBaseURL("", server.URL)
+	client.SetScopedTokens(map[TokenScope]string{ScopeRead: "r", ScopeSend: "s"})
+
+	ctx := context.Background()
+	call := func(method string) error {
+		_, err := client.MakeRequest(ctx, &Request{MethodCalls: []MethodCall{{method, map[string]any{}, "c0"}}})
+		return err
+	}
+	if err := call("Email/get"); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if err := call("Email/set"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	want := []string{"Bearer r", "Bearer r", "Bearer s"}
+	if len(used) != len(want) {
+		t.Fatalf("Authorization headers = %v, want %v", used, want)
+	}
+	for i := range want {
+		if used[i] != want[i] {
+			t.Errorf("request %d used %q, want %q (write falls through to the send token)", i, used[i], want[i])
+		}
+	}
+
+	client.SetScopedTokens(map[TokenScope]string{ScopeRead: "r"})
+	if err := call("Email/set"); !errors.Is(err, ErrTokenScope) {
+		t.Errorf("write with only a read token: error = %v, want ErrTokenScope", err)
+	}
+}
@@ -0,0 +1,112 @@
+package jmap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const multiAccountSession = `{
+	"apiUrl": "https://api.example.com",
+	"accounts": {
+		"a1": {"name": "me@example.com", "isPersonal": true},
+		"a2": {"name": "team@example.com", "isPersonal": false, "isReadOnly": true,
+			"accountCapabilities": {"urn:ietf:params:jmap:mail": {}}}
+	},
+	"primaryAccounts": {
+		"urn:ietf:params:jmap:mail": "a2",
+		"urn:ietf:params:jmap:contacts": "a1"
+	}
+}`
+
+func newSessionTestClient(t *testing.T) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(multiAccountSession))
+	}))
+	t.Cleanup(server.Close)
+	return NewClientWithBaseURL("test-token", server.URL)
+}
+
+func TestGetSession_PrimaryAccounts(t *testing.T) {
+	client := newSessionTestClient(t)
+
+	session, err := client.GetSession(context.Background())
+	if err != nil {
+		t.Fatalf("GetSession() error: %v", err)
+	}
+	if session.AccountID != "a2" {
+		t.Errorf("AccountID = %q, want primary mail account a2", session.AccountID)
+	}
+	if got := session.AccountFor(contactsCapability); got != "a1" {
+		t.Errorf("AccountFor(contacts) = %q, want a1", got)
+	}
+	if got := session.AccountFor(calendarsCapability); got != "a2" {
+		t.Errorf("AccountFor(calendars) = %q, want fallback a2", got)
+	}
+
+	team := session.Accounts["a2"]
+	if team.ID != "a2" || team.Name != "team@example.com" || team.IsPersonal || !team.IsReadOnly {
+		t.Errorf("unexpected account: %+v", team)
+	}
+	if _, ok := team.AccountCapabilities["urn:ietf:params:jmap:mail"]; !ok {
+		t.Errorf("account capabilities not kept: %+v", team.AccountCapabilities)
+	}
+}
+
+func TestSetAccount(t *testing.T) {
+	client := newSessionTestClient(t)
+	ctx := context.Background()
+
+	client.SetAccount("ME@example.com")
+	session, err := client.GetSession(ctx)
+	if err != nil {
+		t.Fatalf("GetSession() error: %v", err)
+	}
+	if session.AccountID != "a1" || session.AccountFor(contactsCapability) != "a1" || session.AccountFor(calendarsCapability) != "a1" {
+		t.Errorf("selected account should be used for every capability, got %+v", session)
+	}
+
+	client.SetAccount("a2")
+	session, err = client.GetSession(ctx)
+	if err != nil {
+		t.Fatalf("GetSession() error: %v", err)
+	}
+	if session.AccountFor(contactsCapability) != "a2" {
+		t.Errorf("SetAccount by ID should override primary accounts, got %q", session.AccountFor(contactsCapability))
+	}
+
+	client.SetAccount("nobody")
+	if _, err := client.GetSession(ctx); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("expected ErrAccountNotFound, got %v", err)
+	}
+}
+
+func TestSelectAccount_Fallback(t *testing.T) {
+	accounts := map[string]SessionAccount{"b": {}, "a": {}}
+
+	id, err := selectAccount(accounts, nil, "")
+	if err != nil || id != "a" {
+		t.Errorf("selectAccount() = %q, %v; want first ID a", id, err)
+	}
+	if _, err := selectAccount(map[string]SessionAccount{}, nil, ""); !errors.Is(err, ErrNoAccounts) {
+		t.Errorf("expected ErrNoAccounts, got %v", err)
+	}
+}
+
+func TestSessionURLFor(t *testing.T) {
+	tests := map[string]string{
+		"https://mail.example.com":                 "https://mail.example.com/.well-known/jmap",
+		"https://mail.example.com/":                "https://mail.example.com/.well-known/jmap",
+		"http://127.0.0.1:8080":                    "http://127.0.0.1:8080/.well-known/jmap",
+		"https://mail.example.com/jmap/session":    "https://mail.example.com/jmap/session",
+		" https://api.fastmail.com/jmap/session/ ": "https://api.fastmail.com/jmap/session/",
+	}
+	for in, want := range tests {
+		if got := SessionURLFor(in); got != want {
+			t.Errorf("SessionURLFor(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -8,8 +8,11 @@ import (
 	"io"
 	"net/http"
 	"sort"
+	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
 )
 
 // SieveBlocks represents the Fastmail Sieve script blocks.
@@ -33,16 +36,18 @@ type SieveClient struct {
 	apiURL     string
 	http       *http.Client
 	accountID  string
+	readOnly   bool
 }
 
 // NewSieveClient creates a Sieve client with browser session credentials.
+// An empty apiURL is taken from the session resource.
 func NewSieveClient(token, cookie, sessionURL, apiURL string) *SieveClient {
 	return &SieveClient{
 		token:      token,
 		cookie:     cookie,
 		sessionURL: sessionURL,
 		apiURL:     apiURL,
-		http:       newSecureHTTPClient(),
+		http:       transport.NewHTTPClient(30 * time.Second),
 	}
 }
 
@@ -53,10 +58,15 @@ func NewSieveClientFromCredentials(token, cookie string) *SieveClient {
 		cookie:     cookie,
 		sessionURL: "https://api.fastmail.com/jmap/session",
 		apiURL:     "https://api.fastmail.com/jmap/api",
-		http:       newSecureHTTPClient(),
+		http:       transport.NewHTTPClient(30 * time.Second),
 	}
 }
 
+// SetHTTPClient sets a custom HTTP client for the Sieve client
+func (c *SieveClient) SetHTTPClient(httpClient *http.Client) {
+	c.http = httpClient
+}
+
 func (c *SieveClient) getAccountID(ctx context.Context) (string, error) {
 	if c.accountID != "" {
 		return c.accountID, nil
@@ -81,11 +91,15 @@ func (c *SieveClient) getAccountID(ctx context.Context) (string, error) {
 	}
 
 	var session struct {
+		APIUrl   string         `json:"apiUrl"`
 		Accounts map[string]any `json:"accounts"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
 		return "", err
 	}
+	if c.apiURL == "" {
+		c.apiURL = session.APIUrl
+	}
 
 	ids := make([]string, 0, len(session.Accounts))
 	for id := range session.Accounts {
@@ -191,6 +205,10 @@ type SetSieveBlocksOpts struct {
 
 // SetSieveBlocks updates the writable Sieve script blocks.
 func (c *SieveClient) SetSieveBlocks(ctx context.Context, opts SetSieveBlocksOpts) error {
+	if c.readOnly {
+		return &transport.ReadOnlyError{Operation: "update sieve scripts"}
+	}
+
 	accountID, err := c.getAccountID(ctx)
 	if err != nil {
 		return err
@@ -0,0 +1,112 @@
+package jmap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TokenRefresher returns a new access token after the server rejected the
+// current one, e.g. by redeeming an OAuth refresh token.
+type TokenRefresher func(ctx context.Context) (string, error)
+
+// SetTokenRefresher enables transparent token refresh: a request that gets a
+// 401 refreshes the token once and is retried before the error is returned.
+func (c *Client) SetTokenRefresher(fn TokenRefresher) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	c.refresher = fn
+}
+
+// bearer returns the current access token.
+func (c *Client) bearer() string {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	return c.token
+}
+
+// refreshToken replaces stale with a new token from the refresher. When
+// several requests fail at once only the first refreshes; the others see the
+// token has already changed and retry with it.
+func (c *Client) refreshToken(ctx context.Context, stale string) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	if c.bearer() != stale {
+		return nil
+	}
+
+	token, err := c.refresher(ctx)
+	if err != nil {
+		return &AuthError{Message: fmt.Sprintf("refreshing access token: %v", err)}
+	}
+	if token == "" {
+		return &AuthError{Message: "refreshing access token: empty token"}
+	}
+
+	c.tokenMu.Lock()
+	c.token = token
+	c.tokenMu.Unlock()
+	return nil
+}
+
+// sendAuthorized runs send and, if the server answers 401 and a refresher is
+// set, refreshes the token and runs send once more. A second 401 is reported
+// as an AuthError. Only the main token can be refreshed: a 401 for a request
+// signed with a scoped token is returned as is.
+func (c *Client) sendAuthorized(ctx context.Context, send func() (*http.Response, error)) (*http.Response, error) {
+	c.refreshMu.Lock()
+	canRefresh := c.refresher != nil
+	c.refreshMu.Unlock()
+
+	resp, err := send()
+	if err != nil || !canRefresh || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The request builders sign with tokenFor(scope), so the rejected
+	// request itself says which token was used.
+	stale := signedToken(resp)
+	if stale == "" || (stale != c.bearer() && c.isScopedToken(stale)) {
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+
+	if err := c.refreshToken(ctx, stale); err != nil {
+		return nil, err
+	}
+
+	resp, err = send()
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	_ = resp.Body.Close()
+	return nil, &AuthError{Message: "access token rejected after refresh"}
+}
+
+// signedToken returns the bearer token the request behind resp was sent
+// with, or "" if it had none.
+func signedToken(resp *http.Response) string {
+	if resp.Request == nil {
+		return ""
+	}
+	token, ok := strings.CutPrefix(resp.Request.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	return token
+}
+
+// isScopedToken reports whether token is one of the scoped tokens rather
+// than the main one.
+func (c *Client) isScopedToken(token string) bool {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	for _, scoped := range c.scoped {
+		if scoped == token {
+			return true
+		}
+	}
+	return false
+}
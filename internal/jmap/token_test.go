@@ -0,0 +1,119 @@
+package jmap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
+)
+
+// newTokenTestServer serves a session only to requests bearing valid.
+func newTokenTestServer(t *testing.T, valid *atomic.Value) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+valid.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"apiUrl": "http://example.invalid", "username": "me@example.com", "accounts": {"acc1": {"name": "me"}}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_RefreshesTokenOn401(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("fresh")
+	server := newTokenTestServer(t, &valid)
+
+	var refreshes atomic.Int32
+	client := NewClientWithBaseURL("expired", server.URL)
+	client.SetTokenRefresher(func(context.Context) (string, error) {
+		refreshes.Add(1)
+		return "fresh", nil
+	})
+
+	session, err := client.GetSession(context.Background())
+	if err != nil {
+		t.Fatalf("GetSession() error: %v", err)
+	}
+	if session.Username != "me@example.com" {
+		t.Errorf("Username = %q", session.Username)
+	}
+	if refreshes.Load() != 1 {
+		t.Errorf("refreshes = %d, want 1", refreshes.Load())
+	}
+	if client.bearer() != "fresh" {
+		t.Errorf("token not replaced: %q", client.bearer())
+	}
+}
+
+func TestClient_RefreshFailureIsAuthError(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("fresh")
+	server := newTokenTestServer(t, &valid)
+
+	client := NewClientWithBaseURL("expired", server.URL)
+	client.SetTokenRefresher(func(context.Context) (string, error) {
+		return "", errors.New("invalid_grant")
+	})
+
+	_, err := client.GetSession(context.Background())
+	if !IsAuthError(err) {
+		t.Fatalf("error = %v, want AuthError", err)
+	}
+}
+
+func TestClient_RejectedAfterRefreshIsAuthError(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("never")
+	server := newTokenTestServer(t, &valid)
+
+	client := NewClientWithBaseURL("expired", server.URL)
+	client.SetTokenRefresher(func(context.Context) (string, error) {
+		return "also-rejected", nil
+	})
+
+	_, err := client.GetSession(context.Background())
+	if !IsAuthError(err) {
+		t.Fatalf("error = %v, want AuthError", err)
+	}
+}
+
+func TestClient_NoRefresherKeeps401(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("fresh")
+	server := newTokenTestServer(t, &valid)
+
+	client := NewClientWithBaseURL("expired", server.URL)
+	_, err := client.GetSession(context.Background())
+	if !transport.IsUnauthorized(err) {
+		t.Fatalf("error = %v, want 401 HTTPError", err)
+	}
+}
+
+func TestClient_ScopedToken401DoesNotRefreshMainToken(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("main")
+	server := newTokenTestServer(t, &valid)
+
+	var refreshes atomic.Int32
+	client := NewClientWithBaseURL("main", server.URL)
+	client.SetScopedTokens(map[TokenScope]string{ScopeRead: "revoked-read"})
+	client.SetTokenRefresher(func(context.Context) (string, error) {
+		refreshes.Add(1)
+		return "rotated", nil
+	})
+
+	_, err := client.GetSession(context.Background())
+	if !transport.IsUnauthorized(err) {
+		t.Fatalf("error = %v, want the scoped token's 401", err)
+	}
+	if refreshes.Load() != 0 || client.bearer() != "main" {
+		t.Errorf("main token refreshed (%d times, now %q) for a scoped token's 401", refreshes.Load(), client.bearer())
+	}
+}
@@ -27,7 +27,7 @@ func (c *Client) GetVacationResponse(ctx context.Context) (*VacationResponse, er
 		Using: []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:vacationresponse"},
 		MethodCalls: []MethodCall{
 			{"VacationResponse/get", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor("urn:ietf:params:jmap:vacationresponse"),
 			}, "getVacation"},
 		},
 	}
@@ -119,7 +119,7 @@ func (c *Client) SetVacationResponse(ctx context.Context, opts SetVacationRespon
 		Using: []string{"urn:ietf:params:jmap:core", "urn:ietf:params:jmap:vacationresponse"},
 		MethodCalls: []MethodCall{
 			{"VacationResponse/set", map[string]any{
-				"accountId": session.AccountID,
+				"accountId": session.AccountFor("urn:ietf:params:jmap:vacationresponse"),
 				"update": map[string]any{
 					current.ID: update,
 				},
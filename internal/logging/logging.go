@@ -2,27 +2,58 @@ package logging
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"strings"
 )
 
 type contextKey struct{}
 
+// Format selects how log records are written.
+type Format string
+
+const (
+	// FormatText writes key=value lines.
+	FormatText Format = "text"
+	// FormatJSON writes one JSON object per line.
+	FormatJSON Format = "json"
+)
+
+// ParseFormat parses a --log-format value. An empty value means text.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("invalid log format %q (want text or json)", s)
+	}
+}
+
 // Setup creates a new slog.Logger with the appropriate log level.
 // If debug is true, the logger will log at Debug level.
 // If debug is false, the logger will log at Info level.
 // The logger writes to stderr using a text handler.
 func Setup(debug bool) *slog.Logger {
+	return New(os.Stderr, debug, FormatText)
+}
+
+// New creates a logger like Setup, writing to w in the given format.
+func New(w io.Writer, debug bool, format Format) *slog.Logger {
 	level := slog.LevelInfo
 	if debug {
 		level = slog.LevelDebug
 	}
-
-	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+	opts := &slog.HandlerOptions{
 		Level: level,
-	})
-
-	return slog.New(handler)
+	}
+	if format == FormatJSON {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
 }
 
 // WithLogger stores the logger in the context.
@@ -1,7 +1,9 @@
 package logging
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"log/slog"
 	"testing"
 )
@@ -70,3 +72,27 @@ func TestFromContext_ReturnsDefault_WithNilContext(t *testing.T) {
 	// and document that a valid context must always be passed
 	t.Skip("FromContext requires a valid context")
 }
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"": FormatText, "text": FormatText, "JSON": FormatJSON} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("logfmt"); err == nil {
+		t.Error("ParseFormat(logfmt) should fail")
+	}
+}
+
+func TestNew_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	New(&buf, true, FormatJSON).Debug("hello", "attempt", 2)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("log line %q is not JSON: %v", buf.String(), err)
+	}
+	if record["msg"] != "hello" || record["attempt"] != float64(2) {
+		t.Errorf("record = %v", record)
+	}
+}
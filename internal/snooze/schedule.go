@@ -0,0 +1,137 @@
+// Package snooze keeps the local snooze schedule used when the server does not
+// support snoozing natively. Entries record where each email lived before it
+// was moved to the Snoozed mailbox so 'fastmail email snooze wake' can put it
+// back.
+package snooze
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/config"
+)
+
+// FileName is the name of the schedule file inside config.StateDir().
+const FileName = "snoozed.json"
+
+// Entry is one snoozed email.
+type Entry struct {
+	Account    string          `json:"account"`
+	EmailID    string          `json:"emailId"`
+	Subject    string          `json:"subject,omitempty"`
+	Until      time.Time       `json:"until"`
+	SnoozedAt  time.Time       `json:"snoozedAt"`
+	MailboxIDs map[string]bool `json:"mailboxIds"`
+}
+
+// Schedule is the set of locally tracked snoozes.
+type Schedule struct {
+	Entries []Entry `json:"entries"`
+}
+
+// DefaultPath returns the schedule path inside the fastmail-cli state directory.
+func DefaultPath() string {
+	return filepath.Join(config.StateDir(), FileName)
+}
+
+// Load reads the schedule at path. A missing file is an empty schedule.
+func Load(path string) (*Schedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Schedule{Entries: []Entry{}}, nil
+		}
+		return nil, fmt.Errorf("read snooze schedule: %w", err)
+	}
+
+	var s Schedule
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse snooze schedule: %w", err)
+	}
+	if s.Entries == nil {
+		s.Entries = []Entry{}
+	}
+	return &s, nil
+}
+
+// Save writes the schedule to path, creating the parent directory if needed.
+func (s *Schedule) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal snooze schedule: %w", err)
+	}
+
+	// Write to a temp file first so a crash never leaves a truncated schedule.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write snooze schedule: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write snooze schedule: %w", err)
+	}
+	return nil
+}
+
+// Add records e, replacing any existing entry for the same account and email.
+// When replacing, the original mailboxes are kept so re-snoozing an email that
+// is already in the Snoozed mailbox still wakes it into its real home.
+func (s *Schedule) Add(e Entry) {
+	for i, existing := range s.Entries {
+		if existing.Account == e.Account && existing.EmailID == e.EmailID {
+			e.MailboxIDs = existing.MailboxIDs
+			s.Entries[i] = e
+			return
+		}
+	}
+	s.Entries = append(s.Entries, e)
+}
+
+// Remove deletes the entries for the given account and email IDs.
+func (s *Schedule) Remove(account string, emailIDs ...string) {
+	drop := make(map[string]bool, len(emailIDs))
+	for _, id := range emailIDs {
+		drop[id] = true
+	}
+
+	kept := s.Entries[:0]
+	for _, e := range s.Entries {
+		if e.Account == account && drop[e.EmailID] {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	s.Entries = kept
+}
+
+// ForAccount returns the entries for account, soonest first.
+func (s *Schedule) ForAccount(account string) []Entry {
+	entries := make([]Entry, 0)
+	for _, e := range s.Entries {
+		if e.Account == account {
+			entries = append(entries, e)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Until.Before(entries[j].Until)
+	})
+	return entries
+}
+
+// Due returns the entries for account whose snooze has expired at now.
+func (s *Schedule) Due(account string, now time.Time) []Entry {
+	due := make([]Entry, 0)
+	for _, e := range s.ForAccount(account) {
+		if !e.Until.After(now) {
+			due = append(due, e)
+		}
+	}
+	return due
+}
@@ -0,0 +1,65 @@
+package snooze
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScheduleRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", FileName)
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load missing file: %v", err)
+	}
+	if len(s.Entries) != 0 {
+		t.Fatalf("expected empty schedule, got %+v", s.Entries)
+	}
+
+	until := time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC)
+	s.Add(Entry{Account: "a@example.com", EmailID: "e1", Until: until, MailboxIDs: map[string]bool{"inbox": true}})
+	if err := s.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded.Entries) != 1 || !loaded.Entries[0].Until.Equal(until) || !loaded.Entries[0].MailboxIDs["inbox"] {
+		t.Fatalf("unexpected entries after reload: %+v", loaded.Entries)
+	}
+}
+
+func TestScheduleAddKeepsOriginalMailboxes(t *testing.T) {
+	s := &Schedule{}
+	s.Add(Entry{Account: "a", EmailID: "e1", Until: time.Unix(100, 0), MailboxIDs: map[string]bool{"inbox": true}})
+	s.Add(Entry{Account: "a", EmailID: "e1", Until: time.Unix(200, 0), MailboxIDs: map[string]bool{"snoozed": true}})
+
+	if len(s.Entries) != 1 {
+		t.Fatalf("expected one entry, got %d", len(s.Entries))
+	}
+	if !s.Entries[0].MailboxIDs["inbox"] || s.Entries[0].Until.Unix() != 200 {
+		t.Errorf("expected updated time with original mailboxes, got %+v", s.Entries[0])
+	}
+}
+
+func TestScheduleDueAndRemove(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := &Schedule{Entries: []Entry{
+		{Account: "a", EmailID: "later", Until: now.Add(time.Hour)},
+		{Account: "a", EmailID: "due", Until: now.Add(-time.Minute)},
+		{Account: "b", EmailID: "other", Until: now.Add(-time.Hour)},
+	}}
+
+	due := s.Due("a", now)
+	if len(due) != 1 || due[0].EmailID != "due" {
+		t.Fatalf("unexpected due entries: %+v", due)
+	}
+
+	s.Remove("a", "due")
+	if len(s.ForAccount("a")) != 1 || len(s.ForAccount("b")) != 1 {
+		t.Fatalf("unexpected entries after remove: %+v", s.Entries)
+	}
+}
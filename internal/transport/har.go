@@ -0,0 +1,215 @@
+package transport
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// HAR 1.2 structures, as read by browser dev tools and HAR viewers.
+// See http://www.softwareishard.com/blog/har-12-spec/.
+type (
+	harFile struct {
+		Log harLog `json:"log"`
+	}
+	harLog struct {
+		Version string     `json:"version"`
+		Creator harCreator `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	}
+	harCreator struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	harEntry struct {
+		StartedDateTime time.Time   `json:"startedDateTime"`
+		Time            float64     `json:"time"`
+		Request         harRequest  `json:"request"`
+		Response        harResponse `json:"response"`
+		Cache           struct{}    `json:"cache"`
+		Timings         harTimings  `json:"timings"`
+	}
+	harRequest struct {
+		Method      string         `json:"method"`
+		URL         string         `json:"url"`
+		HTTPVersion string         `json:"httpVersion"`
+		Cookies     []harNameValue `json:"cookies"`
+		Headers     []harNameValue `json:"headers"`
+		QueryString []harNameValue `json:"queryString"`
+		PostData    *harPostData   `json:"postData,omitempty"`
+		HeadersSize int            `json:"headersSize"`
+		BodySize    int            `json:"bodySize"`
+	}
+	harResponse struct {
+		Status      int            `json:"status"`
+		StatusText  string         `json:"statusText"`
+		HTTPVersion string         `json:"httpVersion"`
+		Cookies     []harNameValue `json:"cookies"`
+		Headers     []harNameValue `json:"headers"`
+		Content     harContent     `json:"content"`
+		RedirectURL string         `json:"redirectURL"`
+		HeadersSize int            `json:"headersSize"`
+		BodySize    int64          `json:"bodySize"`
+	}
+	harNameValue struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	harPostData struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+		Comment  string `json:"comment,omitempty"`
+	}
+	harContent struct {
+		Size     int64  `json:"size"`
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text,omitempty"`
+		Encoding string `json:"encoding,omitempty"`
+		Comment  string `json:"comment,omitempty"`
+	}
+	harTimings struct {
+		Send    float64 `json:"send"`
+		Wait    float64 `json:"wait"`
+		Receive float64 `json:"receive"`
+	}
+)
+
+// record adds a HAR entry for an exchange. A nil resp records a request
+// that failed before any response arrived.
+func (t *Tracer) record(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, respSize int64, truncated bool, start time.Time, wait, receive time.Duration) {
+	if !t.recordHAR {
+		return
+	}
+	entry := harEntry{
+		StartedDateTime: start,
+		Time:            millis(wait + receive),
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         RedactURL(req.URL),
+			HTTPVersion: req.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(req.Header),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: harResponse{
+			Cookies:     []harNameValue{},
+			Headers:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: harTimings{Wait: millis(wait), Receive: millis(receive)},
+	}
+	if entry.Request.HTTPVersion == "" {
+		entry.Request.HTTPVersion = "HTTP/1.1"
+	}
+	for name, values := range req.URL.Query() {
+		value := values[0]
+		if IsSensitiveName(name) {
+			value = Redacted
+		}
+		entry.Request.QueryString = append(entry.Request.QueryString, harNameValue{Name: name, Value: value})
+	}
+	sortNameValues(entry.Request.QueryString)
+	if len(reqBody) > 0 {
+		contentType := req.Header.Get("Content-Type")
+		entry.Request.PostData = &harPostData{MimeType: contentType}
+		switch {
+		case !utf8.Valid(reqBody):
+			entry.Request.PostData.Comment = fmt.Sprintf("%d bytes of binary data omitted", len(reqBody))
+		case len(reqBody) > maxTraceBody && !structuredBody(contentType):
+			entry.Request.PostData.Text = string(reqBody[:maxTraceBody])
+			entry.Request.PostData.Comment = fmt.Sprintf("truncated to %d of %d bytes", maxTraceBody, len(reqBody))
+		default:
+			entry.Request.PostData.Text = string(RedactBody(contentType, reqBody))
+		}
+	}
+
+	if resp == nil {
+		entry.Response.HTTPVersion = entry.Request.HTTPVersion
+		entry.Response.Content.MimeType = "x-unknown"
+	} else {
+		contentType := resp.Header.Get("Content-Type")
+		entry.Response.Status = resp.StatusCode
+		entry.Response.StatusText = strings.TrimSpace(strings.TrimPrefix(resp.Status, fmt.Sprint(resp.StatusCode)))
+		entry.Response.HTTPVersion = resp.Proto
+		entry.Response.Headers = harHeaders(resp.Header)
+		entry.Response.RedirectURL = resp.Header.Get("Location")
+		entry.Response.BodySize = respSize
+		entry.Response.Content = harContent{Size: respSize, MimeType: contentType}
+		switch {
+		case len(respBody) == 0:
+		case utf8.Valid(respBody) && isTextual(contentType):
+			entry.Response.Content.Text = string(RedactBody(contentType, respBody))
+		default:
+			entry.Response.Content.Text = base64.StdEncoding.EncodeToString(respBody)
+			entry.Response.Content.Encoding = "base64"
+		}
+		if truncated {
+			entry.Response.Content.Comment = fmt.Sprintf("truncated to %d of %d bytes", len(respBody), respSize)
+		}
+	}
+
+	t.mu.Lock()
+	t.entries = append(t.entries, entry)
+	t.mu.Unlock()
+}
+
+// WriteHAR writes the recorded exchanges to w as a HAR 1.2 file, ordered by
+// start time. version is reported as the creator's version.
+func (t *Tracer) WriteHAR(w io.Writer, version string) error {
+	t.mu.Lock()
+	entries := append([]harEntry{}, t.entries...)
+	t.mu.Unlock()
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedDateTime.Before(entries[j].StartedDateTime)
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "fastmail-cli", Version: version},
+		Entries: entries,
+	}})
+}
+
+func harHeaders(h http.Header) []harNameValue {
+	out := []harNameValue{}
+	for name, values := range RedactHeaders(h) {
+		for _, value := range values {
+			out = append(out, harNameValue{Name: name, Value: value})
+		}
+	}
+	sortNameValues(out)
+	return out
+}
+
+func sortNameValues(nv []harNameValue) {
+	sort.SliceStable(nv, func(i, j int) bool { return nv[i].Name < nv[j].Name })
+}
+
+// isTextual reports whether a body of contentType is stored as text rather
+// than base64.
+func isTextual(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType == ""
+	}
+	return strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "xml") ||
+		mediaType == "application/x-www-form-urlencoded"
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
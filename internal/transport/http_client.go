@@ -0,0 +1,147 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options configures the HTTP transport every API client is built on.
+type Options struct {
+	// Proxy is the proxy URL for all requests. If empty, HTTPS_PROXY,
+	// HTTP_PROXY and NO_PROXY from the environment apply.
+	Proxy string
+	// CAFile is a PEM bundle of CA certificates to trust in addition to the
+	// system roots, e.g. for a TLS-inspecting corporate proxy.
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate and key presented to
+	// servers that ask for one. Both or neither must be set.
+	CertFile string
+	KeyFile  string
+	// Tracer, if set, traces every request sent over NewRoundTripper and
+	// NewHTTPClient, and DoWithRetry logs its retry decisions to it.
+	Tracer *Tracer
+}
+
+var (
+	configMu  sync.RWMutex
+	proxyFunc = http.ProxyFromEnvironment
+	tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	tracer    *Tracer
+)
+
+// Configure validates opts and makes NewTransport and NewHTTPClient use them
+// from then on. Call it once at startup, before any client is created.
+func Configure(opts Options) error {
+	proxy := http.ProxyFromEnvironment
+	if strings.TrimSpace(opts.Proxy) != "" {
+		proxyURL, err := ParseProxyURL(opts.Proxy)
+		if err != nil {
+			return err
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if opts.CAFile != "" {
+		pool, err := loadCAFile(opts.CAFile)
+		if err != nil {
+			return err
+		}
+		cfg.RootCAs = pool
+	}
+	if (opts.CertFile == "") != (opts.KeyFile == "") {
+		return fmt.Errorf("a client certificate needs both a certificate file and a key file")
+	}
+	if opts.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+	proxyFunc = proxy
+	tlsConfig = cfg
+	tracer = opts.Tracer
+	return nil
+}
+
+// ParseProxyURL parses a proxy given as a URL or host:port. A missing scheme
+// means http; https, socks5 and socks5h are also accepted.
+func ParseProxyURL(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", raw, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https, socks5 or socks5h", raw)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", raw)
+	}
+	return u, nil
+}
+
+func loadCAFile(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path) //nolint:gosec // path is user-configured
+	if err != nil {
+		return nil, fmt.Errorf("read CA file: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CA file %s contains no PEM certificates", path)
+	}
+	return pool, nil
+}
+
+// NewTransport returns an HTTP transport with the configured proxy, CA
+// bundle and client certificate, requiring TLS 1.2 or later.
+func NewTransport() *http.Transport {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = proxyFunc
+	t.TLSClientConfig = tlsConfig.Clone()
+	return t
+}
+
+// NewRoundTripper returns NewTransport, wrapped by the configured Tracer if
+// there is one.
+func NewRoundTripper() http.RoundTripper {
+	rt := NewTransport()
+	if t := currentTracer(); t != nil {
+		return t.Wrap(rt)
+	}
+	return rt
+}
+
+// NewHTTPClient returns an HTTP client using NewRoundTripper. A zero timeout
+// means none.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: NewRoundTripper()}
+}
+
+func currentTracer() *Tracer {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return tracer
+}
@@ -0,0 +1,171 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resetTransportConfig restores the default transport options after a test.
+func resetTransportConfig(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		if err := Configure(Options{}); err != nil {
+			t.Errorf("reset transport config: %v", err)
+		}
+	})
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestParseProxyURL(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "proxy.corp:3128", want: "http://proxy.corp:3128"},
+		{in: "https://proxy.corp", want: "https://proxy.corp"},
+		{in: "socks5://127.0.0.1:1080", want: "socks5://127.0.0.1:1080"},
+		{in: "ftp://proxy.corp", wantErr: true},
+		{in: "http://", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseProxyURL(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseProxyURL(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("ParseProxyURL(%q) = %v, %v; want %s", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestConfigure_Proxy(t *testing.T) {
+	resetTransportConfig(t)
+
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	if err := Configure(Options{Proxy: proxy.URL}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	resp, err := NewHTTPClient(5 * time.Second).Get("http://api.example.invalid/jmap/session")
+	if err != nil {
+		t.Fatalf("GET through proxy: %v", err)
+	}
+	_ = resp.Body.Close()
+	if proxied != "http://api.example.invalid/jmap/session" {
+		t.Errorf("proxy saw %q", proxied)
+	}
+}
+
+func TestConfigure_CAFile(t *testing.T) {
+	resetTransportConfig(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if _, err := NewHTTPClient(5 * time.Second).Get(server.URL); err == nil {
+		t.Fatal("expected the test server's certificate to be untrusted by default")
+	}
+
+	caFile := writePEM(t, t.TempDir(), "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+	if err := Configure(Options{CAFile: caFile}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	resp, err := NewHTTPClient(5 * time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET with CA file: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Configure(Options{CAFile: empty}); err == nil || !strings.Contains(err.Error(), "no PEM certificates") {
+		t.Errorf("Configure with an empty CA file: err = %v", err)
+	}
+}
+
+func TestConfigure_ClientCert(t *testing.T) {
+	resetTransportConfig(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "fastmail-cli test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile := writePEM(t, dir, "client.pem", "CERTIFICATE", certDER)
+	keyFile := writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", keyDER)
+
+	var presented int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presented = len(r.TLS.PeerCertificates)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := writePEM(t, dir, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+	if err := Configure(Options{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	resp, err := NewHTTPClient(5 * time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET with client certificate: %v", err)
+	}
+	_ = resp.Body.Close()
+	if presented != 1 {
+		t.Errorf("server saw %d client certificates, want 1", presented)
+	}
+
+	if err := Configure(Options{CertFile: certFile}); err == nil {
+		t.Error("Configure with a certificate but no key should fail")
+	}
+}
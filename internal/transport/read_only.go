@@ -0,0 +1,35 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ReadOnlyError is returned when a client in read-only mode is asked to
+// change data. The request is never sent.
+type ReadOnlyError struct {
+	Operation string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("read-only mode: refusing to %s", e.Operation)
+}
+
+// IsReadOnly checks whether err was caused by read-only mode.
+func IsReadOnly(err error) bool {
+	var ro *ReadOnlyError
+	return errors.As(err, &ro)
+}
+
+// IsWriteMethod reports whether an HTTP, WebDAV or CalDAV method can change
+// data on the server.
+func IsWriteMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, "PROPFIND", "REPORT":
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,32 @@
+package transport
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestIsWriteMethod(t *testing.T) {
+	for _, method := range []string{"GET", "head", "OPTIONS", "PROPFIND", "REPORT"} {
+		if IsWriteMethod(method) {
+			t.Errorf("IsWriteMethod(%s) = true, want false", method)
+		}
+	}
+	for _, method := range []string{"PUT", "DELETE", "MOVE", "MKCOL", "COPY", "POST", "PROPPATCH"} {
+		if !IsWriteMethod(method) {
+			t.Errorf("IsWriteMethod(%s) = false, want true", method)
+		}
+	}
+}
+
+func TestIsReadOnly(t *testing.T) {
+	err := fmt.Errorf("uploading: %w", &ReadOnlyError{Operation: "PUT /a.txt"})
+	if !IsReadOnly(err) {
+		t.Error("IsReadOnly should see through wrapping")
+	}
+	if err.Error() != "uploading: read-only mode: refusing to PUT /a.txt" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if IsReadOnly(fmt.Errorf("other")) {
+		t.Error("IsReadOnly(other) = true")
+	}
+}
@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// RecordedRequest is a write request a Recorder captured instead of sending.
+// Credentials (Authorization, Cookie) are not recorded.
+type RecordedRequest struct {
+	Method      string
+	URL         string
+	ContentType string
+	Body        []byte
+}
+
+// Recorder is an http.RoundTripper for dry runs. Requests IsWrite reports as
+// reads go to Next; writes are recorded and answered by Respond without
+// reaching the server.
+type Recorder struct {
+	Next    http.RoundTripper
+	IsWrite func(req *http.Request, body []byte) bool
+	Respond func(req *http.Request, body []byte) (*http.Response, error)
+
+	mu       sync.Mutex
+	requests []RecordedRequest
+}
+
+// NewRecorder returns a Recorder that sends reads over NewRoundTripper.
+func NewRecorder(isWrite func(*http.Request, []byte) bool, respond func(*http.Request, []byte) (*http.Response, error)) *Recorder {
+	return &Recorder{
+		Next:    NewRoundTripper(),
+		IsWrite: isWrite,
+		Respond: respond,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if !r.IsWrite(req, body) {
+		return r.Next.RoundTrip(req)
+	}
+
+	r.mu.Lock()
+	r.requests = append(r.requests, RecordedRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		ContentType: req.Header.Get("Content-Type"),
+		Body:        body,
+	})
+	r.mu.Unlock()
+
+	return r.Respond(req, body)
+}
+
+// Requests returns the recorded write requests in the order they were made.
+func (r *Recorder) Requests() []RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedRequest(nil), r.requests...)
+}
+
+// NewResponse builds a synthetic response to req.
+func NewResponse(req *http.Request, status int, contentType string, body []byte) *http.Response {
+	header := make(http.Header)
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,61 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Method)
+		_, _ = w.Write([]byte("listing"))
+	}))
+	defer server.Close()
+
+	recorder := NewRecorder(
+		func(req *http.Request, _ []byte) bool { return IsWriteMethod(req.Method) },
+		func(req *http.Request, _ []byte) (*http.Response, error) {
+			return NewResponse(req, http.StatusCreated, "", nil), nil
+		},
+	)
+	client := &http.Client{Transport: recorder}
+
+	req, _ := http.NewRequest("PROPFIND", server.URL+"/", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("PROPFIND: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "listing" {
+		t.Errorf("read body = %q, want the server's response", body)
+	}
+
+	req, _ = http.NewRequest(http.MethodPut, server.URL+"/a.txt", strings.NewReader("content"))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Content-Type", "text/plain")
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("PUT: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("PUT status = %d, want synthetic 201", resp.StatusCode)
+	}
+
+	if len(sent) != 1 || sent[0] != "PROPFIND" {
+		t.Errorf("server saw %v, want only the PROPFIND", sent)
+	}
+	recorded := recorder.Requests()
+	if len(recorded) != 1 {
+		t.Fatalf("recorded %d requests, want 1", len(recorded))
+	}
+	got := recorded[0]
+	if got.Method != http.MethodPut || got.URL != server.URL+"/a.txt" || got.ContentType != "text/plain" || string(got.Body) != "content" {
+		t.Errorf("recorded %+v", got)
+	}
+}
@@ -0,0 +1,169 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces credentials in traces and HAR files.
+const Redacted = "REDACTED"
+
+// sensitiveNames are header, query parameter, form field and JSON member
+// names whose values are never traced, compared by normalizedName.
+var sensitiveNames = map[string]bool{
+	"authorization":      true,
+	"proxyauthorization": true,
+	"cookie":             true,
+	"setcookie":          true,
+	"password":           true,
+	"passwd":             true,
+	"secret":             true,
+	"clientsecret":       true,
+	"token":              true,
+	"accesstoken":        true,
+	"refreshtoken":       true,
+	"idtoken":            true,
+	"codeverifier":       true,
+	"key":                true,
+	"apikey":             true,
+	"xapikey":            true,
+	"adminkey":           true,
+	"trackingkey":        true,
+}
+
+// normalizedName lowercases name and drops separators, so "X-Api-Key",
+// "api_key" and "apiKey" compare equal.
+func normalizedName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// IsSensitiveName reports whether values named name are redacted.
+func IsSensitiveName(name string) bool {
+	return sensitiveNames[normalizedName(name)]
+}
+
+// RedactHeaders returns a copy of h with credential headers redacted.
+func RedactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for name := range out {
+		if IsSensitiveName(name) {
+			out[name] = []string{Redacted}
+		}
+	}
+	return out
+}
+
+// RedactURL returns u as a string with any userinfo password and sensitive
+// query parameters redacted.
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	c := *u
+	if c.RawQuery != "" {
+		q := c.Query()
+		changed := false
+		for name := range q {
+			if IsSensitiveName(name) {
+				q[name] = []string{Redacted}
+				changed = true
+			}
+		}
+		if changed {
+			c.RawQuery = q.Encode()
+		}
+	}
+	return c.Redacted()
+}
+
+// RedactBody redacts sensitive members of a JSON body or fields of a form
+// body. Other bodies, and bodies with nothing to redact, are returned as is.
+func RedactBody(contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case !structuredBody(contentType):
+		return body
+	case mediaType == "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return body
+		}
+		changed := false
+		for name := range form {
+			if IsSensitiveName(name) {
+				form[name] = []string{Redacted}
+				changed = true
+			}
+		}
+		if !changed {
+			return body
+		}
+		return []byte(form.Encode())
+	default:
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return body
+		}
+		if !redactJSON(v) {
+			return body
+		}
+		out, err := json.Marshal(v)
+		if err != nil {
+			return body
+		}
+		return out
+	}
+}
+
+// structuredBody reports whether RedactBody looks inside bodies of
+// contentType: JSON, forms, and untyped bodies that turn out to be JSON.
+// Tracers keep such bodies whole, as a truncated one cannot be parsed.
+func structuredBody(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "" ||
+		mediaType == "application/json" ||
+		strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/x-www-form-urlencoded"
+}
+
+// redactJSON replaces sensitive members of v in place and reports whether
+// it changed anything.
+func redactJSON(v any) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if IsSensitiveName(k) {
+				if s, ok := child.(string); !ok || s != "" {
+					v[k] = Redacted
+					changed = true
+				}
+				continue
+			}
+			if redactJSON(child) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if redactJSON(child) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"strconv"
 	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/logging"
 )
 
 const (
@@ -80,6 +82,7 @@ func DoWithRetry(ctx context.Context, client *http.Client, cfg RetryConfig, reqF
 
 		if attempt < cfg.MaxRetries {
 			delay := RetryDelay(cfg, attempt, retryResp)
+			logRetry(ctx, "retrying request", req, attempt, cfg.MaxRetries, retryResp, lastErr, "delay", delay)
 			select {
 			case <-time.After(delay):
 				continue
@@ -92,9 +95,29 @@ func DoWithRetry(ctx context.Context, client *http.Client, cfg RetryConfig, reqF
 	if lastErr == nil {
 		lastErr = fmt.Errorf("request failed")
 	}
+	logRetry(ctx, "giving up after retries", nil, cfg.MaxRetries, cfg.MaxRetries, retryResp, lastErr)
 	return nil, fmt.Errorf("request failed after %d retries: %w", cfg.MaxRetries, lastErr)
 }
 
+// logRetry logs a retry decision at debug level to the configured Tracer's
+// logger, or to the context logger when not tracing. attempt is 0-based.
+func logRetry(ctx context.Context, msg string, req *http.Request, attempt, maxRetries int, resp *http.Response, err error, extra ...any) {
+	logger := logging.FromContext(ctx)
+	if t := currentTracer(); t != nil && t.logger != nil {
+		logger = t.logger
+	}
+	args := []any{"attempt", attempt + 1, "maxAttempts", maxRetries + 1}
+	if req != nil {
+		args = append(args, "method", req.Method, "url", RedactURL(req.URL))
+	}
+	if resp != nil {
+		args = append(args, "status", resp.StatusCode)
+	} else if err != nil {
+		args = append(args, "error", err)
+	}
+	logger.Debug(msg, append(args, extra...)...)
+}
+
 // RetryDelay calculates the delay before the next retry attempt.
 func RetryDelay(cfg RetryConfig, attempt int, resp *http.Response) time.Duration {
 	cfg = normalizeRetryConfig(cfg)
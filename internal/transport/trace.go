@@ -0,0 +1,150 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// maxTraceBody is how much of each request and response body a Tracer
+	// keeps for the HAR file, unless it needs the whole body for redaction.
+	maxTraceBody = 1 << 20
+	// maxTraceAttr caps the JSON logged for a single JMAP call or response.
+	maxTraceAttr = 4096
+)
+
+// Tracer logs HTTP traffic and JMAP method calls with credentials redacted,
+// and optionally records it for a HAR file. Install it with Options.Tracer.
+type Tracer struct {
+	logger    *slog.Logger
+	recordHAR bool
+
+	mu      sync.Mutex
+	entries []harEntry
+}
+
+// NewTracer returns a Tracer that logs to logger at debug level (nil
+// disables logging) and, if recordHAR is set, keeps entries for WriteHAR.
+func NewTracer(logger *slog.Logger, recordHAR bool) *Tracer {
+	return &Tracer{logger: logger, recordHAR: recordHAR}
+}
+
+// Wrap returns a RoundTripper that traces requests sent through next.
+func (t *Tracer) Wrap(next http.RoundTripper) http.RoundTripper {
+	return &tracingTransport{next: next, tracer: t}
+}
+
+func (t *Tracer) debug(msg string, args ...any) {
+	if t.logger != nil {
+		t.logger.Debug(msg, args...)
+	}
+}
+
+type tracingTransport struct {
+	next   http.RoundTripper
+	tracer *Tracer
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t := rt.tracer
+	start := time.Now()
+
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	t.debug("http request", "method", req.Method, "url", RedactURL(req.URL), "contentType", req.Header.Get("Content-Type"), "size", len(reqBody))
+	t.logJMAP("jmap call", reqBody, "methodCalls")
+
+	resp, err := rt.next.RoundTrip(req)
+	wait := time.Since(start)
+	if err != nil {
+		t.debug("http error", "method", req.Method, "url", RedactURL(req.URL), "duration", wait, "error", err)
+		t.record(req, reqBody, nil, nil, 0, false, start, wait, 0)
+		return nil, err
+	}
+	t.debug("http response", "method", req.Method, "url", RedactURL(req.URL), "status", resp.StatusCode, "duration", wait)
+
+	body := &traceBody{ReadCloser: resp.Body, whole: structuredBody(resp.Header.Get("Content-Type"))}
+	body.done = func() {
+		t.logJMAP("jmap response", body.buf.Bytes(), "methodResponses")
+		t.record(req, reqBody, resp, body.buf.Bytes(), body.size, body.size > int64(body.buf.Len()), start, wait, time.Since(start)-wait)
+	}
+	resp.Body = body
+	return resp, nil
+}
+
+// traceBody keeps the first maxTraceBody bytes of a response body, or all
+// of it if whole is set, and reports it once the caller closes the body.
+type traceBody struct {
+	io.ReadCloser
+	whole bool
+	buf   bytes.Buffer
+	size  int64
+	done  func()
+	once  sync.Once
+}
+
+func (b *traceBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.size += int64(n)
+		if b.whole {
+			b.buf.Write(p[:n])
+		} else if room := maxTraceBody - b.buf.Len(); room > 0 {
+			b.buf.Write(p[:min(n, room)])
+		}
+	}
+	return n, err
+}
+
+func (b *traceBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.done)
+	return err
+}
+
+// logJMAP logs each invocation in a JMAP request (field "methodCalls") or
+// response (field "methodResponses") body. Other bodies are ignored.
+func (t *Tracer) logJMAP(msg string, body []byte, field string) {
+	if t.logger == nil || len(body) == 0 || body[0] != '{' {
+		return
+	}
+	var envelope map[string]json.RawMessage
+	if json.Unmarshal(body, &envelope) != nil {
+		return
+	}
+	var invocations [][]json.RawMessage
+	if json.Unmarshal(envelope[field], &invocations) != nil {
+		return
+	}
+	for _, inv := range invocations {
+		if len(inv) != 3 {
+			continue
+		}
+		var name, callID string
+		_ = json.Unmarshal(inv[0], &name)
+		_ = json.Unmarshal(inv[2], &callID)
+		args := RedactBody("application/json", inv[1])
+		text := string(args)
+		if len(args) > maxTraceAttr {
+			text = fmt.Sprintf("%s... (%d bytes)", args[:maxTraceAttr], len(args))
+		}
+		t.logger.Debug(msg, "method", name, "callId", callID, "args", text)
+	}
+}
@@ -3,13 +3,14 @@ package update
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"net/http"
 	"strings"
 	"time"
 
 	"golang.org/x/mod/semver"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
 )
 
 // GitHubReleasesURL is the API endpoint for releases (var for testing).
@@ -51,14 +52,7 @@ func CheckForUpdate(ctx context.Context, currentVersion string) *CheckResult {
 	}
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				MinVersion: tls.VersionTLS12,
-			},
-		},
-	}
-	resp, err := client.Do(req)
+	resp, err := transport.NewHTTPClient(0).Do(req)
 	if err != nil {
 		return nil
 	}
@@ -3,7 +3,6 @@ package webdav
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -27,6 +26,7 @@ type Client struct {
 	httpClient *http.Client
 	token      string
 	retry      transport.RetryConfig
+	readOnly   bool
 }
 
 // FileInfo represents information about a file or directory
@@ -71,23 +71,11 @@ type resourceType struct {
 	Collection *struct{} `xml:"collection"`
 }
 
-// newSecureHTTPClient creates an HTTP client with secure TLS configuration.
-func newSecureHTTPClient() *http.Client {
-	return &http.Client{
-		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				MinVersion: tls.VersionTLS12,
-			},
-		},
-	}
-}
-
 // NewClient creates a new WebDAV client with the provided API token
 func NewClient(token string) *Client {
 	return &Client{
 		baseURL:    DefaultBaseURL,
-		httpClient: newSecureHTTPClient(),
+		httpClient: transport.NewHTTPClient(30 * time.Second),
 		token:      token,
 		retry:      transport.DefaultRetryConfig(),
 	}
@@ -97,7 +85,7 @@ func NewClient(token string) *Client {
 func NewClientWithBaseURL(token, baseURL string) *Client {
 	return &Client{
 		baseURL:    baseURL,
-		httpClient: newSecureHTTPClient(),
+		httpClient: transport.NewHTTPClient(30 * time.Second),
 		token:      token,
 		retry:      transport.DefaultRetryConfig(),
 	}
@@ -108,6 +96,25 @@ func (c *Client) SetRetryConfig(cfg transport.RetryConfig) {
 	c.retry = cfg
 }
 
+// SetHTTPClient sets a custom HTTP client for the WebDAV client
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+// SetReadOnly makes the client reject Upload, Mkdir, Delete and Move (PUT,
+// MKCOL, DELETE and MOVE) with a *transport.ReadOnlyError.
+func (c *Client) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
+
+// checkWrite returns an error if the client is read-only.
+func (c *Client) checkWrite(method, remotePath string) error {
+	if c.readOnly {
+		return &transport.ReadOnlyError{Operation: method + " " + remotePath}
+	}
+	return nil
+}
+
 // validateRemotePath validates and cleans a remote path to prevent traversal attacks.
 // It ensures the path starts with / and doesn't contain parent directory references.
 func validateRemotePath(remotePath string) (string, error) {
@@ -229,6 +236,34 @@ func (c *Client) List(ctx context.Context, filePath string) ([]FileInfo, error)
 	return files, nil
 }
 
+// Ping checks that file storage is reachable and the token is accepted by
+// requesting the properties of the root directory.
+func (c *Client) Ping(ctx context.Context) error {
+	reqFn := func(ctx context.Context) (*http.Request, error) {
+		req, reqErr := http.NewRequestWithContext(ctx, "PROPFIND", c.baseURL+"/", nil)
+		if reqErr != nil {
+			return nil, fmt.Errorf("creating request: %w", reqErr)
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Depth", "0")
+		return req, nil
+	}
+
+	resp, err := transport.DoWithRetry(ctx, c.httpClient, c.retry, reqFn, func(_ int, resp *http.Response) (bool, error) {
+		return transport.IsRetriableStatus(resp.StatusCode), nil
+	})
+	if err != nil {
+		return fmt.Errorf("executing PROPFIND: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusMultiStatus && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body) //nolint:errcheck // best-effort read for error message
+		return transport.NewHTTPError("PROPFIND", resp, body)
+	}
+	return nil
+}
+
 // Upload uploads a local file to the remote path
 func (c *Client) Upload(ctx context.Context, localPath, remotePath string) error {
 	// Validate remote path
@@ -237,6 +272,9 @@ func (c *Client) Upload(ctx context.Context, localPath, remotePath string) error
 		return err
 	}
 	remotePath = validPath
+	if err := c.checkWrite("PUT", remotePath); err != nil {
+		return err
+	}
 
 	// Open local file
 	file, err := os.Open(localPath)
@@ -352,6 +390,9 @@ func (c *Client) Mkdir(ctx context.Context, dirPath string) error {
 		return err
 	}
 	dirPath = validPath
+	if err := c.checkWrite("MKCOL", dirPath); err != nil {
+		return err
+	}
 
 	// Ensure trailing slash for directory
 	if !strings.HasSuffix(dirPath, "/") {
@@ -404,6 +445,9 @@ func (c *Client) Delete(ctx context.Context, filePath string) error {
 		return err
 	}
 	filePath = validPath
+	if err := c.checkWrite("DELETE", filePath); err != nil {
+		return err
+	}
 
 	url := c.baseURL + filePath
 
@@ -457,6 +501,9 @@ func (c *Client) Move(ctx context.Context, source, destination string) error {
 		return fmt.Errorf("invalid destination path: %w", err)
 	}
 	destination = validDest
+	if err := c.checkWrite("MOVE", source); err != nil {
+		return err
+	}
 
 	sourceURL := c.baseURL + source
 	destinationURL := c.baseURL + destination
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/salmonumbrella/fastmail-cli/internal/transport"
 )
 
 func TestNewClient(t *testing.T) {
@@ -139,6 +141,39 @@ func TestUpload(t *testing.T) {
 	}
 }
 
+func TestReadOnly(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	localFile := filepath.Join(t.TempDir(), "upload.txt")
+	if err := os.WriteFile(localFile, []byte("x"), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	client := NewClientWithBaseURL("test-token", server.URL)
+	client.SetReadOnly(true)
+	ctx := context.Background()
+
+	errs := map[string]error{
+		"Upload": client.Upload(ctx, localFile, "/upload.txt"),
+		"Mkdir":  client.Mkdir(ctx, "/dir"),
+		"Delete": client.Delete(ctx, "/file.txt"),
+		"Move":   client.Move(ctx, "/a.txt", "/b.txt"),
+	}
+	for op, err := range errs {
+		if !transport.IsReadOnly(err) {
+			t.Errorf("%s error = %v, want read-only error", op, err)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("%d requests sent, want none", requests)
+	}
+}
+
 func TestDownload(t *testing.T) {
 	content := []byte("test download content")
 
@@ -218,6 +253,30 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestPing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PROPFIND" {
+			t.Errorf("expected PROPFIND, got %s", r.Method)
+		}
+		if r.Header.Get("Depth") != "0" {
+			t.Errorf("expected Depth 0, got %s", r.Header.Get("Depth"))
+		}
+		if r.Header.Get("Authorization") != "Bearer good-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+	}))
+	defer server.Close()
+
+	if err := NewClientWithBaseURL("good-token", server.URL).Ping(context.Background()); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if err := NewClientWithBaseURL("bad-token", server.URL).Ping(context.Background()); err == nil {
+		t.Fatal("Ping with a rejected token should fail")
+	}
+}
+
 func TestMove(t *testing.T) {
 	// Create a test server that handles MOVE
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {